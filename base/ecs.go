@@ -18,6 +18,17 @@ type ISystem interface {
 	Deactivate(IGameObject2D)
 }
 
+// IPreRenderSystem is a graphics system preparing for a frame before any camera draws, such as moving cameras.
+type IPreRenderSystem interface {
+	PreRender()
+}
+
+// ICameraRenderSystem is a graphics system preparing for each camera drawing the frame, after the viewport of the camera is set,
+// such as redirecting drawings into an off-screen target.
+type ICameraRenderSystem interface {
+	PreRenderCamera()
+}
+
 type SystemBase struct {
 	priority  int
 	isEnabled bool
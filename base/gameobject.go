@@ -13,10 +13,12 @@ type DefaultComponentWrapper struct {
 }
 
 type GameObjectFunctions struct {
-	OnCreate  func()
-	OnStep    func(self IGameObject2D)
-	OnRender  func(self IGameObject2D)
-	OnDestroy func(self IGameObject2D)
+	OnCreate            func()
+	OnStep              func(self IGameObject2D)
+	OnRender            func(self IGameObject2D) // called once for each camera drawing the frame.
+	OnDestroy           func(self IGameObject2D)
+	OnAnimationEvent    func(self IGameObject2D, event string) // called when a frame with an event is shown.
+	OnAnimationFinished func(self IGameObject2D, state string) // called when a once or clamp-forever animation reaches its end.
 }
 
 type IGameObject2D interface {
@@ -77,6 +79,18 @@ func (o *GameObject2D) RegisterDestroy(method func(IGameObject2D)) *GameObject2D
 	return o
 }
 
+// RegisterAnimationEvent registers a callback receiving frame events of sprite renderers, which is called by Animation2DSystem.
+func (o *GameObject2D) RegisterAnimationEvent(method func(IGameObject2D, string)) *GameObject2D {
+	o.Callbacks.OnAnimationEvent = method
+	return o
+}
+
+// RegisterAnimationFinished registers a callback receiving states whose animation finished, which is called by Animation2DSystem.
+func (o *GameObject2D) RegisterAnimationFinished(method func(IGameObject2D, string)) *GameObject2D {
+	o.Callbacks.OnAnimationFinished = method
+	return o
+}
+
 func (o *GameObject2D) RegisterComponent(com IComponent) *GameObject2D {
 	o.components[com.GetName()] = com
 	return o
@@ -121,6 +135,12 @@ func (o *GameObject2D) GetComponent(name string) IComponent {
 	return ret
 }
 
+// TryGetComponent returns the component with given name, ok is false if absent.
+func (o *GameObject2D) TryGetComponent(name string) (IComponent, bool) {
+	ret, ok := o.components[name]
+	return ret, ok
+}
+
 func (o *GameObject2D) GetAllComponents() map[string]IComponent {
 	return o.components
 }
@@ -0,0 +1,82 @@
+// gxatlas packs pngs under given directories into atlas pages offline.
+// It writes <name>_<index>.png pages and a <name>.json description, which could be declared in a level file by
+//
+//	<frame-metas>
+//		<atlas file="<name>.json" prefix="frm_"/>
+//	</frame-metas>
+//
+// Frames are named by their file names without extension.
+// Usage: gxatlas [-out dir] [-name atlas] [-size 2048] [-padding 2] [-extrude 1] dir...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"galaxyzeta.io/engine/graphics"
+)
+
+func main() {
+	out := flag.String("out", ".", "output directory")
+	name := flag.String("name", "atlas", "name of the atlas files")
+	size := flag.Int("size", graphics.DefaultAtlasPageSize, "max width and height of a page")
+	padding := flag.Int("padding", graphics.DefaultAtlasPadding, "transparent pixels between frames")
+	extrude := flag.Int("extrude", graphics.DefaultAtlasExtrude, "pixels copied from edges around frames")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	names, images, err := readFrames(flag.Args())
+	if err != nil {
+		fail(err)
+	}
+	packer := graphics.AtlasPacker{PageSize: *size, Padding: *padding, Extrude: *extrude}
+	pages, err := packer.Pack(names, images)
+	if err != nil {
+		fail(err)
+	}
+	if err := graphics.WriteAtlas(*out, *name, pages); err != nil {
+		fail(err)
+	}
+	fmt.Printf("packed %d frames into %d pages\n", len(names), len(pages))
+}
+
+// readFrames reads pngs under directories, frames are named by file names without extension and must be unique.
+func readFrames(dirs []string) (names []string, images []image.Image, err error) {
+	seen := map[string]string{}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || graphics.GetImgFormat(entry.Name()) != graphics.PNG {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			frameName := strings.Split(entry.Name(), ".")[0]
+			if prev, ok := seen[frameName]; ok {
+				return nil, nil, fmt.Errorf("frame %s is declared by both %s and %s", frameName, prev, path)
+			}
+			seen[frameName] = path
+			img, err := graphics.ReadPng(path)
+			if err != nil {
+				return nil, nil, err
+			}
+			names = append(names, frameName)
+			images = append(images, img)
+		}
+	}
+	return names, images, nil
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
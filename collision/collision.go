@@ -46,58 +46,58 @@ func (cb CollisionBuilder) Fetch() {
 
 }
 
-func colliderAtWithFilter(sys ICollisionSystem, pos linalg.Vector2f64, fx func(col *component.PolygonCollider) bool, mode QueryMode) *component.PolygonCollider {
+func colliderAtWithFilter(sys ICollisionSystem, pos linalg.Vector2f64, fx func(col *component.Collider) bool, mode QueryMode) *component.Collider {
 	cols := sys.QueryNeighborCollidersWithPosition(pos, mode)
 	for _, col := range cols {
 		// use a tiny rectangle to test intersect
-		if physics.NewRectangle(pos.X-pointEpsHalf, pos.Y-pointEpsHalf, pointEps, pointEps).ToPolygon().Intersect(col.Collider) && fx(col) {
+		if physics.NewRectangle(pos.X-pointEpsHalf, pos.Y-pointEpsHalf, pointEps, pointEps).Intersect(col.Shape) && (fx == nil || fx(col)) {
 			return col
 		}
 	}
 	return nil
 }
 
-func collidersAtWithFilter(sys ICollisionSystem, pos linalg.Vector2f64, fx func(col *component.PolygonCollider) bool, mode QueryMode) []*component.PolygonCollider {
+func collidersAtWithFilter(sys ICollisionSystem, pos linalg.Vector2f64, fx func(col *component.Collider) bool, mode QueryMode) []*component.Collider {
 	cols := sys.QueryNeighborCollidersWithPosition(pos, mode)
-	ret := make([]*component.PolygonCollider, 0)
+	ret := make([]*component.Collider, 0)
 	for _, col := range cols {
 		// use a tiny rectangle to test intersect
-		if physics.NewRectangle(pos.X-pointEpsHalf, pos.Y-pointEpsHalf, pointEps, pointEps).ToPolygon().Intersect(col.Collider) && fx(col) {
+		if physics.NewRectangle(pos.X-pointEpsHalf, pos.Y-pointEpsHalf, pointEps, pointEps).Intersect(col.Shape) && (fx == nil || fx(col)) {
 			ret = append(ret, col)
 		}
 	}
 	return ret
 }
 
-func ColliderAt(sys ICollisionSystem, pos linalg.Vector2f64, mode QueryMode) *component.PolygonCollider {
+func ColliderAt(sys ICollisionSystem, pos linalg.Vector2f64, mode QueryMode) *component.Collider {
 	return colliderAtWithFilter(sys, pos, nil, mode)
 }
 
-func CollidersAt(sys ICollisionSystem, pos linalg.Vector2f64, mode QueryMode) []*component.PolygonCollider {
+func CollidersAt(sys ICollisionSystem, pos linalg.Vector2f64, mode QueryMode) []*component.Collider {
 	return collidersAtWithFilter(sys, pos, nil, mode)
 }
 
-func ColliderAtWithName(sys ICollisionSystem, pos linalg.Vector2f64, name string, mode QueryMode) *component.PolygonCollider {
-	return colliderAtWithFilter(sys, pos, func(col *component.PolygonCollider) bool {
+func ColliderAtWithName(sys ICollisionSystem, pos linalg.Vector2f64, name string, mode QueryMode) *component.Collider {
+	return colliderAtWithFilter(sys, pos, func(col *component.Collider) bool {
 		return col.I().Obj().Name == name
 	}, mode)
 }
 
-func CollidersAtWithName(sys ICollisionSystem, pos linalg.Vector2f64, name string, mode QueryMode) []*component.PolygonCollider {
-	return collidersAtWithFilter(sys, pos, func(col *component.PolygonCollider) bool {
+func CollidersAtWithName(sys ICollisionSystem, pos linalg.Vector2f64, name string, mode QueryMode) []*component.Collider {
+	return collidersAtWithFilter(sys, pos, func(col *component.Collider) bool {
 		return col.I().Obj().Name == name
 	}, mode)
 }
 
-func ColliderAtWithTag(sys ICollisionSystem, pos linalg.Vector2f64, name string, mode QueryMode) *component.PolygonCollider {
-	return colliderAtWithFilter(sys, pos, func(col *component.PolygonCollider) bool {
+func ColliderAtWithTag(sys ICollisionSystem, pos linalg.Vector2f64, name string, mode QueryMode) *component.Collider {
+	return colliderAtWithFilter(sys, pos, func(col *component.Collider) bool {
 		_, ok := col.I().Obj().Tags[name]
 		return ok
 	}, mode)
 }
 
-func CollidersAtWithTag(sys ICollisionSystem, pos linalg.Vector2f64, tag string, mode QueryMode) []*component.PolygonCollider {
-	return collidersAtWithFilter(sys, pos, func(col *component.PolygonCollider) bool {
+func CollidersAtWithTag(sys ICollisionSystem, pos linalg.Vector2f64, tag string, mode QueryMode) []*component.Collider {
+	return collidersAtWithFilter(sys, pos, func(col *component.Collider) bool {
 		_, ok := col.I().Obj().Tags[tag]
 		return ok
 	}, mode)
@@ -166,94 +166,94 @@ func HasObjectAtWithTags(sys ICollisionSystem, pos linalg.Vector2f64, tags []str
 	return false
 }
 
-// === polygon ===
+// === shape ===
 
-func checkPolygonCollision(pc component.PolygonCollider, pool []*component.PolygonCollider) bool {
-	return checkPolygonCollisionWithFilter(pc, pool, func(test *component.PolygonCollider) bool { return true })
+func checkPolygonCollision(pc component.Collider, pool []*component.Collider) bool {
+	return checkPolygonCollisionWithFilter(pc, pool, func(test *component.Collider) bool { return true })
 }
 
-func checkPolygonCollisionWithFilter(pc component.PolygonCollider, pool []*component.PolygonCollider, fx func(test *component.PolygonCollider) bool) bool {
+func checkPolygonCollisionWithFilter(pc component.Collider, pool []*component.Collider, fx func(test *component.Collider) bool) bool {
 	for _, test := range pool {
-		if pc.Collider.Intersect(test.Collider) && fx(test) {
+		if pc.Shape.Intersect(test.Shape) && fx(test) {
 			return true
 		}
 	}
 	return false
 }
 
-func collectPolygonCollisionWithFilter(pc component.PolygonCollider, pool []*component.PolygonCollider, fx func(testpc *component.PolygonCollider) bool) *component.PolygonCollider {
+func collectPolygonCollisionWithFilter(pc component.Collider, pool []*component.Collider, fx func(testpc *component.Collider) bool) *component.Collider {
 	for _, testpc := range pool {
-		if pc.Collider.Intersect(testpc.Collider) && fx(testpc) {
+		if pc.Shape.Intersect(testpc.Shape) && fx(testpc) {
 			return testpc
 		}
 	}
 	return nil
 }
 
-func collectPolygonCollisionsWithFilter(pc component.PolygonCollider, pool []*component.PolygonCollider, fx func(testpc *component.PolygonCollider) bool) (result []*component.PolygonCollider) {
+func collectPolygonCollisionsWithFilter(pc component.Collider, pool []*component.Collider, fx func(testpc *component.Collider) bool) (result []*component.Collider) {
 	for _, testpc := range pool {
-		if pc.Collider.Intersect(testpc.Collider) && fx(testpc) {
+		if pc.Shape.Intersect(testpc.Shape) && fx(testpc) {
 			result = append(result, testpc)
 		}
 	}
 	return result
 }
 
-func getPcwrapper(p physics.Polygon) component.PolygonCollider {
-	return component.PolygonCollider{
-		Collider: p,
+func getPcwrapper(p physics.IShape) component.Collider {
+	return component.Collider{
+		Shape: p,
 	}
 }
 
-func HasColliderAtPolygonWithAny(sys ICollisionSystem, p physics.Polygon, mode QueryMode) bool {
+func HasColliderAtPolygonWithAny(sys ICollisionSystem, p physics.IShape, mode QueryMode) bool {
 	pcWrapper := getPcwrapper(p)
 	return checkPolygonCollision(pcWrapper, sys.QueryNeighborCollidersWithCollider(pcWrapper, mode))
 }
 
-func HasColliderAtPolygonWithName(sys ICollisionSystem, p physics.Polygon, name string, mode QueryMode) bool {
+func HasColliderAtPolygonWithName(sys ICollisionSystem, p physics.IShape, name string, mode QueryMode) bool {
 	pcWrapper := getPcwrapper(p)
-	return checkPolygonCollisionWithFilter(pcWrapper, sys.QueryNeighborCollidersWithCollider(pcWrapper, mode), func(test *component.PolygonCollider) bool {
+	return checkPolygonCollisionWithFilter(pcWrapper, sys.QueryNeighborCollidersWithCollider(pcWrapper, mode), func(test *component.Collider) bool {
 		return test.I().Obj().Name == name
 	})
 }
 
-func HasColliderAtPolygonWithTag(sys ICollisionSystem, p physics.Polygon, tag string, mode QueryMode) bool {
+func HasColliderAtPolygonWithTag(sys ICollisionSystem, p physics.IShape, tag string, mode QueryMode) bool {
 	pcWrapper := getPcwrapper(p)
-	return checkPolygonCollisionWithFilter(pcWrapper, sys.QueryNeighborCollidersWithCollider(pcWrapper, mode), func(test *component.PolygonCollider) bool {
+	return checkPolygonCollisionWithFilter(pcWrapper, sys.QueryNeighborCollidersWithCollider(pcWrapper, mode), func(test *component.Collider) bool {
 		_, ok := test.I().Obj().Tags[tag]
 		return ok
 	})
 }
 
-func ColliderAtPolygonWithAny(sys ICollisionSystem, p physics.Polygon, mode QueryMode) *component.PolygonCollider {
+func ColliderAtPolygonWithAny(sys ICollisionSystem, p physics.IShape, mode QueryMode) *component.Collider {
 	pcWrapper := getPcwrapper(p)
-	return collectPolygonCollisionWithFilter(pcWrapper, sys.QueryNeighborCollidersWithCollider(pcWrapper, mode), func(testpc *component.PolygonCollider) bool {
+	return collectPolygonCollisionWithFilter(pcWrapper, sys.QueryNeighborCollidersWithCollider(pcWrapper, mode), func(testpc *component.Collider) bool {
 		return true
 	})
 }
 
-func CollidersAtPolygonWithAny(sys ICollisionSystem, p physics.Polygon, mode QueryMode) []*component.PolygonCollider {
+func CollidersAtPolygonWithAny(sys ICollisionSystem, p physics.IShape, mode QueryMode) []*component.Collider {
 	pcWrapper := getPcwrapper(p)
-	return collectPolygonCollisionsWithFilter(pcWrapper, sys.QueryNeighborCollidersWithCollider(pcWrapper, mode), func(testpc *component.PolygonCollider) bool {
+	return collectPolygonCollisionsWithFilter(pcWrapper, sys.QueryNeighborCollidersWithCollider(pcWrapper, mode), func(testpc *component.Collider) bool {
 		return true
 	})
 }
 
-func ColliderAtPolygonWithFilter(sys ICollisionSystem, p physics.Polygon, fx func(test *component.PolygonCollider) bool, mode QueryMode) *component.PolygonCollider {
+func ColliderAtPolygonWithFilter(sys ICollisionSystem, p physics.IShape, fx func(test *component.Collider) bool, mode QueryMode) *component.Collider {
 	pcWrapper := getPcwrapper(p)
 	return collectPolygonCollisionWithFilter(pcWrapper, sys.QueryNeighborCollidersWithCollider(pcWrapper, mode), fx)
 }
 
-func ColliderAtPolygonWithName(sys ICollisionSystem, p physics.Polygon, name string, mode QueryMode) *component.PolygonCollider {
+func ColliderAtPolygonWithName(sys ICollisionSystem, p physics.IShape, name string, mode QueryMode) *component.Collider {
 	pcWrapper := getPcwrapper(p)
-	return collectPolygonCollisionWithFilter(pcWrapper, sys.QueryNeighborCollidersWithCollider(pcWrapper, mode), func(testpc *component.PolygonCollider) bool {
+	return collectPolygonCollisionWithFilter(pcWrapper, sys.QueryNeighborCollidersWithCollider(pcWrapper, mode), func(testpc *component.Collider) bool {
 		return testpc.I().Obj().Name == name
 	})
 }
 
-func ColliderAtPolygonWithTag(sys ICollisionSystem, p physics.Polygon, tag string, mode QueryMode) *component.PolygonCollider {
+func ColliderAtPolygonWithTag(sys ICollisionSystem, p physics.IShape, tag string, mode QueryMode) *component.Collider {
 	pcWrapper := getPcwrapper(p)
-	return collectPolygonCollisionWithFilter(pcWrapper, sys.QueryNeighborCollidersWithCollider(pcWrapper, mode), func(testpc *component.PolygonCollider) bool {
+	return collectPolygonCollisionWithFilter(pcWrapper, sys.QueryNeighborCollidersWithCollider(pcWrapper, mode), func(testpc *component.Collider) bool {
 		_, ok := testpc.I().Obj().Tags[tag]
 		return ok
 	})
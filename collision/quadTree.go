@@ -1,9 +1,9 @@
 package collision
 
 import (
+	"math"
 	"sync/atomic"
 
-	"galaxyzeta.io/engine/base"
 	"galaxyzeta.io/engine/ecs/component"
 	"galaxyzeta.io/engine/infra/concurrency/lock"
 	"galaxyzeta.io/engine/infra/logger"
@@ -30,6 +30,9 @@ const (
 var idGenerator int64
 var qtlogger *logger.Logger = logger.New("QuadTree")
 
+// Update refreshes the whole tree once more than 1/dirtyRefreshRatio of colliders are dirty.
+const dirtyRefreshRatio = 2
+
 func init() {
 	// qtlogger.Disable()
 }
@@ -41,15 +44,18 @@ type QuadTree struct {
 	minDivision float64
 	looseOffset float64 // once a collider entered a cell, in how much offset to determine a collider has left the original cell.
 	mu          *lock.SpinLock
-	lookup      map[base.IGameObject2D]*QTreeNode
+	lookup      map[*component.Collider]*QTreeNode
+	dirtyMu     *lock.SpinLock
+	dirty       map[*component.Collider]struct{} // colliders that have moved since last update
+	dirtyBack   map[*component.Collider]struct{} // swapped with dirty while updating
 }
 
 type QTreeNode struct {
 	id            int64
-	items         []*component.PolygonCollider
-	inlineItems   []*component.PolygonCollider // inlineItems stores items that is actually on the boundary
-	inactiveItems []*component.PolygonCollider // inactiveItems stors deactivated items
-	children      []*QTreeNode                 // points to 4 sub dimensions
+	items         []*component.Collider
+	inlineItems   []*component.Collider // inlineItems stores items that is actually on the boundary
+	inactiveItems []*component.Collider // inactiveItems stors deactivated items
+	children      []*QTreeNode          // points to 4 sub dimensions
 	parent        *QTreeNode
 	area          physics.Rectangle
 	minDivision   float64
@@ -57,9 +63,9 @@ type QTreeNode struct {
 	quadTree      *QuadTree // always point to the root
 }
 
-type QTreeTraverseFunc func(*component.PolygonCollider, *QTreeNode, AreaType, int) bool
+type QTreeTraverseFunc func(*component.Collider, *QTreeNode, AreaType, int) bool
 
-type qtreeNodeCollectorFunc func() []*component.PolygonCollider
+type qtreeNodeCollectorFunc func() []*component.Collider
 
 type QueryMode int8
 
@@ -69,15 +75,15 @@ const (
 	All          QueryMode = 2
 )
 
-func (qt *QTreeNode) collectorFxCollectActive() []*component.PolygonCollider {
+func (qt *QTreeNode) collectorFxCollectActive() []*component.Collider {
 	return qt.GetActiveItems()
 }
 
-func (qt *QTreeNode) collectorFxCollectInactive() []*component.PolygonCollider {
+func (qt *QTreeNode) collectorFxCollectInactive() []*component.Collider {
 	return qt.GetInactiveItems()
 }
 
-func (qt *QTreeNode) collectorFxCollectAll() []*component.PolygonCollider {
+func (qt *QTreeNode) collectorFxCollectAll() []*component.Collider {
 	return qt.GetAllItems()
 }
 
@@ -104,14 +110,17 @@ func NewQuadTree(maintainanceArea physics.Rectangle, loadFactor int, minDivision
 		minDivision: minDivision,
 		looseOffset: 0,
 		mu:          &lock.SpinLock{},
-		lookup:      make(map[base.IGameObject2D]*QTreeNode),
+		lookup:      make(map[*component.Collider]*QTreeNode),
+		dirtyMu:     &lock.SpinLock{},
+		dirty:       make(map[*component.Collider]struct{}),
+		dirtyBack:   make(map[*component.Collider]struct{}),
 	}
 }
 
 func NewQTreeNode(parent *QTreeNode, section int) *QTreeNode {
 	return &QTreeNode{
 		id:          atomic.AddInt64(&idGenerator, 1),
-		items:       []*component.PolygonCollider{},
+		items:       []*component.Collider{},
 		parent:      parent,
 		area:        boundaryDivision(parent.area, section),
 		loadFactor:  parent.loadFactor,
@@ -120,18 +129,18 @@ func NewQTreeNode(parent *QTreeNode, section int) *QTreeNode {
 	}
 }
 
-func (qt QTreeNode) GetActiveItems() (ret []*component.PolygonCollider) {
+func (qt QTreeNode) GetActiveItems() (ret []*component.Collider) {
 	ret = append(ret, qt.items...)
 	ret = append(ret, qt.inlineItems...)
 	return ret
 }
 
-func (qt QTreeNode) GetInactiveItems() (ret []*component.PolygonCollider) {
+func (qt QTreeNode) GetInactiveItems() (ret []*component.Collider) {
 	ret = append(ret, qt.inactiveItems...)
 	return ret
 }
 
-func (qt QTreeNode) GetAllItems() (ret []*component.PolygonCollider) {
+func (qt QTreeNode) GetAllItems() (ret []*component.Collider) {
 	ret = append(ret, qt.items...)
 	ret = append(ret, qt.inlineItems...)
 	ret = append(ret, qt.inactiveItems...)
@@ -174,67 +183,234 @@ func (qt *QuadTree) TraverseWithLock(fn QTreeTraverseFunc) {
 	// qtlogger.Debugf("cost = %v", time.Since(timer))
 }
 
-func (qt *QuadTree) Insert(collider *component.PolygonCollider) {
+func (qt *QuadTree) Insert(collider *component.Collider) {
+	qt.mu.Lock()
+	qt.insertNoLock(collider)
+	qt.mu.Unlock()
+}
+
+func (qt *QuadTree) insertNoLock(collider *component.Collider) {
 	if qt.root == nil {
 		qt.root = &QTreeNode{
-			items:       []*component.PolygonCollider{},
+			items:       []*component.Collider{},
 			area:        qt.area,
 			loadFactor:  qt.loadFactor,
 			minDivision: qt.minDivision,
 			quadTree:    qt,
 		}
 	}
-	// judge fully outside of maintainance area.
+	// grow the maintainance area if the collider is not fully inside of it.
+	qt.expandToward(collider.Shape.GetBoundingBox())
+	qt.root.insertRecursively(collider)
+}
+
+// Delete removes a collider from the tree. The correlated node is located by the look up table.
+func (qt *QuadTree) Delete(collider *component.Collider) bool {
+	qt.mu.Lock()
+	ok := qt.deleteNoLock(collider)
+	qt.mu.Unlock()
+	qt.dirtyMu.Lock()
+	delete(qt.dirty, collider)
+	qt.dirtyMu.Unlock()
+	return ok
+}
+
+func (qt *QuadTree) deleteNoLock(collider *component.Collider) bool {
+	node, ok := qt.lookup[collider]
+	if !ok {
+		return false
+	}
+	if idx := node.searchFromNormal(collider); idx >= 0 {
+		node.deleteFromNormal(idx)
+		return true
+	}
+	if idx := node.searchFromInline(collider); idx >= 0 {
+		node.deleteFromInline(idx)
+		return true
+	}
+	if idx := node.searchFromInactive(collider); idx >= 0 {
+		node.deleteFromInactive(idx)
+		return true
+	}
+	return false
+}
+
+// MarkDirty tells the tree that a collider has moved, it will be relocated on next Update.
+// Concurrently safe.
+func (qt *QuadTree) MarkDirty(collider *component.Collider) {
+	qt.dirtyMu.Lock()
+	qt.dirty[collider] = struct{}{}
+	qt.dirtyMu.Unlock()
+}
+
+// Update relocates colliders marked dirty since last update, untouched colliders are never visited.
+// If most of colliders are dirty, the whole tree is refreshed instead, which is cheaper than looking up each of them.
+// Shapes of dirty colliders are synchronized with their transforms first.
+func (qt *QuadTree) Update() {
+	qt.dirtyMu.Lock()
+	dirty := qt.dirty
+	qt.dirty, qt.dirtyBack = qt.dirtyBack, qt.dirty
+	qt.dirtyMu.Unlock()
+
 	qt.mu.Lock()
-	if !collider.Collider.GetBoundingBox().ToRectangle().Intersect(&qt.area) {
-		qt.root.doInsertInline(collider)
-		qt.mu.Unlock()
+	defer qt.mu.Unlock()
+	if len(dirty)*dirtyRefreshRatio > len(qt.lookup) {
+		// shapes are synchronized while refreshing, so dirty colliders are dropped without being visited.
+		for collider := range dirty {
+			delete(dirty, collider)
+		}
+		qt.refreshNoLock()
 		return
 	}
-	qt.root.insertRecursively(collider)
+	for collider := range dirty {
+		delete(dirty, collider)
+		collider.SyncTransform()
+		node, ok := qt.lookup[collider]
+		if !ok {
+			// already removed
+			continue
+		}
+		bb := collider.Shape.GetBoundingBox()
+		if rectContains(node.area, bb) {
+			// still inside, only inline items of a branch node might move down.
+			if len(node.children) == 0 || node.GetIntersectedSection(bb) < 0 || node.searchFromInline(collider) < 0 {
+				continue
+			}
+		} else if node.searchFromInactive(collider) >= 0 {
+			// inactive colliders are relocated when activated.
+			continue
+		}
+		qt.deleteNoLock(collider)
+		qt.insertNoLock(collider)
+	}
+}
+
+// expandToward doubles the root area towards the bounding box until the box is fully inside of it.
+// The old root becomes one of the quadrants of the new root.
+func (qt *QuadTree) expandToward(bb physics.BoundingBox) {
+	topLeft := bb.GetTopLeftPoint()
+	botRight := bb.GetBottomRightPoint()
+	if math.IsNaN(topLeft.X+topLeft.Y+botRight.X+botRight.Y) || math.IsInf(topLeft.X+topLeft.Y+botRight.X+botRight.Y, 0) {
+		return
+	}
+	for !rectContains(qt.root.area, bb) {
+		old := qt.root
+		area := old.area
+		toLeft := topLeft.X < area.Left
+		toTop := topLeft.Y < area.Top
+		left := area.Left
+		top := area.Top
+		if toLeft {
+			left -= area.Width
+		}
+		if toTop {
+			top -= area.Height
+		}
+		root := &QTreeNode{
+			id:          atomic.AddInt64(&idGenerator, 1),
+			items:       []*component.Collider{},
+			area:        physics.NewRectangle(left, top, area.Width*2, area.Height*2),
+			loadFactor:  qt.loadFactor,
+			minDivision: qt.minDivision,
+			quadTree:    qt,
+		}
+		root.children = root.newChildren()
+		var section int
+		switch {
+		case toLeft && toTop:
+			section = Section1
+		case !toLeft && toTop:
+			section = Section2
+		case !toLeft && !toTop:
+			section = Section3
+		default:
+			section = Section4
+		}
+		old.parent = root
+		root.children[section] = old
+		qt.root = root
+		qt.area = root.area
+		qtlogger.Debugf("expand root, area = %v", root.area)
+	}
+}
+
+// Refresh relocates colliders that have moved out of their nodes, by traversing the whole tree.
+// Shapes are synchronized with their transforms first. Prefer Update with MarkDirty, which only visits moved colliders.
+func (qt *QuadTree) Refresh() {
+	qt.mu.Lock()
+	qt.refreshNoLock()
 	qt.mu.Unlock()
 }
 
-func (qt *QuadTree) Deactivate(collider *component.PolygonCollider) bool {
-	node, ok := qt.lookup[collider.I()]
+func (qt *QuadTree) refreshNoLock() {
+	rmColliders := []*component.Collider{}
+	qt.Traverse(func(pc *component.Collider, node *QTreeNode, at AreaType, idx int) bool {
+		pc.SyncTransform()
+		if node.needsRelocation(pc, at) {
+			rmColliders = append(rmColliders, pc)
+		}
+		return false
+	})
+	for _, elem := range rmColliders {
+		qt.deleteNoLock(elem)
+		qt.insertNoLock(elem)
+	}
+}
+
+// needsRelocation tells whether a collider no longer belongs to the node.
+func (qt *QTreeNode) needsRelocation(collider *component.Collider, at AreaType) bool {
+	bb := collider.Shape.GetBoundingBox()
+	if !rectContains(qt.area, bb) {
+		return true
+	}
+	// inline item moves down once it fits into one of the children.
+	return at == Inline && len(qt.children) > 0 && qt.GetIntersectedSection(bb) >= 0
+}
+
+func rectContains(area physics.Rectangle, bb physics.BoundingBox) bool {
+	topLeft := bb.GetTopLeftPoint()
+	botRight := bb.GetBottomRightPoint()
+	return topLeft.X >= area.Left && topLeft.Y >= area.Top && botRight.X <= area.Left+area.Width && botRight.Y <= area.Top+area.Height
+}
+
+// Deactivate moves a collider to the inactive list of its node. Concurrently safe.
+func (qt *QuadTree) Deactivate(collider *component.Collider) bool {
+	qt.mu.Lock()
+	defer qt.mu.Unlock()
+	node, ok := qt.lookup[collider]
 	if !ok {
 		panic("failed to find correlated node in look up table")
 	}
-	node.quadTree.mu.Lock()
 	idx := node.searchFromNormal(collider)
 	if idx >= 0 {
 		node.deleteFromNormal(idx)
 		node.doInsertInactive(collider)
-		node.quadTree.mu.Unlock()
 		return true
 	}
 	idx = node.searchFromInline(collider)
 	if idx >= 0 {
 		node.deleteFromInline(idx)
 		node.doInsertInactive(collider)
-		node.quadTree.mu.Unlock()
 		return true
 	}
-	node.quadTree.mu.Unlock()
 	return false
 }
 
-func (qt *QuadTree) Activate(collider *component.PolygonCollider) bool {
-	node, ok := qt.lookup[collider.I()]
+// Activate puts an inactive collider back to where it belongs now. Concurrently safe.
+func (qt *QuadTree) Activate(collider *component.Collider) bool {
+	qt.mu.Lock()
+	defer qt.mu.Unlock()
+	node, ok := qt.lookup[collider]
 	if !ok {
 		panic("failed to find correlated node in look up table")
 	}
-	node.quadTree.mu.Lock()
 	idx := node.searchFromInactive(collider)
-	if idx >= 0 {
-		node.deleteFromInactive(idx)
-		node.quadTree.mu.Unlock()
-		qt.Insert(collider)
-		return true
-	} else {
-		node.quadTree.mu.Unlock()
+	if idx < 0 {
+		return false
 	}
-	return false
+	node.deleteFromInactive(idx)
+	qt.insertNoLock(collider)
+	return true
 }
 
 func (qt *QTreeNode) tryNodeMerge() {
@@ -262,18 +438,18 @@ func (qt *QTreeNode) tryNodeMerge() {
 			}
 
 			parent.items = append(parent.items, eachChild.items...)
-			parent.inactiveItems = append(parent.items, eachChild.inactiveItems...)
+			parent.inactiveItems = append(parent.inactiveItems, eachChild.inactiveItems...)
 
 			quadTree := eachChild.quadTree
 			for idx, item := range eachChild.items {
 				eachChild.items[idx] = nil
-				quadTree.setLookup(item.I(), parent, "try node merge 1")
+				quadTree.setLookup(item, parent, "try node merge 1")
 
 			}
 
 			for idx, item := range eachChild.inactiveItems {
 				eachChild.inactiveItems[idx] = nil
-				quadTree.setLookup(item.I(), parent, "try node merge 2")
+				quadTree.setLookup(item, parent, "try node merge 2")
 			}
 
 			// no need to handle inline items, because leaf node has no inline items.
@@ -281,18 +457,18 @@ func (qt *QTreeNode) tryNodeMerge() {
 		if parent.parent != nil {
 			// parent's inline items move to grandparent.
 			quadTree := parent.quadTree
-			parent.parent.inlineItems = append(parent.inlineItems, parent.parent.inlineItems...)
-			for idx, item := range parent.inlineItems {
-				parent.inlineItems[idx] = nil
-				quadTree.setLookup(item.I(), parent.parent, "try node merge 3")
+			parent.parent.inlineItems = append(parent.parent.inlineItems, parent.inlineItems...)
+			for _, item := range parent.inlineItems {
+				quadTree.setLookup(item, parent.parent, "try node merge 3")
 			}
+			parent.inlineItems = nil
 		}
 		// abandon all childs
 		parent.children = nil
 	}
 }
 
-func (qt *QTreeNode) Delete(collider *component.PolygonCollider) {
+func (qt *QTreeNode) Delete(collider *component.Collider) {
 	qt.quadTree.mu.Lock()
 	qt.UnsafeDelete(collider)
 	qt.quadTree.mu.Unlock()
@@ -301,7 +477,7 @@ func (qt *QTreeNode) Delete(collider *component.PolygonCollider) {
 // UnsafeDelete deletes item without acquiring lock.
 // Don't use this if you don't know what you're doing.
 // Use Delete() instead.
-func (qt *QTreeNode) UnsafeDelete(collider *component.PolygonCollider) {
+func (qt *QTreeNode) UnsafeDelete(collider *component.Collider) {
 	for idx, item := range qt.items {
 		if item == collider {
 			qt.deleteFromNormal(idx)
@@ -317,34 +493,34 @@ func (qt *QTreeNode) UnsafeDelete(collider *component.PolygonCollider) {
 }
 
 func (qt *QTreeNode) deleteFromNormal(idx int) {
-	qt.quadTree.deleteLookup(qt.items[idx].I(), "deleteFromNormal")
+	qt.quadTree.deleteLookup(qt.items[idx], "deleteFromNormal")
 	qt.items = doDeleteFromArray(idx, qt.items)
 	qt.tryNodeMerge()
 }
 
 func (qt *QTreeNode) deleteFromInline(idx int) {
-	qt.quadTree.deleteLookup(qt.inlineItems[idx].I(), "deleteFromInline")
+	qt.quadTree.deleteLookup(qt.inlineItems[idx], "deleteFromInline")
 	qt.inlineItems = doDeleteFromArray(idx, qt.inlineItems)
 }
 
 func (qt *QTreeNode) deleteFromInactive(idx int) {
-	qt.quadTree.deleteLookup(qt.inactiveItems[idx].I(), "deleteFromInactive")
+	qt.quadTree.deleteLookup(qt.inactiveItems[idx], "deleteFromInactive")
 	qt.inactiveItems = doDeleteFromArray(idx, qt.inactiveItems)
 }
 
-func (qt *QTreeNode) searchFromNormal(collider *component.PolygonCollider) (index int) {
+func (qt *QTreeNode) searchFromNormal(collider *component.Collider) (index int) {
 	return doSearchFromArray(qt.items, collider)
 }
 
-func (qt *QTreeNode) searchFromInline(collider *component.PolygonCollider) (index int) {
+func (qt *QTreeNode) searchFromInline(collider *component.Collider) (index int) {
 	return doSearchFromArray(qt.inlineItems, collider)
 }
 
-func (qt *QTreeNode) searchFromInactive(collider *component.PolygonCollider) (index int) {
+func (qt *QTreeNode) searchFromInactive(collider *component.Collider) (index int) {
 	return doSearchFromArray(qt.inactiveItems, collider)
 }
 
-func doSearchFromArray(arr []*component.PolygonCollider, target *component.PolygonCollider) (index int) {
+func doSearchFromArray(arr []*component.Collider, target *component.Collider) (index int) {
 	for index = 0; index < len(arr); index++ {
 		if target == arr[index] {
 			return index
@@ -353,7 +529,7 @@ func doSearchFromArray(arr []*component.PolygonCollider, target *component.Polyg
 	return -1
 }
 
-func doDeleteFromArray(index int, arr []*component.PolygonCollider) (ret []*component.PolygonCollider) {
+func doDeleteFromArray(index int, arr []*component.Collider) (ret []*component.Collider) {
 	last := len(arr) - 1
 	arr[index] = arr[last]
 	arr[last] = nil
@@ -361,26 +537,26 @@ func doDeleteFromArray(index int, arr []*component.PolygonCollider) (ret []*comp
 	return ret
 }
 
-func (qt *QuadTree) QueryByPoint(position linalg.Vector2f64, mode QueryMode) []*component.PolygonCollider {
-	result := make([]*component.PolygonCollider, 0)
+func (qt *QuadTree) QueryByPoint(position linalg.Vector2f64, mode QueryMode) []*component.Collider {
+	result := make([]*component.Collider, 0)
 	qt.root.doQuery(position, mode, &result)
 	return result
 }
 
-func (qt *QuadTree) QueryByRect(rect physics.Rectangle, mode QueryMode) []*component.PolygonCollider {
-	result := make([]*component.PolygonCollider, 0)
+func (qt *QuadTree) QueryByRect(rect physics.Rectangle, mode QueryMode) []*component.Collider {
+	result := make([]*component.Collider, 0)
 	// query inactive
 	qt.root.doQueryByRect(rect, mode, &result)
 	return result
 }
 
-func (qt *QuadTree) QueryByRay(r physics.Ray, mode QueryMode) []*component.PolygonCollider {
-	result := make([]*component.PolygonCollider, 0)
+func (qt *QuadTree) QueryByRay(r physics.Ray, mode QueryMode) []*component.Collider {
+	result := make([]*component.Collider, 0)
 	qt.root.doQueryByRay(r, mode, &result)
 	return result
 }
 
-func (qt *QTreeNode) doQueryByRect(rect physics.Rectangle, mode QueryMode, result *[]*component.PolygonCollider) {
+func (qt *QTreeNode) doQueryByRect(rect physics.Rectangle, mode QueryMode, result *[]*component.Collider) {
 	if qt == nil {
 		return
 	}
@@ -393,7 +569,7 @@ func (qt *QTreeNode) doQueryByRect(rect physics.Rectangle, mode QueryMode, resul
 	}
 }
 
-func (qt *QTreeNode) doQueryByRay(r physics.Ray, mode QueryMode, result *[]*component.PolygonCollider) {
+func (qt *QTreeNode) doQueryByRay(r physics.Ray, mode QueryMode, result *[]*component.Collider) {
 	if qt == nil {
 		return
 	}
@@ -436,7 +612,7 @@ func (qt *QTreeNode) doTraverse(fn QTreeTraverseFunc) {
 	}
 }
 
-func (qt *QTreeNode) doQuery(position linalg.Vector2f64, mode QueryMode, result *[]*component.PolygonCollider) {
+func (qt *QTreeNode) doQuery(position linalg.Vector2f64, mode QueryMode, result *[]*component.Collider) {
 	if qt == nil {
 		return
 	}
@@ -473,12 +649,12 @@ func (qt *QTreeNode) doQuery(position linalg.Vector2f64, mode QueryMode, result
 	qt.children[Section3].doQuery(position, mode, result)
 }
 
-func (qt *QTreeNode) insertRecursively(collider *component.PolygonCollider) {
+func (qt *QTreeNode) insertRecursively(collider *component.Collider) {
 	if qt == nil {
 		return
 	}
 	if qt.children == nil {
-		qtlogger.Debugf("normal insert, %v, area = %v", collider.Collider.GetWorldVertices(), qt.area)
+		qtlogger.Debugf("normal insert, %v, area = %v", collider.Shape.GetBoundingBox(), qt.area)
 		qt.doInsertNormal(collider)
 		if len(qt.items) > qt.loadFactor {
 			if qt.area.Height > qt.minDivision && qt.area.Width > qt.minDivision {
@@ -488,7 +664,7 @@ func (qt *QTreeNode) insertRecursively(collider *component.PolygonCollider) {
 				for _, item := range qt.items {
 					qt.insertIntoChildRecursively(item)
 				}
-				qt.items = []*component.PolygonCollider{}
+				qt.items = []*component.Collider{}
 			}
 		}
 		return
@@ -497,40 +673,40 @@ func (qt *QTreeNode) insertRecursively(collider *component.PolygonCollider) {
 	qt.insertIntoChildRecursively(collider)
 }
 
-func (qt *QTreeNode) insertIntoChildRecursively(collider *component.PolygonCollider) {
+func (qt *QTreeNode) insertIntoChildRecursively(collider *component.Collider) {
 
-	whichSection := qt.GetIntersectedSection(collider.Collider.GetBoundingBox())
+	whichSection := qt.GetIntersectedSection(collider.Shape.GetBoundingBox())
 
 	if whichSection == Overlap {
 		// overlap, insert into current node's inlineItem map
 		qt.doInsertInline(collider)
-		qtlogger.Debugf("overlap, insert into inline, %v, area = %v", collider.Collider.GetWorldVertices(), qt.area)
+		qtlogger.Debugf("overlap, insert into inline, %v, area = %v", collider.Shape.GetBoundingBox(), qt.area)
 	} else if whichSection == Overflow {
 		// overflow
 		if qt.parent != nil {
 			panic("should not happen")
 		}
 		qt.doInsertInline(collider)
-		qtlogger.Debugf("overflow, insert into inline, %v, area = %v", collider.Collider.GetWorldVertices(), qt.area)
+		qtlogger.Debugf("overflow, insert into inline, %v, area = %v", collider.Shape.GetBoundingBox(), qt.area)
 	} else {
 		// normal insert, no overlap, insert into children
 		qt.children[whichSection].insertRecursively(collider)
 	}
 }
 
-func (qt *QTreeNode) doInsertInline(collider *component.PolygonCollider) {
+func (qt *QTreeNode) doInsertInline(collider *component.Collider) {
 	qt.inlineItems = append(qt.inlineItems, collider)
-	qt.quadTree.setLookup(collider.I(), qt, "doInsertInline")
+	qt.quadTree.setLookup(collider, qt, "doInsertInline")
 }
 
-func (qt *QTreeNode) doInsertNormal(collider *component.PolygonCollider) {
+func (qt *QTreeNode) doInsertNormal(collider *component.Collider) {
 	qt.items = append(qt.items, collider)
-	qt.quadTree.setLookup(collider.I(), qt, "doInsertNormal")
+	qt.quadTree.setLookup(collider, qt, "doInsertNormal")
 }
 
-func (qt *QTreeNode) doInsertInactive(collider *component.PolygonCollider) {
+func (qt *QTreeNode) doInsertInactive(collider *component.Collider) {
 	qt.inactiveItems = append(qt.inactiveItems, collider)
-	qt.quadTree.setLookup(collider.I(), qt, "doInsertInactive")
+	qt.quadTree.setLookup(collider, qt, "doInsertInactive")
 }
 
 func (qt *QTreeNode) newChildren() []*QTreeNode {
@@ -604,12 +780,19 @@ func (qt *QTreeNode) GetIntersectedSection(bb physics.BoundingBox) int {
 	return whichSection
 }
 
-func (qt *QuadTree) setLookup(iobj base.IGameObject2D, node *QTreeNode, remark ...string) {
-	qtlogger.Debugf("setting lookup for %v, extra = %v", iobj.Obj().Name, remark)
-	qt.lookup[iobj] = node
+func (qt *QuadTree) setLookup(collider *component.Collider, node *QTreeNode, remark ...string) {
+	qtlogger.Debugf("setting lookup for %v, extra = %v", colliderName(collider), remark)
+	qt.lookup[collider] = node
+}
+
+func (qt *QuadTree) deleteLookup(collider *component.Collider, remark ...string) {
+	qtlogger.Debugf("deleting lookup for %v, extra = %v", colliderName(collider), remark)
+	delete(qt.lookup, collider)
 }
 
-func (qt *QuadTree) deleteLookup(iobj base.IGameObject2D, remark ...string) {
-	qtlogger.Debugf("deleting lookup for %v, extra = %v", iobj.Obj().Name, remark)
-	delete(qt.lookup, iobj)
+func colliderName(collider *component.Collider) string {
+	if collider.I() == nil {
+		return "<anonymous>"
+	}
+	return collider.I().Obj().Name
 }
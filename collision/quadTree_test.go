@@ -3,11 +3,25 @@ package collision
 import (
 	"testing"
 
+	"galaxyzeta.io/engine/base"
 	"galaxyzeta.io/engine/ecs/component"
+	"galaxyzeta.io/engine/infra/require"
 	"galaxyzeta.io/engine/linalg"
 	"galaxyzeta.io/engine/physics"
 )
 
+type testObj struct {
+	*base.GameObject2D
+}
+
+func newTestObj() *testObj {
+	return &testObj{GameObject2D: base.NewGameObject2D("test")}
+}
+
+func (o *testObj) Obj() *base.GameObject2D {
+	return o.GameObject2D
+}
+
 func TestInsertIntoQuadTree(t *testing.T) {
 	qt := NewQuadTree(physics.NewRectangle(-128, -128, 256, 256), 2, 64)
 	pivot := linalg.NewVector2f64(0, 0)
@@ -19,33 +33,175 @@ func TestInsertIntoQuadTree(t *testing.T) {
 		for j := -128.0; j < 128; j += 70 {
 			t.Log(i, j)
 			cnt++
-			qt.Insert(&component.PolygonCollider{
-				Collider: *physics.NewPolygon(&linalg.Vector2f64{X: i, Y: j},
-					pivot, 0, vertices[:]),
-			})
+			qt.Insert(component.NewCollider(*physics.NewPolygon(&linalg.Vector2f64{X: i, Y: j},
+				pivot, 0, vertices[:]), newTestObj()))
 		}
 	}
-	qt.Traverse(func(pc *component.PolygonCollider, node *QTreeNode, at AreaType, idx int) bool {
+	qt.Traverse(func(pc *component.Collider, node *QTreeNode, at AreaType, idx int) bool {
 		cnt--
-		t.Log(pc.Collider.GetBoundingBox(), node)
+		t.Log(pc.Shape.GetBoundingBox(), node)
 		return false
 	})
 	t.Log(cnt)
 
 	t.Log("Q1==============")
 	for _, elem := range qt.QueryByPoint(linalg.NewVector2f64(1, 1), ActiveOnly) {
-		t.Log(elem.Collider.GetWorldVertices())
+		t.Log(elem.Shape.GetBoundingBox())
 	}
 	t.Log("Q2==============")
 	for _, elem := range qt.QueryByPoint(linalg.NewVector2f64(-1, 1), ActiveOnly) {
-		t.Log(elem.Collider.GetWorldVertices())
+		t.Log(elem.Shape.GetBoundingBox())
 	}
 	t.Log("Q3==============")
 	for _, elem := range qt.QueryByPoint(linalg.NewVector2f64(-1, -1), ActiveOnly) {
-		t.Log(elem.Collider.GetWorldVertices())
+		t.Log(elem.Shape.GetBoundingBox())
 	}
 	t.Log("Q4==============")
 	for _, elem := range qt.QueryByPoint(linalg.NewVector2f64(1, -1), ActiveOnly) {
-		t.Log(elem.Collider.GetWorldVertices())
+		t.Log(elem.Shape.GetBoundingBox())
+	}
+}
+
+func TestQuadTreeUpdate(t *testing.T) {
+	qt := NewQuadTree(physics.NewRectangle(0, 0, 256, 256), 2, 32)
+	var tfs []*component.Transform2D
+	var cols []*component.Collider
+	for i := 0; i < 8; i++ {
+		tf := component.NewTransform2D()
+		tf.Teleport(float64(i*30+10), float64(i*30+10))
+		col := component.NewCollider(physics.NewAnchoredRectangle(&tf.Pos, -4, -4, 8, 8), newTestObj())
+		tf.AddChangeListener(qt, func(*component.Transform2D) {
+			qt.MarkDirty(col)
+		})
+		qt.Insert(col)
+		tfs = append(tfs, tf)
+		cols = append(cols, col)
+	}
+	require.EqBool(len(qt.QueryByPoint(linalg.NewVector2f64(10, 10), ActiveOnly)) > 0, true)
+
+	// leave the maintainance area, root should be expanded
+	tfs[0].Teleport(-1000, 2000)
+	qt.Update()
+	require.EqBool(rectContains(qt.root.area, cols[0].Shape.GetBoundingBox()), true)
+	found := false
+	for _, col := range qt.QueryByRect(physics.NewRectangle(-1001, 1999, 2, 2), ActiveOnly) {
+		if col == cols[0] {
+			found = true
+		}
+	}
+	require.EqBool(found, true)
+
+	// everyone is still reachable after the expansion
+	for i, col := range cols {
+		found = false
+		for _, candidate := range qt.QueryByRect(col.Shape.GetBoundingBox().ToRectangle(), ActiveOnly) {
+			if candidate == col {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("collider %d is lost", i)
+		}
+	}
+
+	// every collider moves, Update falls back to a full refresh.
+	for i, tf := range tfs {
+		tf.Teleport(float64(200-i*25), float64(i*25+20))
+	}
+	qt.Update()
+	require.EqInt(len(qt.dirty), 0)
+	for i, col := range cols {
+		if !qtContains(qt, col) {
+			t.Fatalf("collider %d is lost after refresh", i)
+		}
+	}
+
+	// inactive colliders are not queried, and are relocated when activated.
+	require.EqBool(qt.Deactivate(cols[1]), true)
+	require.EqBool(qtContains(qt, cols[1]), false)
+	tfs[1].Teleport(100, 100)
+	qt.Update()
+	require.EqBool(qt.Activate(cols[1]), true)
+	require.EqBool(qtContains(qt, cols[1]), true)
+
+	for _, col := range cols {
+		require.EqBool(qt.Delete(col), true)
+	}
+	require.EqInt(len(qt.lookup), 0)
+}
+
+// qtContains tells whether an active collider could be found by querying its bounding box.
+func qtContains(qt *QuadTree, col *component.Collider) bool {
+	for _, candidate := range qt.QueryByRect(col.Shape.GetBoundingBox().ToRectangle(), ActiveOnly) {
+		if candidate == col {
+			return true
+		}
+	}
+	return false
+}
+
+// fewMovingRatio is the proportion of objects moving in each frame, most of objects in a level are static.
+const fewMovingRatio = 20
+
+func newQuadTreeForBench(objs []*benchObject) *QuadTree {
+	qtlogger.Disable()
+	qt := NewQuadTree(physics.NewRectangle(-64, -64, benchWorldSize+128, benchWorldSize+128), 4, 64)
+	for _, obj := range objs {
+		qt.Insert(obj.col)
+	}
+	return qt
+}
+
+func BenchmarkQuadTreeRefreshFewMoving(b *testing.B) {
+	objs := newBenchObjects()
+	qt := newQuadTreeForBench(objs)
+	defer qtlogger.Enable()
+	moving := objs[:len(objs)/fewMovingRatio]
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stepBenchObjects(moving)
+		qt.Refresh()
+	}
+}
+
+func BenchmarkQuadTreeUpdateFewMoving(b *testing.B) {
+	objs := newBenchObjects()
+	qt := newQuadTreeForBench(objs)
+	defer qtlogger.Enable()
+	moving := objs[:len(objs)/fewMovingRatio]
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stepBenchObjects(moving)
+		for _, obj := range moving {
+			qt.MarkDirty(obj.col)
+		}
+		qt.Update()
+	}
+}
+
+// When every collider moves, Update falls back to a full refresh without visiting dirty colliders one by one.
+// It is still slower than Refresh by the cost of marking every collider dirty, which Refresh does not need.
+func BenchmarkQuadTreeRefreshAllMoving(b *testing.B) {
+	objs := newBenchObjects()
+	qt := newQuadTreeForBench(objs)
+	defer qtlogger.Enable()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stepBenchObjects(objs)
+		qt.Refresh()
+	}
+}
+
+func BenchmarkQuadTreeUpdateAllMoving(b *testing.B) {
+	objs := newBenchObjects()
+	qt := newQuadTreeForBench(objs)
+	defer qtlogger.Enable()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stepBenchObjects(objs)
+		for _, obj := range objs {
+			qt.MarkDirty(obj.col)
+		}
+		qt.Update()
 	}
 }
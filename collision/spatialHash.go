@@ -0,0 +1,293 @@
+package collision
+
+import (
+	"math"
+
+	"galaxyzeta.io/engine/config"
+	"galaxyzeta.io/engine/ecs/component"
+	"galaxyzeta.io/engine/infra/concurrency/lock"
+	"galaxyzeta.io/engine/linalg"
+	"galaxyzeta.io/engine/physics"
+)
+
+// SpatialHash is a uniform grid broadphase. Cells are created lazily and kept in a map,
+// so unlike the quad tree it has no maintainance area and grows with the world without bounds.
+type SpatialHash struct {
+	cellWidth  float64
+	cellHeight float64
+	cells      map[cellKey]*hashCell
+	lookup     map[*component.Collider]*hashEntry
+	mu         *lock.SpinLock
+}
+
+type cellKey struct {
+	X int
+	Y int
+}
+
+// cellRange is an inclusive range of cells covered by a bounding box.
+type cellRange struct {
+	minX int
+	minY int
+	maxX int
+	maxY int
+}
+
+type hashCell struct {
+	items []*component.Collider
+}
+
+type hashEntry struct {
+	cells  cellRange
+	active bool
+}
+
+// NewSpatialHash creates a spatial hash with given cell size.
+// Non-positive cell size falls back to config.GridCollisionGridWidth and config.GridCollisionGridHeight.
+func NewSpatialHash(cellWidth float64, cellHeight float64) *SpatialHash {
+	if cellWidth <= 0 {
+		cellWidth = float64(config.GridCollisionGridWidth)
+	}
+	if cellHeight <= 0 {
+		cellHeight = float64(config.GridCollisionGridHeight)
+	}
+	return &SpatialHash{
+		cellWidth:  cellWidth,
+		cellHeight: cellHeight,
+		cells:      make(map[cellKey]*hashCell),
+		lookup:     make(map[*component.Collider]*hashEntry),
+		mu:         &lock.SpinLock{},
+	}
+}
+
+func (sh *SpatialHash) GetCellSize() (float64, float64) {
+	return sh.cellWidth, sh.cellHeight
+}
+
+// Len returns how many colliders are managed by the spatial hash.
+func (sh *SpatialHash) Len() int {
+	return len(sh.lookup)
+}
+
+func (sh *SpatialHash) Insert(collider *component.Collider) {
+	sh.mu.Lock()
+	if _, ok := sh.lookup[collider]; ok {
+		sh.mu.Unlock()
+		return
+	}
+	cr := sh.rangeOf(collider.Shape.GetBoundingBox())
+	sh.lookup[collider] = &hashEntry{cells: cr, active: true}
+	sh.addToCells(collider, cr)
+	sh.mu.Unlock()
+}
+
+func (sh *SpatialHash) Delete(collider *component.Collider) {
+	sh.mu.Lock()
+	if entry, ok := sh.lookup[collider]; ok {
+		sh.removeFromCells(collider, entry.cells)
+		delete(sh.lookup, collider)
+	}
+	sh.mu.Unlock()
+}
+
+func (sh *SpatialHash) Deactivate(collider *component.Collider) bool {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	entry, ok := sh.lookup[collider]
+	if !ok {
+		panic("failed to find correlated entry in look up table")
+	}
+	if !entry.active {
+		return false
+	}
+	entry.active = false
+	return true
+}
+
+func (sh *SpatialHash) Activate(collider *component.Collider) bool {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	entry, ok := sh.lookup[collider]
+	if !ok {
+		panic("failed to find correlated entry in look up table")
+	}
+	if entry.active {
+		return false
+	}
+	entry.active = true
+	sh.relocate(collider, entry)
+	return true
+}
+
+// Refresh moves every active collider into the cells it currently covers, after its shape is synchronized with its transform.
+func (sh *SpatialHash) Refresh() {
+	sh.mu.Lock()
+	for collider, entry := range sh.lookup {
+		collider.SyncTransform()
+		if entry.active {
+			sh.relocate(collider, entry)
+		}
+	}
+	sh.mu.Unlock()
+}
+
+// Traverse visits each collider once, stops when fn returns true.
+func (sh *SpatialHash) Traverse(fn func(*component.Collider, bool) bool) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	for collider, entry := range sh.lookup {
+		if fn(collider, entry.active) {
+			return
+		}
+	}
+}
+
+func (sh *SpatialHash) QueryByPoint(position linalg.Vector2f64, mode QueryMode) []*component.Collider {
+	result := make([]*component.Collider, 0)
+	sh.mu.Lock()
+	if cell, ok := sh.cells[sh.keyOf(position.X, position.Y)]; ok {
+		for _, item := range cell.items {
+			if sh.matchMode(item, mode) {
+				result = append(result, item)
+			}
+		}
+	}
+	sh.mu.Unlock()
+	return result
+}
+
+func (sh *SpatialHash) QueryByRect(rect physics.Rectangle, mode QueryMode) []*component.Collider {
+	cr := sh.rangeOf(rect.GetBoundingBox())
+	result := make([]*component.Collider, 0)
+	sh.mu.Lock()
+	if cr.minX == cr.maxX && cr.minY == cr.maxY {
+		// single cell, no duplication
+		if cell, ok := sh.cells[cellKey{X: cr.minX, Y: cr.minY}]; ok {
+			for _, item := range cell.items {
+				if sh.matchMode(item, mode) {
+					result = append(result, item)
+				}
+			}
+		}
+		sh.mu.Unlock()
+		return result
+	}
+	seen := make(map[*component.Collider]struct{})
+	sh.forEachCell(cr, func(cell *hashCell) {
+		for _, item := range cell.items {
+			if _, ok := seen[item]; ok || !sh.matchMode(item, mode) {
+				continue
+			}
+			seen[item] = struct{}{}
+			result = append(result, item)
+		}
+	})
+	sh.mu.Unlock()
+	return result
+}
+
+func (sh *SpatialHash) QueryByRay(r physics.Ray, mode QueryMode) []*component.Collider {
+	result := make([]*component.Collider, 0)
+	seen := make(map[*component.Collider]struct{})
+	sh.mu.Lock()
+	for key, cell := range sh.cells {
+		cellRect := physics.NewRectangle(float64(key.X)*sh.cellWidth, float64(key.Y)*sh.cellHeight, sh.cellWidth, sh.cellHeight)
+		if !r.IntersectPolygon(cellRect.ToPolygon()) {
+			continue
+		}
+		for _, item := range cell.items {
+			if _, ok := seen[item]; ok || !sh.matchMode(item, mode) {
+				continue
+			}
+			seen[item] = struct{}{}
+			result = append(result, item)
+		}
+	}
+	sh.mu.Unlock()
+	return result
+}
+
+func (sh *SpatialHash) matchMode(collider *component.Collider, mode QueryMode) bool {
+	switch mode {
+	case ActiveOnly:
+		return sh.lookup[collider].active
+	case InactiveOnly:
+		return !sh.lookup[collider].active
+	}
+	return true
+}
+
+func (sh *SpatialHash) relocate(collider *component.Collider, entry *hashEntry) {
+	cr := sh.rangeOf(collider.Shape.GetBoundingBox())
+	if cr == entry.cells {
+		return
+	}
+	sh.removeFromCells(collider, entry.cells)
+	sh.addToCells(collider, cr)
+	entry.cells = cr
+}
+
+func (sh *SpatialHash) addToCells(collider *component.Collider, cr cellRange) {
+	for x := cr.minX; x <= cr.maxX; x++ {
+		for y := cr.minY; y <= cr.maxY; y++ {
+			key := cellKey{X: x, Y: y}
+			cell, ok := sh.cells[key]
+			if !ok {
+				cell = &hashCell{}
+				sh.cells[key] = cell
+			}
+			cell.items = append(cell.items, collider)
+		}
+	}
+}
+
+func (sh *SpatialHash) removeFromCells(collider *component.Collider, cr cellRange) {
+	for x := cr.minX; x <= cr.maxX; x++ {
+		for y := cr.minY; y <= cr.maxY; y++ {
+			key := cellKey{X: x, Y: y}
+			cell, ok := sh.cells[key]
+			if !ok {
+				continue
+			}
+			if idx := doSearchFromArray(cell.items, collider); idx >= 0 {
+				cell.items = doDeleteFromArray(idx, cell.items)
+			}
+			if len(cell.items) == 0 {
+				// release empty cells so that the map won't keep growing as objects travel around.
+				delete(sh.cells, key)
+			}
+		}
+	}
+}
+
+func (sh *SpatialHash) forEachCell(cr cellRange, fn func(*hashCell)) {
+	// iterate whichever is smaller, the requested range or the occupied cells.
+	if (cr.maxX-cr.minX+1)*(cr.maxY-cr.minY+1) > len(sh.cells) {
+		for key, cell := range sh.cells {
+			if key.X >= cr.minX && key.X <= cr.maxX && key.Y >= cr.minY && key.Y <= cr.maxY {
+				fn(cell)
+			}
+		}
+		return
+	}
+	for x := cr.minX; x <= cr.maxX; x++ {
+		for y := cr.minY; y <= cr.maxY; y++ {
+			if cell, ok := sh.cells[cellKey{X: x, Y: y}]; ok {
+				fn(cell)
+			}
+		}
+	}
+}
+
+func (sh *SpatialHash) keyOf(x float64, y float64) cellKey {
+	return cellKey{
+		X: int(math.Floor(x / sh.cellWidth)),
+		Y: int(math.Floor(y / sh.cellHeight)),
+	}
+}
+
+func (sh *SpatialHash) rangeOf(bb physics.BoundingBox) cellRange {
+	min := sh.keyOf(bb.GetTopLeftPoint().X, bb.GetTopLeftPoint().Y)
+	max := sh.keyOf(bb.GetBottomRightPoint().X, bb.GetBottomRightPoint().Y)
+	return cellRange{minX: min.X, minY: min.Y, maxX: max.X, maxY: max.Y}
+}
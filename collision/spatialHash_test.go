@@ -0,0 +1,114 @@
+package collision
+
+import (
+	"math/rand"
+	"testing"
+
+	"galaxyzeta.io/engine/ecs/component"
+	"galaxyzeta.io/engine/infra/require"
+	"galaxyzeta.io/engine/linalg"
+	"galaxyzeta.io/engine/physics"
+)
+
+func TestSpatialHash(t *testing.T) {
+	sh := NewSpatialHash(64, 64)
+	anchor := linalg.NewVector2f64Ptr(10, 10)
+	small := component.NewCollider(physics.NewAnchoredRectangle(anchor, 0, 0, 8, 8), newTestObj())
+	// a big one covers 4 cells
+	big := component.NewCollider(physics.NewRectangle(-32, -32, 64, 64), newTestObj())
+	sh.Insert(small)
+	sh.Insert(big)
+
+	require.EqInt(len(sh.QueryByPoint(linalg.NewVector2f64(12, 12), ActiveOnly)), 2)
+	require.EqInt(len(sh.QueryByPoint(linalg.NewVector2f64(-12, -12), ActiveOnly)), 1)
+	require.EqInt(len(sh.QueryByRect(physics.NewRectangle(-100, -100, 200, 200), ActiveOnly)), 2)
+
+	// move far away, cells are created on demand
+	anchor.X, anchor.Y = 100000, -100000
+	sh.Refresh()
+	require.EqInt(len(sh.QueryByPoint(linalg.NewVector2f64(12, 12), ActiveOnly)), 1)
+	require.EqInt(len(sh.QueryByPoint(linalg.NewVector2f64(100004, -99996), ActiveOnly)), 1)
+
+	require.EqBool(sh.Deactivate(small), true)
+	require.EqInt(len(sh.QueryByPoint(linalg.NewVector2f64(100004, -99996), ActiveOnly)), 0)
+	require.EqInt(len(sh.QueryByPoint(linalg.NewVector2f64(100004, -99996), InactiveOnly)), 1)
+	require.EqBool(sh.Activate(small), true)
+
+	sh.Delete(small)
+	sh.Delete(big)
+	require.EqInt(sh.Len(), 0)
+	require.EqInt(len(sh.cells), 0)
+}
+
+const benchObjectCount = 2000
+const benchWorldSize = 4096.0
+
+type benchObject struct {
+	anchor *linalg.Vector2f64
+	vx     float64
+	vy     float64
+	col    *component.Collider
+}
+
+func newBenchObjects() []*benchObject {
+	rnd := rand.New(rand.NewSource(1))
+	objs := make([]*benchObject, 0, benchObjectCount)
+	for i := 0; i < benchObjectCount; i++ {
+		anchor := linalg.NewVector2f64Ptr(rnd.Float64()*benchWorldSize, rnd.Float64()*benchWorldSize)
+		objs = append(objs, &benchObject{
+			anchor: anchor,
+			vx:     rnd.Float64()*8 - 4,
+			vy:     rnd.Float64()*8 - 4,
+			col:    component.NewCollider(physics.NewAnchoredRectangle(anchor, -8, -8, 16, 16), newTestObj()),
+		})
+	}
+	return objs
+}
+
+// stepBenchObjects moves every object and bounces it back at world edges.
+func stepBenchObjects(objs []*benchObject) {
+	for _, obj := range objs {
+		obj.anchor.X += obj.vx
+		obj.anchor.Y += obj.vy
+		if obj.anchor.X < 0 || obj.anchor.X > benchWorldSize {
+			obj.vx = -obj.vx
+		}
+		if obj.anchor.Y < 0 || obj.anchor.Y > benchWorldSize {
+			obj.vy = -obj.vy
+		}
+	}
+}
+
+func BenchmarkQuadTreeMovingObjects(b *testing.B) {
+	qtlogger.Disable()
+	defer qtlogger.Enable()
+	objs := newBenchObjects()
+	qt := NewQuadTree(physics.NewRectangle(-64, -64, benchWorldSize+128, benchWorldSize+128), 4, 64)
+	for _, obj := range objs {
+		qt.Insert(obj.col)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stepBenchObjects(objs)
+		qt.Refresh()
+		for _, obj := range objs {
+			qt.QueryByRect(obj.col.Shape.GetBoundingBox().ToRectangle(), ActiveOnly)
+		}
+	}
+}
+
+func BenchmarkSpatialHashMovingObjects(b *testing.B) {
+	objs := newBenchObjects()
+	sh := NewSpatialHash(64, 64)
+	for _, obj := range objs {
+		sh.Insert(obj.col)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stepBenchObjects(objs)
+		sh.Refresh()
+		for _, obj := range objs {
+			sh.QueryByRect(obj.col.Shape.GetBoundingBox().ToRectangle(), ActiveOnly)
+		}
+	}
+}
@@ -8,8 +8,8 @@ import (
 
 type ICollisionSystem interface {
 	base.ISystem
-	QueryNeighborCollidersWithCollider(col component.PolygonCollider, mode QueryMode) []*component.PolygonCollider
-	QueryNeighborCollidersWithPosition(pos linalg.Vector2f64, mode QueryMode) []*component.PolygonCollider
-	QueryNeighborCollidersWithColliderAndFilter(col component.PolygonCollider, filter func(*component.PolygonCollider) bool, mode QueryMode) []*component.PolygonCollider
-	QueryNeighborCollidersWithPositionAndFilter(pos linalg.Vector2f64, filter func(*component.PolygonCollider) bool, mode QueryMode) []*component.PolygonCollider
+	QueryNeighborCollidersWithCollider(col component.Collider, mode QueryMode) []*component.Collider
+	QueryNeighborCollidersWithPosition(pos linalg.Vector2f64, mode QueryMode) []*component.Collider
+	QueryNeighborCollidersWithColliderAndFilter(col component.Collider, filter func(*component.Collider) bool, mode QueryMode) []*component.Collider
+	QueryNeighborCollidersWithPositionAndFilter(pos linalg.Vector2f64, filter func(*component.Collider) bool, mode QueryMode) []*component.Collider
 }
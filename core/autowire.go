@@ -26,7 +26,6 @@ func Inject(iobj base.IGameObject2D) {
 		iobj:                       iobj,
 		toInject:                   reflect.ValueOf(iobj),
 		delayedInjectionFieldIndex: []int{},
-		cachedTf:                   &component.Transform2D{},
 	}
 	// 1st inject attempt
 	injectAll(&injCtx)
@@ -64,7 +63,9 @@ func doInject(injCtx *injectContext, i int, tolerateMissingDep bool) {
 	attrs := strings.Split(tag, "|")
 	switch attrs[0] {
 	case "tf":
-		injectValue(&fdv, component.NewTransform2D(), iobj)
+		// SpriteRenderer follows position, rotation and scale of the transform.
+		injCtx.cachedTf = component.NewTransform2D()
+		injectValue(&fdv, injCtx.cachedTf, iobj)
 	case "rb":
 		injectValue(&fdv, component.NewRigidBody2D(), iobj)
 	case "sr":
@@ -77,6 +78,30 @@ func doInject(injCtx *injectContext, i int, tolerateMissingDep bool) {
 		}
 		animator, isStatic, opts := mustParseSr(attrs)
 		injectValue(&fdv, component.NewSpriteRendererWithOptions(animator, injCtx.cachedTf, isStatic, opts), iobj)
+	case "pe":
+		// "pe|emitter=name|static=true|layer=name" uses a particle emitter config declared in level metas.
+		// "pe|...|sorting-layer=name|z=1" puts the emitter on a sorting layer, and orders it in the layer.
+		if injCtx.cachedTf == nil {
+			if !tolerateMissingDep {
+				panic("required Transform2D for ParticleEmitter is not found")
+			}
+			injCtx.delayedInjectionFieldIndex = append(injCtx.delayedInjectionFieldIndex, i)
+			return
+		}
+		params := mustResolveParams(attrs[1:])
+		isStatic := params["static"] == "true" || params["static"] == "1"
+		cfg := component.GetParticleEmitterConfig(params["emitter"])
+		emitter := component.NewParticleEmitterWithConfig(cfg, injCtx.cachedTf, isStatic)
+		if layerName, ok := params["layer"]; ok {
+			emitter.Layer = graphics.GetLayer(layerName)
+		}
+		if sortingLayerName, ok := params["sorting-layer"]; ok {
+			emitter.SortingLayer = graphics.GetSortingLayer(sortingLayerName)
+		}
+		if z, ok := params["z"]; ok {
+			emitter.SetZ(parser.MustParseInt(z))
+		}
+		injectValue(&fdv, emitter, iobj)
 	}
 }
 
@@ -88,8 +113,12 @@ func injectValue(val *reflect.Value, any interface{}, iobj base.IGameObject2D) {
 
 func mustParseSr(attrs []string) (animator *graphics.Animator, isStatic bool, options graphics.RenderOptions) {
 	// "sr|clips=clip,status,clip,status,...|static=true|pivot=tl"
+	// "sr|animator=name|static=true|pivot=tl" uses an animation state machine declared in level metas instead of clips.
+	// "sr|...|material=name" draws with a copy of a material declared in level metas.
+	// "sr|...|layer=name" puts the renderer on a layer declared in level metas.
+	// "sr|...|sorting-layer=name|z=1" puts the renderer on a sorting layer, and orders it in the layer.
 	clipPairs := make([]graphics.StateClipPair, 0)
-	params := mustResolveParams(attrs)
+	params := mustResolveParams(attrs[1:])
 	clipPairDefs, ok := params["clips"]
 	if ok {
 		terms := strings.Split(clipPairDefs, ",")
@@ -142,7 +171,24 @@ func mustParseSr(attrs []string) (animator *graphics.Animator, isStatic bool, op
 		s := parser.MustParseNumericStringTuple(scaleAttr)
 		options.Scale = &s
 	}
-	animator = graphics.NewAnimator(clipPairs...)
+	if materialName, ok := params["material"]; ok {
+		options.Material = graphics.GetMaterial(materialName)
+	}
+	if layerName, ok := params["layer"]; ok {
+		options.Layer = graphics.GetLayer(layerName)
+	}
+	if sortingLayerName, ok := params["sorting-layer"]; ok {
+		sortingLayer := graphics.GetSortingLayer(sortingLayerName)
+		options.SortingLayer = &sortingLayer
+	}
+	if z, ok := params["z"]; ok {
+		options.Z = parser.MustParseInt(z)
+	}
+	if machineName, ok := params["animator"]; ok {
+		animator = graphics.NewAnimatorFromStateMachine(graphics.GetAnimStateMachine(machineName))
+	} else {
+		animator = graphics.NewAnimator(clipPairs...)
+	}
 	return
 }
 
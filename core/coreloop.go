@@ -40,6 +40,7 @@ func init() {
 // AppConfig stores all user defined configs.
 type AppConfig struct {
 	Resolution  *linalg.Vector2f64
+	ScaleMode   graphics.ScaleMode // how cameras are fitted into the window.
 	PhysicalFps int
 	RenderFps   int
 	Parallelism int
@@ -99,6 +100,7 @@ func NewApplication(cfg *AppConfig) *Application {
 	}
 
 	graphics.SetScreenResolution(cfg.Resolution.X, cfg.Resolution.Y)
+	graphics.SetScaleMode(cfg.ScaleMode)
 
 	return app
 }
@@ -237,7 +239,16 @@ func (g *Application) doPhysicalUpdate() {
 		req := <-g.unregisterChannel
 		g.doObjectRemoval(req.payload)
 	}
-	// 6. memorize current step
+	// 6. propagate transforms from parents to children
+	for _, pool := range activePoolReplica {
+		for iobj2d, _ := range pool {
+			tf := iobj2d.Obj().GetComponent(component.NameTransform2D).(*component.Transform2D)
+			if tf.GetParent() == nil {
+				tf.PropagateToChildren()
+			}
+		}
+	}
+	// 7. memorize current step
 	for _, pool := range activePoolReplica {
 		for iobj2d, _ := range pool {
 			tf := iobj2d.Obj().GetComponent(component.NameTransform2D).(*component.Transform2D)
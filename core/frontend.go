@@ -5,6 +5,7 @@ import (
 	"runtime"
 	"time"
 
+	"galaxyzeta.io/engine/base"
 	"galaxyzeta.io/engine/graphics"
 	"galaxyzeta.io/engine/input/keys"
 	"galaxyzeta.io/engine/linalg"
@@ -52,6 +53,21 @@ func cursorCb(w *glfw.Window, xpos float64, ypos float64) {
 	mutexList[Mutex_CursorPos].Unlock()
 }
 
+// sizeCb keeps the screen resolution in screen coordinates, in which cursor positions are reported.
+func sizeCb(w *glfw.Window, width int, height int) {
+	if width > 0 && height > 0 {
+		graphics.SetScreenResolution(float64(width), float64(height))
+	}
+}
+
+// framebufferSizeCb keeps the size of the window in pixels, viewports of cameras are fitted into it in the next frame.
+func framebufferSizeCb(w *glfw.Window, width int, height int) {
+	// the window is minimized.
+	if width > 0 && height > 0 {
+		graphics.SetFramebufferSize(float64(width), float64(height))
+	}
+}
+
 // InitOpenGL will be called at the very beginning of the whole program.
 func InitOpenGL(resolution linalg.Vector2f64, title string) *glfw.Window {
 	// glfw init
@@ -69,6 +85,10 @@ func InitOpenGL(resolution linalg.Vector2f64, title string) *glfw.Window {
 	window.SetKeyCallback(keyboardCb)
 	window.SetMouseButtonCallback(mouseButtonCb)
 	window.SetCursorPosCallback(cursorCb)
+	window.SetSizeCallback(sizeCb)
+	window.SetFramebufferSizeCallback(framebufferSizeCb)
+	fbWidth, fbHeight := window.GetFramebufferSize()
+	graphics.SetFramebufferSize(float64(fbWidth), float64(fbHeight))
 	glfw.SwapInterval(1)
 
 	// opengl init
@@ -86,42 +106,16 @@ func InitOpenGL(resolution linalg.Vector2f64, title string) *glfw.Window {
 	return window
 }
 
+// installShaders installs builtin shaders, which could be replaced by shaders of the same names declared in level metas.
 func installShaders() {
-	// handle default shader
-	// TODO shader loading refactor, using configuration
-	graphics.GLNewShader(
-		"default",
-		graphics.GLMustPrepareShaderProgram(fmt.Sprintf("%s/graphics/shaders/simpleVertex.glsl", GetCwd()), fmt.Sprintf("%s/graphics/shaders/simpleFragment.glsl", GetCwd())),
-		graphics.GLNewVAO(1),
-		func(program uint32) {
-			// process uniform
-			textureUniform := gl.GetUniformLocation(program, gl.Str("tex\x00"))
-			gl.Uniform1i(textureUniform, 0)
-			// process input
-			// -- position
-			aPos := uint32(gl.GetAttribLocation(program, gl.Str("aPos\x00")))
-			gl.VertexAttribPointerWithOffset(aPos, 3, gl.DOUBLE, false, 5*8, 0)
-			gl.EnableVertexAttribArray(aPos)
-			// -- uv
-			texcoord := uint32(gl.GetAttribLocation(program, gl.Str("vertTexCoord\x00")))
-			gl.EnableVertexAttribArray(texcoord)
-			gl.VertexAttribPointerWithOffset(texcoord, 2, gl.DOUBLE, false, 5*8, 3*8)
-		})
-	graphics.GLNewShader(
-		"color",
-		graphics.GLMustPrepareShaderProgram(fmt.Sprintf("%s/graphics/shaders/colorVertex.glsl", GetCwd()), fmt.Sprintf("%s/graphics/shaders/colorFragment.glsl", GetCwd())),
-		graphics.GLNewVAO(1),
-		func(program uint32) {
-			// process input
-			// -- position
-			aPos := uint32(gl.GetAttribLocation(program, gl.Str("aPos\x00")))
-			gl.VertexAttribPointerWithOffset(aPos, 3, gl.DOUBLE, false, 7*8, 0)
-			gl.EnableVertexAttribArray(aPos)
-			// -- color
-			color := uint32(gl.GetAttribLocation(program, gl.Str("inputColor\x00")))
-			gl.EnableVertexAttribArray(color)
-			gl.VertexAttribPointerWithOffset(color, 4, gl.DOUBLE, false, 7*8, 3*8)
-		})
+	for _, layout := range []graphics.ShaderLayout{graphics.ShaderLayout_Sprite, graphics.ShaderLayout_Color} {
+		name, vert, frag := graphics.BuiltinShader(layout)
+		graphics.LoadShader(name, fmt.Sprintf("%s/%s", GetCwd(), vert), fmt.Sprintf("%s/%s", GetCwd(), frag), layout)
+	}
+	_, vert, _ := graphics.BuiltinShader(graphics.ShaderLayout_Sprite)
+	for name, frag := range graphics.BuiltinPostShaders() {
+		graphics.LoadShader(name, fmt.Sprintf("%s/%s", GetCwd(), vert), fmt.Sprintf("%s/%s", GetCwd(), frag), graphics.ShaderLayout_Sprite)
+	}
 	graphics.GLNewShader("noshader", 0, graphics.GLNewVAO(1), nil)
 }
 
@@ -130,9 +124,9 @@ func RenderLoop(window *glfw.Window, renderFunc func(), sigKill <-chan struct{})
 	fmt.Println("[System] renderLoop entered")
 
 	defer glfw.Terminate()
-	gl.ClearColor(0.5, 0.5, 1, 1)
 
 	vboBufferCheckTimestamp := time.Now()
+	shaderCheckTimestamp := time.Now()
 
 	for !window.ShouldClose() {
 
@@ -143,17 +137,37 @@ func RenderLoop(window *glfw.Window, renderFunc func(), sigKill <-chan struct{})
 		default:
 		}
 		// Do OpenGL stuff.
-		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
-
-		// ---- exec pipeline cmd ----
-		renderFunc()
+		graphics.ClearScreen()
 		for _, gfxsys := range gfxSystemPriorityList {
-			gfxsys.Execute(app.executor)
+			if pre, ok := gfxsys.(base.IPreRenderSystem); ok {
+				pre.PreRender()
+			}
+		}
+
+		// ---- exec pipeline cmd, once for each camera ----
+		for _, cam := range graphics.GetRenderingCameras() {
+			graphics.BeginCamera(cam)
+			for _, gfxsys := range gfxSystemPriorityList {
+				if pre, ok := gfxsys.(base.ICameraRenderSystem); ok {
+					pre.PreRenderCamera()
+				}
+			}
+			renderFunc()
+			for _, gfxsys := range gfxSystemPriorityList {
+				gfxsys.Execute(app.executor)
+			}
+			if cam == graphics.GetCurrentCamera() {
+				executeAdditionalDrawCalls()
+			}
+			graphics.EndCamera()
 		}
+		// draw calls are still executed if the current camera is disabled, so that they do not pile up.
 		executeAdditionalDrawCalls()
 
 		// ---- check buffer status ----
 		tryAddVboStock(&vboBufferCheckTimestamp)
+		// ---- check modified shaders ----
+		tryReloadShaders(&shaderCheckTimestamp)
 
 		window.SwapBuffers()
 		glfw.PollEvents()
@@ -182,3 +196,12 @@ func tryAddVboStock(vboBufferCheckTimestamp *time.Time) {
 		}
 	}
 }
+
+// tryReloadShaders recompiles shaders modified on disk once a second if hot reloading is enabled,
+// which stats every shader file. Programs could only be compiled on rendering thread.
+func tryReloadShaders(shaderCheckTimestamp *time.Time) {
+	if time.Since(*shaderCheckTimestamp) > time.Second {
+		*shaderCheckTimestamp = time.Now()
+		graphics.ReloadModifiedShaders()
+	}
+}
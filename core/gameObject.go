@@ -2,6 +2,7 @@ package core
 
 import (
 	"galaxyzeta.io/engine/base"
+	"galaxyzeta.io/engine/ecs/component"
 	"galaxyzeta.io/engine/infra"
 )
 
@@ -9,6 +10,9 @@ import (
 func doCreate(constructor func() base.IGameObject2D, isActive *bool) base.IGameObject2D {
 	obj := constructor()
 	obj.Obj().SetIGameObject2D(obj)
+	if tf := transformOf(obj); tf != nil {
+		tf.SetIGameObject2D(obj)
+	}
 	app.registerChannel <- resourceAccessRequest{
 		payload:  obj,
 		isActive: isActive,
@@ -21,6 +25,13 @@ func doDestroy(obj base.IGameObject2D, isActive *bool) {
 	if obj2d.Callbacks.OnDestroy != nil {
 		obj2d.Callbacks.OnDestroy(obj)
 	}
+	// the destroyed object leaves the hierarchy, children left become roots in place.
+	if tf := transformOf(obj); tf != nil {
+		tf.SetParent(nil)
+		for _, child := range tf.GetChildren() {
+			child.SetParent(nil)
+		}
+	}
 	app.unregisterChannel <- resourceAccessRequest{
 		payload:  obj,
 		isActive: isActive,
@@ -45,11 +56,41 @@ func CreateInactive(constructor func() base.IGameObject2D) base.IGameObject2D {
 
 // Destroy will deconstruct an active/inactive object immediately.
 // The object will be truely removed from resource pool in the next physical tick.
+// Children of the object are detached and kept in place.
 func Destroy(obj base.IGameObject2D) {
 	doDestroy(obj, nil)
 }
 
-//
+// DestroyWithChildren destroys the object and all its descendants in the transform hierarchy, children first.
+func DestroyWithChildren(obj base.IGameObject2D) {
+	for _, child := range childrenOf(obj) {
+		DestroyWithChildren(child)
+	}
+	doDestroy(obj, nil)
+}
+
+// transformOf returns the transform of an object, nil if it has none.
+func transformOf(iobj base.IGameObject2D) *component.Transform2D {
+	if tf, ok := iobj.Obj().TryGetComponent(component.NameTransform2D); ok {
+		return tf.(*component.Transform2D)
+	}
+	return nil
+}
+
+// childrenOf returns objects owning direct children of the transform of an object.
+func childrenOf(iobj base.IGameObject2D) (ret []base.IGameObject2D) {
+	tf := transformOf(iobj)
+	if tf == nil {
+		return nil
+	}
+	for _, child := range tf.GetChildren() {
+		if owner := child.I(); owner != nil {
+			ret = append(ret, owner)
+		}
+	}
+	return ret
+}
+
 func GetIGameobjects() (ret []base.IGameObject2D) {
 	mu := mutexList[Mutex_ActivePool]
 	mu.RLock()
@@ -75,8 +116,12 @@ func GetIGameobjectsMap() (ret map[base.IGameObject2D]struct{}) {
 	return ret
 }
 
-// Activate an object from deactive list, if it exists in it.
+// Activate an object from deactive list, if it exists in it. Its descendants are activated as well.
+// The return value only tells whether the object itself was activated.
 func Activate(iobj base.IGameObject2D) bool {
+	for _, child := range childrenOf(iobj) {
+		Activate(child)
+	}
 	if ContainsInactiveDefault(iobj) {
 		doActivate(iobj)
 		delete(inactivePool[Label_Default], iobj)
@@ -88,8 +133,12 @@ func Activate(iobj base.IGameObject2D) bool {
 	return false
 }
 
-// Deactivate an object from active list, if it exists in it.
+// Deactivate an object from active list, if it exists in it. Its descendants are deactivated as well.
+// The return value only tells whether the object itself was deactivated.
 func Deactivate(iobj base.IGameObject2D) bool {
+	for _, child := range childrenOf(iobj) {
+		Deactivate(child)
+	}
 	if ContainsActiveDefault(iobj) {
 		doDeactivate(iobj)
 		delete(activePool[Label_Default], iobj)
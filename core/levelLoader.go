@@ -3,7 +3,10 @@ package core
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"galaxyzeta.io/engine/base"
+	"galaxyzeta.io/engine/collision"
 	"galaxyzeta.io/engine/ecs/component"
 	"galaxyzeta.io/engine/ecs/system"
 	"galaxyzeta.io/engine/graphics"
@@ -15,6 +18,84 @@ import (
 var worldMeta *parser.LevelConfig
 var objName2Ctor map[string]string = make(map[string]string)
 
+const (
+	CollisionSystemQuadTree    = "quadtree"
+	CollisionSystemSpatialHash = "spatial-hash"
+)
+
+var animConditionModes = map[string]graphics.AnimConditionMode{
+	"":        graphics.AnimCondition_If,
+	"if":      graphics.AnimCondition_If,
+	"if-not":  graphics.AnimCondition_IfNot,
+	"greater": graphics.AnimCondition_Greater,
+	"less":    graphics.AnimCondition_Less,
+}
+
+var playModes = map[string]graphics.PlayMode{
+	"":              graphics.PlayMode_Loop,
+	"loop":          graphics.PlayMode_Loop,
+	"once":          graphics.PlayMode_Once,
+	"ping-pong":     graphics.PlayMode_PingPong,
+	"clamp-forever": graphics.PlayMode_ClampForever,
+}
+
+var pointForceModes = map[string]component.PointForceMode{
+	"":                component.PointForceMode_Constant,
+	"constant":        component.PointForceMode_Constant,
+	"inverse-linear":  component.PointForceMode_InverseLinear,
+	"inverse-squared": component.PointForceMode_InverseSquared,
+}
+
+var shaderLayouts = map[string]graphics.ShaderLayout{
+	"":       graphics.ShaderLayout_Sprite,
+	"sprite": graphics.ShaderLayout_Sprite,
+	"color":  graphics.ShaderLayout_Color,
+}
+
+var cameraFollowModes = map[string]graphics.CameraFollowMode{
+	"":            graphics.CameraFollow_SmoothDamp,
+	"smooth-damp": graphics.CameraFollow_SmoothDamp,
+	"lerp":        graphics.CameraFollow_Lerp,
+	"instant":     graphics.CameraFollow_Instant,
+}
+
+var cameraTicks = map[string]graphics.CameraTick{
+	"":        graphics.CameraTick_Physics,
+	"physics": graphics.CameraTick_Physics,
+	"render":  graphics.CameraTick_Render,
+}
+
+var scaleModes = map[string]graphics.ScaleMode{
+	"":          graphics.ScaleMode_Stretch,
+	"stretch":   graphics.ScaleMode_Stretch,
+	"letterbox": graphics.ScaleMode_Letterbox,
+	"integer":   graphics.ScaleMode_Integer,
+	"expand":    graphics.ScaleMode_Expand,
+}
+
+var postEffectCtors = map[string]func(name string) graphics.IPostEffect{
+	"color-grading": func(name string) graphics.IPostEffect { return graphics.NewColorGradingEffect(name) },
+	"vignette":      func(name string) graphics.IPostEffect { return graphics.NewVignetteEffect(name) },
+	"bloom":         func(name string) graphics.IPostEffect { return graphics.NewBloomEffect(name) },
+	"scanlines":     func(name string) graphics.IPostEffect { return graphics.NewScanlinesEffect(name) },
+	"pixelate":      func(name string) graphics.IPostEffect { return graphics.NewPixelateEffect(name) },
+	"flash":         func(name string) graphics.IPostEffect { return graphics.NewFlashEffect(name) },
+}
+
+var emitterShapes = map[string]component.EmitterShape{
+	"":          component.EmitterShape_Point,
+	"point":     component.EmitterShape_Point,
+	"circle":    component.EmitterShape_Circle,
+	"rectangle": component.EmitterShape_Rectangle,
+	"edge":      component.EmitterShape_Edge,
+}
+
+var simulationSpaces = map[string]component.SimulationSpace{
+	"":      component.SimulationSpace_World,
+	"world": component.SimulationSpace_World,
+	"local": component.SimulationSpace_Local,
+}
+
 // NewApplicationFromFile creates a new application from given level definition XML file.
 // Not concurrently safe, no need to create multiple applications at same time.
 func NewApplicationFromFile(filePath string) *Application {
@@ -22,20 +103,90 @@ func NewApplicationFromFile(filePath string) *Application {
 	cwd = GetCwd()
 
 	initializer := func() {
-		// load static frames
 		staticPath := worldMeta.LevelMetas.Static
+		// name layers, which are referred by renderers and cameras
+		for _, layerMeta := range worldMeta.LevelMetas.Layers.Layers {
+			graphics.RegisterLayer(layerMeta.Name, layerMeta.Index)
+		}
+		for _, sortingLayerMeta := range worldMeta.LevelMetas.SortingLayers.SortingLayers {
+			graphics.RegisterSortingLayer(sortingLayerMeta.Name, sortingLayerMeta.Order)
+		}
+		// load shaders and materials
+		shaderMetas := worldMeta.LevelMetas.ShaderMetas
+		for _, shaderMeta := range shaderMetas.Shaders {
+			layout, ok := shaderLayouts[shaderMeta.Layout]
+			if !ok {
+				panic(fmt.Sprintf("unknown shader layout: %s", shaderMeta.Layout))
+			}
+			_, vert, frag := graphics.BuiltinShader(layout)
+			vert, frag = fmt.Sprintf("%s/%s", cwd, vert), fmt.Sprintf("%s/%s", cwd, frag)
+			if shaderMeta.Vertex != "" {
+				vert = fmt.Sprintf("%s/%s/%s", cwd, staticPath, shaderMeta.Vertex)
+			}
+			if shaderMeta.Fragment != "" {
+				frag = fmt.Sprintf("%s/%s/%s", cwd, staticPath, shaderMeta.Fragment)
+			}
+			graphics.LoadShader(shaderMeta.Name, vert, frag, layout)
+		}
+		for _, materialMeta := range shaderMetas.Materials {
+			graphics.RegisterMaterial(materialMeta.Name, newMaterial(&materialMeta))
+		}
+		graphics.SetShaderHotReload(shaderMetas.HotReload)
+		// load static frames
 		for _, dir := range worldMeta.LevelMetas.FrameMetas.Dirs {
 			graphics.BatchNewFrames(fmt.Sprintf("%s/%s/%s", cwd, staticPath, dir.Name), func(fileName string) string {
 				return fmt.Sprintf("%s%s", dir.Prefix, strings.Split(fileName, ".")[0])
 			})
 		}
+		// load packed frames
+		for _, atlas := range worldMeta.LevelMetas.FrameMetas.Atlases {
+			graphics.LoadAtlas(fmt.Sprintf("%s/%s/%s", cwd, staticPath, atlas.File), func(frameName string) string {
+				return fmt.Sprintf("%s%s", atlas.Prefix, frameName)
+			})
+		}
+		// slice sprite sheets
+		for _, sheet := range worldMeta.LevelMetas.FrameMetas.Sheets {
+			graphics.NewSheetFrames(fmt.Sprintf("%s/%s/%s", cwd, staticPath, sheet.File), newSheetGrids(sheet.Grids), newSheetRegions(sheet.Regions), func(frameName string) string {
+				return fmt.Sprintf("%s%s", sheet.Prefix, frameName)
+			})
+		}
+		// import aseprite exports, sprites are created as well
+		for _, ase := range worldMeta.LevelMetas.FrameMetas.Aseprites {
+			graphics.LoadAseprite(fmt.Sprintf("%s/%s/%s", cwd, staticPath, ase.File), ase.FramePrefix, ase.SpritePrefix)
+		}
+		// author boxes of frames
+		for _, boxes := range worldMeta.LevelMetas.FrameMetas.Boxes {
+			if boxes.File != "" {
+				graphics.LoadFrameBoxes(fmt.Sprintf("%s/%s/%s", cwd, staticPath, boxes.File), func(frameName string) string {
+					return fmt.Sprintf("%s%s", boxes.Prefix, frameName)
+				})
+			}
+			for _, frame := range boxes.Frames {
+				graphics.SetFrameBoxes(fmt.Sprintf("%s%s", boxes.Prefix, frame.Name), newFrameBoxes(&frame))
+			}
+		}
 		// register sprites
 		for _, spriteMeta := range worldMeta.LevelMetas.SpriteMetas.Sprites {
 			framesCandidates := make([]string, 0)
 			for _, frameCandidate := range spriteMeta.Frames {
 				framesCandidates = append(framesCandidates, frameCandidate.Name)
 			}
-			graphics.NewSpriteMeta(spriteMeta.Name, framesCandidates...)
+			graphics.NewSpriteMetaWithOptions(spriteMeta.Name, framesCandidates, newSpriteMetaOptions(&spriteMeta))
+		}
+		// load fonts
+		for _, fontMeta := range worldMeta.LevelMetas.FontMetas.BMFonts {
+			graphics.RegisterFont(fontMeta.Name, graphics.LoadBMFont(fmt.Sprintf("%s/%s/%s", cwd, staticPath, fontMeta.File)))
+		}
+		for _, fontMeta := range worldMeta.LevelMetas.FontMetas.TrueTypes {
+			graphics.RegisterFont(fontMeta.Name, graphics.LoadTrueTypeFont(fmt.Sprintf("%s/%s/%s", cwd, staticPath, fontMeta.File), fontMeta.Size, fontMeta.Runes))
+		}
+		// register animation state machines
+		for _, animatorMeta := range worldMeta.LevelMetas.AnimatorMetas.Animators {
+			graphics.RegisterAnimStateMachine(animatorMeta.Name, newAnimStateMachine(&animatorMeta))
+		}
+		// register particle emitter configs
+		for _, emitterMeta := range worldMeta.LevelMetas.ParticleMetas.Emitters {
+			component.RegisterParticleEmitterConfig(emitterMeta.Name, newParticleEmitterConfig(&emitterMeta))
 		}
 		// build object name-src relation map
 		for _, objectMeta := range worldMeta.LevelMetas.ObjectMetas.Objects {
@@ -43,12 +194,42 @@ func NewApplicationFromFile(filePath string) *Application {
 		}
 		// create systems
 		// TODO only load user defined systems
-		csys := system.NewQuadTreeCollision2DSystem(0, physics.NewRectangle(0, 0, 1024, 1024), 4, 64)
+		csys := newCollisionSystem(worldMeta.LevelMetas.CollisionSystem)
 		RegisterSystem(csys)
-		RegisterSystem(system.NewPhysics2DSystem(1, csys))
+		physicsSys := system.NewPhysics2DSystem(1, csys)
+		steppedSystems := []interface {
+			base.ISystem
+			SetTimeStep(dt float64)
+		}{
+			physicsSys,
+			system.NewCharacterController2DSystem(2, csys),
+			system.NewAnimation2DSystem(3),
+			system.NewParticle2DSystem(4, csys),
+			// cameras follow targets after they are moved.
+			system.NewCamera2DSystem(-1, graphics.CameraTick_Physics),
+		}
+		// systems step by the default time step if the physics fps is not declared.
+		var dt float64
+		if fps := worldMeta.LevelMetas.ApplicationMetas.FPS.Physics; fps > 0 {
+			dt = 1 / float64(fps)
+		}
+		for _, sys := range steppedSystems {
+			if dt > 0 {
+				sys.SetTimeStep(dt)
+			}
+			RegisterSystem(sys)
+		}
+		RegisterGfxSystem(system.NewCamera2DSystem(1, graphics.CameraTick_Render))
 		RegisterGfxSystem(system.NewRenderer2DSystem(0))
-		// create cameras
-		graphics.InitCameraPool(worldMeta.LevelMetas.CameraCount)
+		RegisterGfxSystem(system.NewPostProcess2DSystem(-1))
+		RegisterGfxSystem(system.NewPhysicsDebug2DSystem(-2, physicsSys))
+		// create cameras, which view the whole window if the virtual resolution is not declared.
+		appMetas := worldMeta.LevelMetas.ApplicationMetas
+		virtual := linalg.NewVector2f64(appMetas.VirtualResolution.W, appMetas.VirtualResolution.H)
+		if virtual.X <= 0 || virtual.Y <= 0 {
+			virtual = linalg.NewVector2f64(appMetas.Resolution.W, appMetas.Resolution.H)
+		}
+		graphics.InitCameraPool(worldMeta.LevelMetas.CameraCount, virtual)
 		// register scenes
 		for _, scene := range worldMeta.LevelDetails.Scene {
 			registerScene(scene.SceneName, &scene)
@@ -59,8 +240,13 @@ func NewApplicationFromFile(filePath string) *Application {
 
 	// start application
 	appCfg := worldMeta.LevelMetas.ApplicationMetas
+	scaleMode, ok := scaleModes[appCfg.ScaleMode]
+	if !ok {
+		panic(fmt.Sprintf("unknown scale mode: %s", appCfg.ScaleMode))
+	}
 	return NewApplication(&AppConfig{
 		Resolution:  linalg.NewVector2f64Ptr(appCfg.Resolution.W, appCfg.Resolution.H),
+		ScaleMode:   scaleMode,
 		PhysicalFps: appCfg.FPS.Physics,
 		RenderFps:   appCfg.FPS.Render,
 		Parallelism: appCfg.Parallelism,
@@ -69,7 +255,265 @@ func NewApplicationFromFile(filePath string) *Application {
 	})
 }
 
+// newSpriteMetaOptions converts play mode, durations and events of a sprite declared in level metas.
+func newSpriteMetaOptions(meta *parser.Sprite) graphics.SpriteMetaOptions {
+	mode, ok := playModes[meta.Mode]
+	if !ok {
+		panic(fmt.Sprintf("unknown play mode: %s", meta.Mode))
+	}
+	opts := graphics.SpriteMetaOptions{
+		Mode:    mode,
+		Reverse: meta.Reverse,
+	}
+	for idx, frame := range meta.Frames {
+		if frame.Duration > 0 {
+			if opts.Durations == nil {
+				opts.Durations = make([]time.Duration, len(meta.Frames))
+			}
+			opts.Durations[idx] = time.Duration(frame.Duration) * time.Millisecond
+		}
+		if frame.Event == "" {
+			continue
+		}
+		if opts.Events == nil {
+			opts.Events = make(map[int][]string)
+		}
+		for _, event := range strings.Split(frame.Event, ",") {
+			opts.Events[idx] = append(opts.Events[idx], strings.TrimSpace(event))
+		}
+	}
+	return opts
+}
+
+// newAnimStateMachine builds an animation state machine declared in level metas.
+func newAnimStateMachine(meta *parser.AnimatorMeta) *graphics.AnimStateMachine {
+	machine := graphics.NewAnimStateMachine()
+	for _, state := range meta.States {
+		speed := 1.0
+		if state.Speed != nil {
+			speed = *state.Speed
+		}
+		machine.AddState(state.Name, state.Sprite, speed)
+	}
+	if meta.Entry != "" {
+		machine.SetEntry(meta.Entry)
+	}
+	for _, param := range meta.Params.Bools {
+		machine.AddBool(param.Name, param.Default)
+	}
+	for _, param := range meta.Params.Floats {
+		machine.AddFloat(param.Name, param.Default)
+	}
+	for _, param := range meta.Params.Triggers {
+		machine.AddTrigger(param.Name)
+	}
+	for _, t := range meta.Transitions {
+		transition := graphics.AnimTransition{
+			From:     t.From,
+			To:       t.To,
+			Priority: t.Priority,
+			ToSelf:   t.ToSelf,
+		}
+		if t.ExitTime != nil {
+			transition.HasExitTime = true
+			transition.ExitTime = *t.ExitTime
+		}
+		for _, cond := range t.Conditions {
+			mode, ok := animConditionModes[cond.Mode]
+			if !ok {
+				panic(fmt.Sprintf("unknown animation condition mode: %s", cond.Mode))
+			}
+			transition.Conditions = append(transition.Conditions, graphics.AnimCondition{
+				Param:     cond.Param,
+				Mode:      mode,
+				Threshold: cond.Threshold,
+			})
+		}
+		machine.AddTransition(transition)
+	}
+	return machine
+}
+
+// newFrameBoxes converts boxes of a frame declared in level metas, the hitbox is left nil to be generated if absent.
+func newFrameBoxes(meta *parser.FrameBoxesMeta) (ret graphics.FrameBoxes) {
+	if meta.Hitbox != nil {
+		ret.Hitbox = parser.MustParsePointList(meta.Hitbox.Points)
+	}
+	for _, hurtbox := range meta.Hurtboxes {
+		ret.Hurtboxes = append(ret.Hurtboxes, parser.MustParsePointList(hurtbox.Points))
+	}
+	return ret
+}
+
+// newMaterial converts a material declared in level metas.
+func newMaterial(meta *parser.MaterialMeta) *graphics.Material {
+	// fails early if the shader is not declared.
+	graphics.GetShader(meta.Shader)
+	material := graphics.NewMaterial(meta.Shader)
+	if meta.Tint != "" {
+		material.Tint = parser.MustParseColor(meta.Tint)
+	}
+	for _, uniform := range meta.Uniforms {
+		material.SetUniform(uniform.Name, parser.MustParseFloatList(uniform.Value)...)
+	}
+	return material
+}
+
+// newParticleEmitterConfig converts a particle emitter declared in level metas, attributes not declared keep defaults.
+func newParticleEmitterConfig(meta *parser.EmitterMeta) component.ParticleEmitterConfig {
+	cfg := component.NewParticleEmitterConfig(meta.Sprite)
+	if meta.MaxParticles != nil {
+		cfg.MaxParticles = *meta.MaxParticles
+	}
+	if meta.Duration != nil {
+		cfg.Duration = *meta.Duration
+	}
+	if meta.Loop != nil {
+		cfg.Loop = *meta.Loop
+	}
+	if meta.Rate != nil {
+		cfg.Rate = *meta.Rate
+	}
+	for _, burst := range meta.Bursts {
+		cfg.Bursts = append(cfg.Bursts, component.ParticleBurst{Time: burst.Time, Count: burst.Count})
+	}
+	shape, ok := emitterShapes[meta.Shape]
+	if !ok {
+		panic(fmt.Sprintf("unknown emitter shape: %s", meta.Shape))
+	}
+	cfg.Shape = shape
+	cfg.Radius = meta.Radius
+	if meta.Size != "" {
+		cfg.Size = parser.MustParseNumericStringTuple(meta.Size)
+	}
+	if meta.Direction != nil {
+		cfg.Direction = *meta.Direction
+	}
+	if meta.Spread != nil {
+		cfg.Spread = *meta.Spread
+	}
+	if meta.Speed != "" {
+		speed := parser.MustParseNumericStringTuple(meta.Speed)
+		cfg.SpeedMin, cfg.SpeedMax = speed.X, speed.Y
+	}
+	if meta.Lifetime != "" {
+		lifetime := parser.MustParseNumericStringTuple(meta.Lifetime)
+		cfg.LifetimeMin, cfg.LifetimeMax = lifetime.X, lifetime.Y
+	}
+	if meta.StartSize != "" {
+		size := parser.MustParseNumericStringTuple(meta.StartSize)
+		cfg.StartSizeMin, cfg.StartSizeMax = size.X, size.Y
+	}
+	if meta.Gravity != "" {
+		cfg.Gravity = parser.MustParseNumericStringTuple(meta.Gravity)
+	}
+	for _, key := range meta.SpeedCurve {
+		cfg.SpeedOverLifetime = append(cfg.SpeedOverLifetime, linalg.CurveKey{T: key.T, V: key.V})
+	}
+	cfg.SpeedOverLifetime = linalg.NewCurve(cfg.SpeedOverLifetime...)
+	for _, key := range meta.SizeCurve {
+		cfg.SizeOverLifetime = append(cfg.SizeOverLifetime, linalg.CurveKey{T: key.T, V: key.V})
+	}
+	cfg.SizeOverLifetime = linalg.NewCurve(cfg.SizeOverLifetime...)
+	for _, key := range meta.ColorGradient {
+		cfg.ColorOverLifetime = append(cfg.ColorOverLifetime, linalg.GradientKey{T: key.T, Color: parser.MustParseColor(key.Color)})
+	}
+	cfg.ColorOverLifetime = linalg.NewGradient(cfg.ColorOverLifetime...)
+	space, ok := simulationSpaces[meta.Space]
+	if !ok {
+		panic(fmt.Sprintf("unknown simulation space: %s", meta.Space))
+	}
+	cfg.Space = space
+	cfg.Collide = meta.Collide
+	if meta.Bounce != nil {
+		cfg.Bounce = *meta.Bounce
+	}
+	cfg.KillOnCollision = meta.KillOnCollision
+	if meta.SolidTag != "" {
+		cfg.SolidTag = meta.SolidTag
+	}
+	return cfg
+}
+
+// newSheetGrids converts grids declared in level metas.
+func newSheetGrids(metas []parser.SheetGrid) []graphics.SheetGrid {
+	ret := make([]graphics.SheetGrid, len(metas))
+	for idx, meta := range metas {
+		if meta.Cell == "" {
+			panic(fmt.Sprintf("cell size of sheet grid %s is not declared", meta.Name))
+		}
+		cell := parser.MustParseNumericStringTuple(meta.Cell)
+		var margin, spacing linalg.Vector2f64
+		if meta.Margin != "" {
+			margin = parser.MustParseNumericStringTuple(meta.Margin)
+		}
+		if meta.Spacing != "" {
+			spacing = parser.MustParseNumericStringTuple(meta.Spacing)
+		}
+		ret[idx] = graphics.SheetGrid{
+			Name:     meta.Name,
+			CellW:    int(cell.X),
+			CellH:    int(cell.Y),
+			MarginX:  int(margin.X),
+			MarginY:  int(margin.Y),
+			SpacingX: int(spacing.X),
+			SpacingY: int(spacing.Y),
+			Count:    meta.Count,
+			Start:    meta.Start,
+		}
+	}
+	return ret
+}
+
+// newSheetRegions converts named regions declared in level metas.
+func newSheetRegions(metas []parser.SheetRegion) []graphics.AtlasRegion {
+	ret := make([]graphics.AtlasRegion, len(metas))
+	for idx, meta := range metas {
+		ret[idx] = graphics.AtlasRegion{Name: meta.Name, X: meta.X, Y: meta.Y, W: meta.W, H: meta.H}
+	}
+	return ret
+}
+
+// newCollisionSystem creates the broadphase declared in level metas, a quadtree is used if not declared.
+func newCollisionSystem(meta parser.CollisionSystem) collision.ICollisionSystem {
+	switch meta.Type {
+	case "", CollisionSystemQuadTree:
+		area := physics.NewRectangle(meta.Area.X, meta.Area.Y, meta.Area.W, meta.Area.H)
+		if area.Width <= 0 || area.Height <= 0 {
+			area = physics.NewRectangle(0, 0, 1024, 1024)
+		}
+		loadFactor := meta.LoadFactor
+		if loadFactor <= 0 {
+			loadFactor = 4
+		}
+		minDivision := meta.MinDivision
+		if minDivision <= 0 {
+			minDivision = 64
+		}
+		return system.NewQuadTreeCollision2DSystem(0, area, loadFactor, minDivision)
+	case CollisionSystemSpatialHash:
+		return system.NewSpatialHashCollision2DSystem(0, meta.CellSize.W, meta.CellSize.H)
+	}
+	panic(fmt.Sprintf("unknown collision system type: %s", meta.Type))
+}
+
 func doSceneLoad(scene *parser.Scene) {
+	// targets and bounds belong to the scene, so cameras are set up before objects of the scene start following.
+	roomSize := scene.SceneMetas.RoomSize
+	for idx := 0; idx < graphics.GetCameraCount(); idx++ {
+		controller := graphics.GetCamera(idx).Controller()
+		controller.Follow(nil)
+		if roomSize.W > 0 && roomSize.H > 0 {
+			controller.SetBounds(linalg.NewRect(0, 0, roomSize.W, roomSize.H))
+		} else {
+			controller.ClearBounds()
+		}
+	}
+	for _, cam := range scene.SceneMetas.Cameras.Cameras {
+		if cam.Controller != nil {
+			applyCameraController(graphics.GetCamera(cam.Index).Controller(), cam.Controller)
+		}
+	}
 	// create objects in level
 	for _, obj := range scene.ObjectDetails.Objects {
 		ctor, ok := objName2Ctor[obj.Name]
@@ -81,13 +525,245 @@ func doSceneLoad(scene *parser.Scene) {
 			panic("failed to find mapping between the object being initialized and constructor map.")
 		}
 
-		tf := Create(invoker).Obj().GetComponent(component.NameTransform2D).(*component.Transform2D)
-		tf.Pos.X = float64(obj.X)
-		tf.Pos.Y = float64(obj.Y)
+		obj := obj
+		// details are applied before the object is put into systems.
+		Create(func() base.IGameObject2D {
+			iobj := invoker()
+			tf := iobj.Obj().GetComponent(component.NameTransform2D).(*component.Transform2D)
+			tf.Pos.X = float64(obj.X)
+			tf.Pos.Y = float64(obj.Y)
+			tf.Rotation = obj.Rotation
+			if obj.Scale != "" {
+				tf.Scale = parser.MustParseNumericStringTuple(obj.Scale)
+			}
+			applyObjectDetail(iobj, &obj)
+			return iobj
+		})
+	}
+	// backgrounds are objects of the scene, so they are destroyed with others when the scene is unloaded.
+	for _, parallaxMeta := range scene.SceneMetas.Parallaxes {
+		background := newParallaxBackground(&parallaxMeta)
+		Create(func() base.IGameObject2D {
+			return newSceneBackground(background)
+		})
 	}
 	// set camera pos
 	for _, cam := range scene.SceneMetas.Cameras.Cameras {
-		graphics.GetCamera(cam.Index).SetPos(cam.X, cam.Y)
+		camera := graphics.GetCamera(cam.Index)
+		camera.SetPos(cam.X, cam.Y)
+		zoom := cam.Zoom
+		if zoom <= 0 {
+			zoom = 1
+		}
+		camera.SetZoom(zoom)
+		camera.SetRotation(cam.Rotation)
+		camera.SetOrder(cam.Order)
+		viewport := linalg.NewRect(0, 0, 1, 1)
+		if cam.Viewport != "" {
+			v := parser.MustParseFloatList(cam.Viewport)
+			if len(v) != 4 {
+				panic("viewport should contain 4 components")
+			}
+			viewport = linalg.NewRect(v[0], v[1], v[2], v[3])
+		}
+		camera.SetViewportRect(viewport)
+		camera.SetLayerMask(mustParseLayerMask(cam.Layers))
+		if cam.Enabled != nil {
+			camera.SetEnabled(*cam.Enabled)
+		}
+	}
+	// effects belong to the scene, so chains of the last scene are dropped.
+	for idx := 0; idx < graphics.GetCameraCount(); idx++ {
+		graphics.GetCamera(idx).PostProcessor().Clear()
+	}
+	for _, chain := range scene.SceneMetas.PostProcesses {
+		processor := graphics.GetCamera(chain.Camera).PostProcessor()
+		for _, effectMeta := range chain.Effects {
+			processor.Add(newPostEffect(&effectMeta))
+		}
+	}
+}
+
+// mustParseLayerMask parses comma separated names of layers into a mask, every layer is contained if names is empty or "all".
+func mustParseLayerMask(names string) uint32 {
+	if names == "" || names == "all" {
+		return graphics.LayerMaskAll
+	}
+	var layers []int
+	for _, name := range strings.Split(names, ",") {
+		layers = append(layers, graphics.GetLayer(strings.TrimSpace(name)))
+	}
+	return graphics.LayerMask(layers...)
+}
+
+// applyCameraController overrides settings of a controller by declared attributes.
+func applyCameraController(controller *graphics.CameraController, meta *parser.CameraControllerMeta) {
+	mode, ok := cameraFollowModes[meta.Mode]
+	if !ok {
+		panic(fmt.Sprintf("unknown camera follow mode: %s", meta.Mode))
+	}
+	tick, ok := cameraTicks[meta.Tick]
+	if !ok {
+		panic(fmt.Sprintf("unknown camera tick: %s", meta.Tick))
+	}
+	controller.Mode = mode
+	controller.Tick = tick
+	if meta.SmoothTime > 0 {
+		controller.SmoothTime = meta.SmoothTime
+	}
+	if meta.LerpSpeed > 0 {
+		controller.LerpSpeed = meta.LerpSpeed
+	}
+	if meta.Offset != "" {
+		controller.Offset = parser.MustParseNumericStringTuple(meta.Offset)
+	}
+	if meta.Deadzone != "" {
+		controller.Deadzone = parser.MustParseNumericStringTuple(meta.Deadzone)
+	}
+	if meta.LookAhead > 0 {
+		controller.LookAhead = meta.LookAhead
+	}
+	if meta.LookAheadMax != "" {
+		controller.LookAheadMax = parser.MustParseNumericStringTuple(meta.LookAheadMax)
+	}
+	if meta.MaxShake != "" {
+		controller.MaxShake = parser.MustParseNumericStringTuple(meta.MaxShake)
+	}
+	if meta.TraumaDecay > 0 {
+		controller.TraumaDecay = meta.TraumaDecay
+	}
+}
+
+// sceneBackground is an object carrying a parallax background declared in scene metas.
+type sceneBackground struct {
+	*base.GameObject2D
+}
+
+func newSceneBackground(background *component.ParallaxBackground) *sceneBackground {
+	this := &sceneBackground{GameObject2D: base.NewGameObject2D("parallax")}
+	this.Callbacks.OnStep = func(base.IGameObject2D) {}
+	this.RegisterComponent(background)
+	SubscribeSystem(this, system.NameRenderer2DSystem)
+	return this
+}
+
+func (o *sceneBackground) Obj() *base.GameObject2D {
+	return o.GameObject2D
+}
+
+// newParallaxBackground converts a background declared in scene metas.
+func newParallaxBackground(meta *parser.ParallaxMeta) *component.ParallaxBackground {
+	background := component.NewParallaxBackground()
+	if meta.SortingLayer != "" {
+		background.SortingLayer = graphics.GetSortingLayer(meta.SortingLayer)
+	}
+	if meta.Layer != "" {
+		background.Layer = graphics.GetLayer(meta.Layer)
+	}
+	background.SetZ(meta.Z)
+	for _, layerMeta := range meta.Layers {
+		layer := component.NewParallaxLayer(graphics.GetFrame(layerMeta.Frame))
+		if layerMeta.Scroll != "" {
+			layer.ScrollFactor = parser.MustParseNumericStringTuple(layerMeta.Scroll)
+		}
+		if layerMeta.Offset != "" {
+			layer.Offset = parser.MustParseNumericStringTuple(layerMeta.Offset)
+		}
+		if layerMeta.Scale != "" {
+			layer.Scale = parser.MustParseNumericStringTuple(layerMeta.Scale)
+		}
+		if layerMeta.AutoScroll != "" {
+			layer.AutoScroll = parser.MustParseNumericStringTuple(layerMeta.AutoScroll)
+		}
+		if layerMeta.Tint != "" {
+			layer.Tint = parser.MustParseColor(layerMeta.Tint)
+		}
+		layer.RepeatX, layer.RepeatY = layerMeta.RepeatX, layerMeta.RepeatY
+		background.Layers = append(background.Layers, layer)
+	}
+	return background
+}
+
+// newPostEffect converts an effect declared in scene metas, an effect with a shader is drawn in a single pass of the shader.
+// Effects are named after their types if names are not declared.
+func newPostEffect(meta *parser.PostEffectMeta) graphics.IPostEffect {
+	name := meta.Name
+	if name == "" {
+		name = meta.Type
+	}
+	var effect graphics.IPostEffect
+	if meta.Shader != "" {
+		// fails early if the shader is not declared.
+		graphics.GetShader(meta.Shader)
+		effect = graphics.NewPostEffect(name, meta.Shader)
+	} else {
+		ctor, ok := postEffectCtors[meta.Type]
+		if !ok {
+			panic(fmt.Sprintf("unknown post effect type: %s", meta.Type))
+		}
+		effect = ctor(name)
+	}
+	material := effect.GetMaterial()
+	if meta.Tint != "" {
+		material.Tint = parser.MustParseColor(meta.Tint)
+	}
+	for _, uniform := range meta.Uniforms {
+		material.SetUniform(uniform.Name, parser.MustParseFloatList(uniform.Value)...)
+	}
+	if meta.Enabled != nil {
+		effect.SetEnabled(*meta.Enabled)
+	}
+	return effect
+}
+
+// applyObjectDetail makes the collider one-way and attaches effectors, as declared in the scene.
+func applyObjectDetail(iobj base.IGameObject2D, detail *parser.ObjectDetail) {
+	obj := iobj.Obj()
+	if detail.OneWay != nil {
+		normal := linalg.Vector2f64{}
+		if detail.OneWay.Normal != "" {
+			normal = parser.MustParseNumericStringTuple(detail.OneWay.Normal)
+		}
+		obj.GetComponent(component.NameCollider).(*component.Collider).SetOneWay(normal)
+	}
+
+	effectors := detail.Effectors
+	if e := effectors.Conveyor; e != nil {
+		conveyor := component.NewConveyorEffector2D(e.Speed)
+		if e.ForceScale != nil {
+			conveyor.ForceScale = *e.ForceScale
+		}
+		obj.RegisterComponent(conveyor)
+	}
+	if e := effectors.Bounce; e != nil {
+		obj.RegisterComponent(component.NewBounceEffector2D(e.Speed))
+	}
+	if e := effectors.Buoyancy; e != nil {
+		buoyancy := component.NewBuoyancyEffector2D(e.Density)
+		if e.LinearDrag != nil {
+			buoyancy.LinearDrag = *e.LinearDrag
+		}
+		if e.AngularDrag != nil {
+			buoyancy.AngularDrag = *e.AngularDrag
+		}
+		if e.Flow != "" {
+			buoyancy.FlowVelocity = parser.MustParseNumericStringTuple(e.Flow)
+		}
+		obj.RegisterComponent(buoyancy)
+	}
+	if e := effectors.Point; e != nil {
+		mode, ok := pointForceModes[e.Mode]
+		if !ok {
+			panic(fmt.Sprintf("unknown point effector mode: %s", e.Mode))
+		}
+		point := component.NewPointEffector2D(e.Magnitude, mode)
+		if e.DistanceScale != nil {
+			point.DistanceScale = *e.DistanceScale
+		}
+		obj.RegisterComponent(point)
+	}
+	if effectors.Conveyor != nil || effectors.Bounce != nil || effectors.Buoyancy != nil || effectors.Point != nil {
+		SubscribeSystem(iobj, system.NamePhysics2DSystem)
 	}
 }
 
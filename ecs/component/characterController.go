@@ -0,0 +1,178 @@
+package component
+
+import (
+	"galaxyzeta.io/engine/linalg"
+)
+
+const NameCharacterController2D = "CharacterController2D"
+
+// CharacterController2D moves a platformer character by collide-and-slide instead of physical simulation.
+// Set input with Move, Jump and ReleaseJump in OnStep, CharacterController2DSystem will do the rest in the next physics step.
+// All speeds are in pixels per second, and all durations are in seconds.
+type CharacterController2D struct {
+	// ===== configuration =====
+	Gravity           float64
+	MaxFallSpeed      float64
+	JumpSpeed         float64
+	JumpCutMultiplier float64 // vertical speed is multiplied by this when jump is released while ascending.
+	MaxSlopeDeg       float64 // surfaces steeper than this are walls.
+	StepHeight        float64 // obstacles lower than this are stepped over.
+	SkinWidth         float64 // tolerance for one-way platforms and ground snapping.
+	CoyoteTime        float64 // jump is still allowed in this duration after leaving the ground.
+	JumpBufferTime    float64 // jump pressed this earlier before landing is still performed.
+	SolidTag          string  // colliders with this tag block the character, one-way colliders always do from their facing side.
+
+	Velocity linalg.Vector2f64
+
+	// ===== input =====
+	moveX          float64
+	jumpHeld       bool
+	jumpBufferLeft float64
+
+	// ===== state =====
+	grounded     bool
+	onWall       bool
+	wallDir      int
+	onCeiling    bool
+	jumping      bool
+	coyoteLeft   float64
+	groundNormal linalg.Vector2f64
+	ground       *Collider
+	groundAnchor linalg.Vector2f64 // anchor of the ground collider at last step, used to carry the character.
+}
+
+func NewCharacterController2D() *CharacterController2D {
+	return &CharacterController2D{
+		Gravity:           2000,
+		MaxFallSpeed:      600,
+		JumpSpeed:         500,
+		JumpCutMultiplier: 0.5,
+		MaxSlopeDeg:       50,
+		StepHeight:        4,
+		SkinWidth:         1,
+		CoyoteTime:        0.1,
+		JumpBufferTime:    0.1,
+		SolidTag:          "solid",
+	}
+}
+
+// GetName is an implementation of IComponent.
+func (cc *CharacterController2D) GetName() string {
+	return NameCharacterController2D
+}
+
+// Move sets the desired horizontal speed, negative value means moving left.
+func (cc *CharacterController2D) Move(speed float64) {
+	cc.moveX = speed
+}
+
+// Jump should be called when the jump button is pressed. The jump is buffered if not allowed at this moment.
+func (cc *CharacterController2D) Jump() {
+	cc.jumpHeld = true
+	cc.jumpBufferLeft = cc.JumpBufferTime
+}
+
+// ReleaseJump should be called when the jump button is released, which cuts the jump short.
+func (cc *CharacterController2D) ReleaseJump() {
+	cc.jumpHeld = false
+}
+
+func (cc *CharacterController2D) IsGrounded() bool {
+	return cc.grounded
+}
+
+func (cc *CharacterController2D) IsOnWall() bool {
+	return cc.onWall
+}
+
+// GetWallDirection returns -1 if the wall is at left, 1 if at right, 0 if there is no wall.
+func (cc *CharacterController2D) GetWallDirection() int {
+	return cc.wallDir
+}
+
+func (cc *CharacterController2D) IsOnCeiling() bool {
+	return cc.onCeiling
+}
+
+// GetGroundNormal returns the normal of the ground pointing to the character, zero if not grounded.
+func (cc *CharacterController2D) GetGroundNormal() linalg.Vector2f64 {
+	return cc.groundNormal
+}
+
+// GetGround returns the collider beneath the character, nil if not grounded.
+func (cc *CharacterController2D) GetGround() *Collider {
+	return cc.ground
+}
+
+// ===== system only =====
+
+func (cc *CharacterController2D) GetMoveX() float64 {
+	return cc.moveX
+}
+
+func (cc *CharacterController2D) IsJumpHeld() bool {
+	return cc.jumpHeld
+}
+
+// TryConsumeJump starts a jump if it is both buffered and allowed, and ticks the timers. It should only be called by system.
+func (cc *CharacterController2D) TryConsumeJump(dt float64) bool {
+	if cc.grounded {
+		cc.coyoteLeft = cc.CoyoteTime
+	}
+	jumped := false
+	if cc.jumpBufferLeft > 0 && (cc.grounded || cc.coyoteLeft > 0) {
+		cc.Velocity.Y = -cc.JumpSpeed
+		cc.jumpBufferLeft, cc.coyoteLeft = 0, 0
+		cc.jumping = true
+		jumped = true
+	}
+	cc.jumpBufferLeft -= dt
+	cc.coyoteLeft -= dt
+	if cc.jumping && (!cc.jumpHeld || cc.Velocity.Y >= 0) {
+		// variable jump height
+		if cc.Velocity.Y < 0 {
+			cc.Velocity.Y *= cc.JumpCutMultiplier
+		}
+		cc.jumping = false
+	}
+	return jumped
+}
+
+// ResetContacts clears contact flags before moving. It should only be called by system.
+func (cc *CharacterController2D) ResetContacts() {
+	cc.grounded, cc.onWall, cc.onCeiling = false, false, false
+	cc.wallDir = 0
+	cc.groundNormal = linalg.Vector2f64{}
+	cc.ground = nil
+}
+
+// SetGround records the ground. It should only be called by system.
+func (cc *CharacterController2D) SetGround(ground *Collider, normal linalg.Vector2f64) {
+	cc.grounded = true
+	cc.ground = ground
+	cc.groundNormal = normal
+	cc.groundAnchor = linalg.Vector2f64{}
+	if anchor := ground.Shape.GetAnchor(); anchor != nil {
+		cc.groundAnchor = *anchor
+	}
+	cc.jumping = false
+}
+
+// SetWall records a wall at given direction. It should only be called by system.
+func (cc *CharacterController2D) SetWall(dir int) {
+	cc.onWall = true
+	cc.wallDir = dir
+}
+
+// SetCeiling records a ceiling. It should only be called by system.
+func (cc *CharacterController2D) SetCeiling() {
+	cc.onCeiling = true
+}
+
+// GetGroundDelta returns how far the ground moved since it was recorded. It should only be called by system.
+func (cc *CharacterController2D) GetGroundDelta() linalg.Vector2f64 {
+	if cc.ground == nil || cc.ground.Shape.GetAnchor() == nil {
+		return linalg.Vector2f64{}
+	}
+	return cc.ground.Shape.GetAnchor().Sub(cc.groundAnchor)
+}
@@ -0,0 +1,168 @@
+package component
+
+import (
+	"sync/atomic"
+
+	"galaxyzeta.io/engine/base"
+	"galaxyzeta.io/engine/linalg"
+	"galaxyzeta.io/engine/physics"
+)
+
+const NameCollider = "Collider"
+
+// oneWayMinDot is the minimal cosine between a contact normal and the one-way normal for the contact to be blocked.
+const oneWayMinDot = 0.5
+
+// OneWayUp is the default one-way normal, such platforms could be jumped through from below.
+var OneWayUp = linalg.NewVector2f64(0, -1)
+
+// Collider attaches a collision shape to a gameObject.
+// Shape could be any of physics.Circle, physics.Capsule, physics.Rectangle or physics.Polygon.
+type Collider struct {
+	Shape  physics.IShape
+	Name   string
+	iobj2d base.IGameObject2D // attached gameObject2D
+	Sr     *SpriteRenderer    // if spriteRenderer is not nil, collider will always synchronize with the hitbox of Sr's current frame.
+	tf     *Transform2D       // Shape follows rotation and scale of the transform once bound.
+	local  physics.IShape     // the shape before rotation and scale.
+	// 1 if the transform or the frame of Sr changed since Shape was built. Transforms may change in parallel with others reading Shape,
+	// so Shape is only rebuilt by SyncTransform, which collision systems call in their single-threaded updates.
+	dirty int32
+
+	// one-way colliders only block things coming from the side OneWayNormal points to.
+	OneWay       bool
+	OneWayNormal linalg.Vector2f64 // unit vector, OneWayUp is used if zero.
+}
+
+func NewCollider(shape physics.IShape, iobj2d base.IGameObject2D) *Collider {
+	return &Collider{
+		Shape:  shape,
+		Name:   NameCollider,
+		iobj2d: iobj2d,
+	}
+}
+
+// NewColliderDynamicHitbox creates a collider following the hitbox of current frame of the sprite renderer.
+func NewColliderDynamicHitbox(followSr *SpriteRenderer, iobj2d base.IGameObject2D) *Collider {
+	c := &Collider{
+		Shape:  followSr.GetHitbox(),
+		Name:   NameCollider,
+		iobj2d: iobj2d,
+		Sr:     followSr,
+	}
+	followSr.AddFrameChangeListener(c, func(*SpriteRenderer) {
+		atomic.StoreInt32(&c.dirty, 1)
+	})
+	return c
+}
+
+// NewCircleCollider creates a circle collider whose bounding square is placed on the anchor by pivot.
+func NewCircleCollider(anchor *linalg.Vector2f64, radius float64, pivot physics.Pivot, iobj2d base.IGameObject2D) *Collider {
+	p := pivot.GetPointOfBox(radius*2, radius*2)
+	return NewCollider(physics.NewCircle(anchor, -p.X, -p.Y, radius), iobj2d)
+}
+
+// NewCapsuleCollider creates an upright capsule collider that fits into a w*h box placed on the anchor by pivot.
+func NewCapsuleCollider(anchor *linalg.Vector2f64, w float64, h float64, pivot physics.Pivot, iobj2d base.IGameObject2D) *Collider {
+	p := pivot.GetPointOfBox(w, h)
+	return NewCollider(physics.NewVerticalCapsule(anchor, -p.X, -p.Y, w, h), iobj2d)
+}
+
+// NewBoxCollider creates an axis aligned box collider placed on the anchor by pivot.
+func NewBoxCollider(anchor *linalg.Vector2f64, w float64, h float64, pivot physics.Pivot, iobj2d base.IGameObject2D) *Collider {
+	p := pivot.GetPointOfBox(w, h)
+	return NewCollider(physics.NewAnchoredRectangle(anchor, -p.X, -p.Y, w, h), iobj2d)
+}
+
+func (c *Collider) GetName() string {
+	return c.Name
+}
+
+// I returns IGameObject2D, the representation and abstraction of a gameObject.
+func (c *Collider) I() base.IGameObject2D {
+	return c.iobj2d
+}
+
+// BindTransform makes Shape follow rotation and scale of the transform, the current shape is taken as the untransformed one.
+// Shapes should be anchored on the position of the transform, so they move along without being rebuilt.
+// Binding again to the same transform does nothing.
+func (c *Collider) BindTransform(tf *Transform2D) {
+	if c.tf == tf {
+		return
+	}
+	if c.tf != nil {
+		c.tf.RemoveChangeListener(c)
+	}
+	if c.local == nil {
+		c.local = c.Shape
+	}
+	c.tf = tf
+	tf.AddChangeListener(c, func(*Transform2D) {
+		atomic.StoreInt32(&c.dirty, 1)
+	})
+	c.syncTransform()
+}
+
+// SyncTransform rebuilds Shape if the bound transform or the frame of Sr has changed since last time.
+// Collision systems call it before relocating colliders, others may call it to get the up to date Shape at once.
+func (c *Collider) SyncTransform() {
+	if atomic.LoadInt32(&c.dirty) == 0 {
+		return
+	}
+	c.syncTransform()
+}
+
+// SetLocalShape replaces the untransformed shape, Shape is rebuilt from it if a transform is bound.
+func (c *Collider) SetLocalShape(shape physics.IShape) {
+	c.local = shape
+	c.Shape = shape
+	c.syncTransform()
+}
+
+// GetLocalShape returns the shape before rotation and scale.
+func (c *Collider) GetLocalShape() physics.IShape {
+	if c.local == nil {
+		return c.Shape
+	}
+	return c.local
+}
+
+func (c *Collider) syncTransform() {
+	atomic.StoreInt32(&c.dirty, 0)
+	if c.Sr != nil {
+		// the hitbox of current frame is the untransformed shape.
+		if c.tf == nil {
+			c.Shape = c.Sr.GetHitbox()
+			return
+		}
+		c.local = c.Sr.GetLocalHitbox()
+	}
+	if c.tf == nil {
+		return
+	}
+	c.Shape = physics.WithTransform(c.local, c.tf.Rotation, c.tf.Scale)
+}
+
+// SetOneWay makes the collider a one-way surface facing the normal, zero normal means OneWayUp.
+func (c *Collider) SetOneWay(normal linalg.Vector2f64) *Collider {
+	c.OneWay = true
+	c.OneWayNormal = OneWayUp
+	if normal != (linalg.Vector2f64{}) {
+		c.OneWayNormal = normal.Normalize()
+	}
+	return c
+}
+
+// GetOneWayNormal returns the direction a one-way collider faces.
+func (c *Collider) GetOneWayNormal() linalg.Vector2f64 {
+	if c.OneWayNormal == (linalg.Vector2f64{}) {
+		return OneWayUp
+	}
+	return c.OneWayNormal
+}
+
+// BlocksFrom tells whether a contact is blocked by the collider, normal points from the collider to the other shape.
+// Colliders that are not one-way block contacts from all directions.
+func (c *Collider) BlocksFrom(normal linalg.Vector2f64) bool {
+	return !c.OneWay || normal.Dot(c.GetOneWayNormal()) >= oneWayMinDot
+}
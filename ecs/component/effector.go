@@ -0,0 +1,119 @@
+package component
+
+import (
+	"galaxyzeta.io/engine/linalg"
+)
+
+// Effectors are applied by Physics2DSystem to dynamic bodies overlapping the collider of the same gameObject.
+// Surface effectors (conveyor and bounce) usually sit on solid colliders,
+// while area effectors (buoyancy and point) usually sit on colliders not tagged solid, so that bodies could get in.
+const (
+	NameConveyorEffector2D = "ConveyorEffector2D"
+	NameBounceEffector2D   = "BounceEffector2D"
+	NameBuoyancyEffector2D = "BuoyancyEffector2D"
+	NamePointEffector2D    = "PointEffector2D"
+)
+
+// ConveyorEffector2D drags touching bodies along its surface, like a conveyor belt.
+// Positive speed moves bodies on the top face to the right. Friction of contacts also drives bodies to the surface speed.
+type ConveyorEffector2D struct {
+	Speed      float64 // surface speed in pixels per second.
+	ForceScale float64 // portion of the speed difference removed each step, 1 means bodies reach the surface speed at once.
+}
+
+func NewConveyorEffector2D(speed float64) *ConveyorEffector2D {
+	return &ConveyorEffector2D{
+		Speed:      speed,
+		ForceScale: 1,
+	}
+}
+
+// GetName is an implementation of IComponent.
+func (e *ConveyorEffector2D) GetName() string {
+	return NameConveyorEffector2D
+}
+
+// BounceEffector2D launches touching bodies away from its surface, like a jump pad.
+type BounceEffector2D struct {
+	Speed float64 // minimal speed along the contact normal in pixels per second after bouncing.
+}
+
+func NewBounceEffector2D(speed float64) *BounceEffector2D {
+	return &BounceEffector2D{
+		Speed: speed,
+	}
+}
+
+// GetName is an implementation of IComponent.
+func (e *BounceEffector2D) GetName() string {
+	return NameBounceEffector2D
+}
+
+// BuoyancyEffector2D simulates fluid in its area, the surface is the top of the collider.
+// Forces are scaled by the submerged portion of the body.
+type BuoyancyEffector2D struct {
+	Density      float64           // density relative to bodies, 1 means fully submerged bodies neither sink nor float.
+	LinearDrag   float64           // velocity relative to the flow lost per second, in proportion.
+	AngularDrag  float64           // angular velocity lost per second, in proportion.
+	FlowVelocity linalg.Vector2f64 // velocity of the fluid in pixels per second.
+}
+
+func NewBuoyancyEffector2D(density float64) *BuoyancyEffector2D {
+	return &BuoyancyEffector2D{
+		Density:     density,
+		LinearDrag:  1,
+		AngularDrag: 1,
+	}
+}
+
+// GetName is an implementation of IComponent.
+func (e *BuoyancyEffector2D) GetName() string {
+	return NameBuoyancyEffector2D
+}
+
+// PointForceMode decides how the force of PointEffector2D falls off with distance.
+type PointForceMode int8
+
+const (
+	PointForceMode_Constant PointForceMode = iota
+	PointForceMode_InverseLinear
+	PointForceMode_InverseSquared
+)
+
+// PointEffector2D attracts or repels bodies in its area from the center of the collider.
+type PointEffector2D struct {
+	Magnitude     float64 // force at unit distance, positive value repels and negative value attracts.
+	Mode          PointForceMode
+	DistanceScale float64 // distance is multiplied by this before the falloff.
+}
+
+func NewPointEffector2D(magnitude float64, mode PointForceMode) *PointEffector2D {
+	return &PointEffector2D{
+		Magnitude:     magnitude,
+		Mode:          mode,
+		DistanceScale: 1,
+	}
+}
+
+// GetName is an implementation of IComponent.
+func (e *PointEffector2D) GetName() string {
+	return NamePointEffector2D
+}
+
+// ForceAt returns the magnitude of force at given distance from the center.
+func (e *PointEffector2D) ForceAt(distance float64) float64 {
+	d := distance * e.DistanceScale
+	switch e.Mode {
+	case PointForceMode_InverseLinear:
+		if d < 1 {
+			d = 1
+		}
+		return e.Magnitude / d
+	case PointForceMode_InverseSquared:
+		if d < 1 {
+			d = 1
+		}
+		return e.Magnitude / (d * d)
+	}
+	return e.Magnitude
+}
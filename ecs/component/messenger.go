@@ -7,9 +7,9 @@ const NameMessenger = "Messenger"
 // Messenger delivers events.
 type Messenger struct {
 	Owner         base.IGameObject2D
-	Pc            *PolygonCollider
-	ShouldTrigger func(pc *PolygonCollider) bool
-	Impact        func(pc *PolygonCollider)
+	Pc            *Collider
+	ShouldTrigger func(pc *Collider) bool
+	Impact        func(pc *Collider)
 }
 
 func (Messenger) GetName() string {
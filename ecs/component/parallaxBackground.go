@@ -0,0 +1,136 @@
+package component
+
+import (
+	"math"
+	"time"
+
+	"galaxyzeta.io/engine/graphics"
+	"galaxyzeta.io/engine/linalg"
+)
+
+const NameParallaxBackground string = "parallaxBackground"
+
+// ParallaxLayer is an image of a background, which moves slower than the world as the camera moves to look far away.
+type ParallaxLayer struct {
+	Frame        *graphics.GLFrame
+	ScrollFactor linalg.Vector2f64 // part of the camera movement the layer follows on each axis, 0 stays on screen and 1 moves with the world.
+	Offset       linalg.Vector2f64 // top-left corner of the image when the view is centered on the origin.
+	Scale        linalg.Vector2f64
+	RepeatX      bool              // tiles the image infinitely along the x axis.
+	RepeatY      bool              // tiles the image infinitely along the y axis.
+	AutoScroll   linalg.Vector2f64 // pixels per second the layer keeps moving, such as drifting clouds.
+	Tint         linalg.RgbaF64
+}
+
+// NewParallaxLayer returns a layer of an image moving with the world, which is not repeated.
+func NewParallaxLayer(frame *graphics.GLFrame) *ParallaxLayer {
+	return &ParallaxLayer{
+		Frame:        frame,
+		ScrollFactor: linalg.NewVector2f64(1, 1),
+		Scale:        linalg.NewVector2f64(1, 1),
+		Tint:         graphics.White,
+	}
+}
+
+// ParallaxBackground draws layers of images behind the scene, each scrolling by its own factor as the camera moves.
+// Layers are drawn in order, so farther layers come first. It is on the background sorting layer by default.
+type ParallaxBackground struct {
+	Name    string
+	Layers  []*ParallaxLayer
+	Enabled bool // is visible or not
+	Layer   int  // drawn by cameras whose layer masks contain it.
+
+	SortingLayer graphics.SortingLayer // drawn over renderables on sorting layers of lower order.
+
+	z     int64     // order in the sorting layer.
+	start time.Time // auto scroll is measured from it.
+}
+
+// NewParallaxBackground returns a background drawing layers in order.
+func NewParallaxBackground(layers ...*ParallaxLayer) *ParallaxBackground {
+	return &ParallaxBackground{
+		Name:         NameParallaxBackground,
+		Layers:       layers,
+		Enabled:      true,
+		SortingLayer: graphics.GetSortingLayer(graphics.SortingLayerBackground),
+		start:        time.Now(),
+	}
+}
+
+// GetName is an implementation of IComponent.
+func (pb *ParallaxBackground) GetName() string {
+	return pb.Name
+}
+
+// Render draws layers through the camera of the batch, so that every camera sees the background by its own position.
+func (pb *ParallaxBackground) Render(batch *graphics.SpriteBatch) {
+	if !pb.Enabled {
+		return
+	}
+	cam := batch.Camera()
+	view := cam.GetViewRect()
+	center := cam.GetCenter()
+	elapsed := time.Since(pb.start).Seconds()
+	for _, layer := range pb.Layers {
+		layer.render(batch, view, center, elapsed)
+	}
+}
+
+// render draws tiles of the layer covering view, or the image only if it is not repeated.
+func (l *ParallaxLayer) render(batch *graphics.SpriteBatch, view linalg.Rect, center linalg.Vector2f64, elapsed float64) {
+	bounds := l.Frame.GetImg().Bounds()
+	w, h := float64(bounds.Dx())*l.Scale.X, float64(bounds.Dy())*l.Scale.Y
+	if w <= 0 || h <= 0 {
+		return
+	}
+	// the layer follows ScrollFactor of the camera movement, so it is left behind by the rest of it.
+	origin := l.Offset.Add(l.AutoScroll.Scale(elapsed)).Add(linalg.NewVector2f64(center.X*(1-l.ScrollFactor.X), center.Y*(1-l.ScrollFactor.Y)))
+	x0, x1 := tileRange(origin.X, w, view.Left(), view.Right(), l.RepeatX)
+	y0, y1 := tileRange(origin.Y, h, view.Top(), view.Bottom(), l.RepeatY)
+	for j := y0; j <= y1; j++ {
+		for i := x0; i <= x1; i++ {
+			p := origin.Add(linalg.NewVector2f64(float64(i)*w, float64(j)*h))
+			batch.DrawFrame(l.Frame, [4]linalg.Vector2f64{
+				p,
+				p.Add(linalg.NewVector2f64(w, 0)),
+				p.Add(linalg.NewVector2f64(w, h)),
+				p.Add(linalg.NewVector2f64(0, h)),
+			}, l.Tint)
+		}
+	}
+}
+
+// tileRange returns indices of the first and the last tile of size placed from origin overlapping [min, max].
+// Only the tile at origin is drawn if the image is not repeated.
+func tileRange(origin float64, size float64, min float64, max float64, repeat bool) (int, int) {
+	if !repeat {
+		return 0, 0
+	}
+	return int(math.Floor((min - origin) / size)), int(math.Floor((max - origin) / size))
+}
+
+// PostRender does nothing, layers scroll by the wall clock.
+func (pb *ParallaxBackground) PostRender() {}
+
+// IsStatic returns false, so that the background could be moved to other sorting layers.
+func (pb *ParallaxBackground) IsStatic() bool {
+	return false
+}
+
+func (pb *ParallaxBackground) Z() int64 {
+	return pb.z
+}
+
+func (pb *ParallaxBackground) SetZ(z int64) {
+	pb.z = z
+}
+
+// GetLayer is an implementation of graphics.ILayered.
+func (pb *ParallaxBackground) GetLayer() int {
+	return pb.Layer
+}
+
+// GetSortingLayer is an implementation of graphics.ISortingLayered.
+func (pb *ParallaxBackground) GetSortingLayer() graphics.SortingLayer {
+	return pb.SortingLayer
+}
@@ -0,0 +1,381 @@
+package component
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"galaxyzeta.io/engine/graphics"
+	"galaxyzeta.io/engine/linalg"
+)
+
+const NameParticleEmitter = "ParticleEmitter"
+
+type EmitterShape uint8
+
+const (
+	EmitterShape_Point     EmitterShape = iota
+	EmitterShape_Circle                 // inside a circle of Radius.
+	EmitterShape_Rectangle              // inside a rectangle of Size.
+	EmitterShape_Edge                   // on a segment as long as Size.X along the X axis.
+)
+
+type SimulationSpace uint8
+
+const (
+	SimulationSpace_World SimulationSpace = iota // particles stay where they are emitted.
+	SimulationSpace_Local                        // particles move, rotate and scale along with the transform.
+)
+
+// ParticleBurst emits Count particles at once when a cycle reaches Time, in seconds.
+type ParticleBurst struct {
+	Time  float64
+	Count int
+}
+
+type particle struct {
+	pos      linalg.Vector2f64 // in world or local space, according to the simulation space.
+	vel      linalg.Vector2f64
+	age      float64
+	lifetime float64
+	size     float64
+}
+
+var particleEmitterConfigMap = map[string]ParticleEmitterConfig{}
+
+// ParticleEmitterConfig describes how particles are emitted, simulated and drawn with frames of a sprite meta.
+// All speeds are in pixels per second, all durations are in seconds, and all angles are in degrees.
+type ParticleEmitterConfig struct {
+	Sprite       string  // frames of the sprite meta are played over the lifetime of each particle.
+	MaxParticles int     // particles emitted beyond this are dropped.
+	Duration     float64 // length of a cycle, bursts are timed within a cycle.
+	Loop         bool    // starts another cycle once a cycle ends, otherwise stops emitting.
+	Rate         float64 // particles emitted per second.
+	Bursts       []ParticleBurst
+
+	// shapes are centered on the transform.
+	Shape  EmitterShape
+	Radius float64
+	Size   linalg.Vector2f64
+
+	Direction    float64 // counterclockwise from the X axis, 90 points up.
+	Spread       float64 // directions are randomized within Direction ± Spread/2.
+	SpeedMin     float64
+	SpeedMax     float64
+	LifetimeMin  float64
+	LifetimeMax  float64
+	StartSizeMin float64 // scale of frames.
+	StartSizeMax float64
+	Gravity      linalg.Vector2f64
+
+	// curves are evaluated on the normalized age of particles, from 0 to 1. Empty curves keep values unchanged.
+	SpeedOverLifetime linalg.Curve    // multiplies the velocity when moving.
+	SizeOverLifetime  linalg.Curve    // multiplies the start size.
+	ColorOverLifetime linalg.Gradient // tints frames.
+
+	Space           SimulationSpace
+	Collide         bool    // particles collide with colliders tagged SolidTag.
+	Bounce          float64 // ratio of speed kept when bouncing off a collider.
+	KillOnCollision bool    // particles die when colliding instead of bouncing.
+	SolidTag        string
+}
+
+// ParticleEmitter emits and simulates particles as its config describes, which could be modified by game logic.
+// It is stepped by Particle2DSystem and drawn by Renderer2DSystem.
+type ParticleEmitter struct {
+	ParticleEmitterConfig
+	Enabled bool // is visible or not
+	Layer   int  // drawn by cameras whose layer masks contain it.
+
+	SortingLayer graphics.SortingLayer // drawn over renderables on sorting layers of lower order.
+
+	mu        sync.RWMutex // particles are simulated on the game thread and drawn on the render thread.
+	particles []particle
+	frames    []*graphics.GLFrame
+	tf        *Transform2D
+	z         int64 // order in the sorting layer.
+	isStatic  bool
+	playing   bool
+	elapsed   float64 // time elapsed in current cycle.
+	rateAcc   float64 // fraction of particles left by continuous emission.
+	rng       *rand.Rand
+}
+
+// NewParticleEmitterConfig returns a config emitting particles upward continuously.
+func NewParticleEmitterConfig(sprite string) ParticleEmitterConfig {
+	return ParticleEmitterConfig{
+		Sprite:       sprite,
+		MaxParticles: 256,
+		Duration:     1,
+		Loop:         true,
+		Rate:         10,
+		Direction:    90,
+		Spread:       30,
+		SpeedMin:     50,
+		SpeedMax:     100,
+		LifetimeMin:  1,
+		LifetimeMax:  1,
+		StartSizeMin: 1,
+		StartSizeMax: 1,
+		Bounce:       0.5,
+		SolidTag:     "solid",
+	}
+}
+
+// RegisterParticleEmitterConfig registers a config with a name, which could be used by level files.
+func RegisterParticleEmitterConfig(name string, cfg ParticleEmitterConfig) {
+	particleEmitterConfigMap[name] = cfg
+}
+
+// GetParticleEmitterConfig gets a config. Will panic if it is not found.
+func GetParticleEmitterConfig(name string) ParticleEmitterConfig {
+	cfg, ok := particleEmitterConfigMap[name]
+	if !ok {
+		panic(fmt.Sprintf("particle emitter config %s not found", name))
+	}
+	return cfg
+}
+
+// NewParticleEmitter returns a playing emitter with the default config of a sprite meta.
+func NewParticleEmitter(sprite string, tf *Transform2D, isStatic bool) *ParticleEmitter {
+	return NewParticleEmitterWithConfig(NewParticleEmitterConfig(sprite), tf, isStatic)
+}
+
+// NewParticleEmitterWithConfig returns a playing emitter with a config.
+func NewParticleEmitterWithConfig(cfg ParticleEmitterConfig, tf *Transform2D, isStatic bool) *ParticleEmitter {
+	return &ParticleEmitter{
+		ParticleEmitterConfig: cfg,
+		Enabled:               true,
+		frames:                graphics.GetSpriteMeta(cfg.Sprite).Frames,
+		tf:                    tf,
+		isStatic:              isStatic,
+		playing:               true,
+		rng:                   rand.New(rand.NewSource(time.Now().UnixNano())),
+		SortingLayer:          graphics.GetSortingLayer(graphics.SortingLayerDefault),
+	}
+}
+
+// GetName is an implementation of IComponent.
+func (e *ParticleEmitter) GetName() string {
+	return NameParticleEmitter
+}
+
+// Play starts emitting from the beginning of a cycle.
+func (e *ParticleEmitter) Play() {
+	e.mu.Lock()
+	e.playing = true
+	e.elapsed = 0
+	e.rateAcc = 0
+	e.mu.Unlock()
+}
+
+// Stop stops emitting, particles alive keep moving until they die.
+func (e *ParticleEmitter) Stop() {
+	e.mu.Lock()
+	e.playing = false
+	e.mu.Unlock()
+}
+
+// Clear kills all particles alive.
+func (e *ParticleEmitter) Clear() {
+	e.mu.Lock()
+	e.particles = e.particles[:0]
+	e.mu.Unlock()
+}
+
+// Emit emits count particles immediately, whether the emitter is playing or not.
+func (e *ParticleEmitter) Emit(count int) {
+	e.mu.Lock()
+	e.emit(count)
+	e.mu.Unlock()
+}
+
+func (e *ParticleEmitter) IsPlaying() (ret bool) {
+	e.mu.RLock()
+	ret = e.playing
+	e.mu.RUnlock()
+	return
+}
+
+// ParticleCount returns the number of particles alive.
+func (e *ParticleEmitter) ParticleCount() (ret int) {
+	e.mu.RLock()
+	ret = len(e.particles)
+	e.mu.RUnlock()
+	return
+}
+
+// IsAlive tells whether the emitter is playing or has particles alive, a finished one-shot effect could be destroyed once it is not.
+func (e *ParticleEmitter) IsAlive() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.playing || len(e.particles) > 0
+}
+
+// Step simulates particles alive and then emits new ones, dt is in seconds.
+// blocked tells whether a point in world space is inside a solid collider, it is only used if Collide is set.
+func (e *ParticleEmitter) Step(dt float64, blocked func(p linalg.Vector2f64) bool) {
+	if e.Duration <= 0 {
+		panic("duration of a particle emitter must be positive")
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.Collide {
+		blocked = nil
+	}
+	alive := e.particles[:0]
+	for _, p := range e.particles {
+		p.age += dt
+		if p.age >= p.lifetime {
+			continue
+		}
+		p.vel = p.vel.Add(e.Gravity.Scale(dt))
+		d := p.vel.Scale(e.SpeedOverLifetime.Evaluate(p.age/p.lifetime) * dt)
+		if blocked != nil && blocked(e.toWorld(p.pos.Add(d))) {
+			if e.KillOnCollision {
+				continue
+			}
+			// bounces off the axis blocked, or both if only the diagonal move is blocked.
+			hitX := blocked(e.toWorld(p.pos.Add(linalg.NewVector2f64(d.X, 0))))
+			hitY := blocked(e.toWorld(p.pos.Add(linalg.NewVector2f64(0, d.Y))))
+			if !hitX && !hitY {
+				hitX, hitY = true, true
+			}
+			if hitX {
+				p.vel.X, d.X = -p.vel.X*e.Bounce, 0
+			}
+			if hitY {
+				p.vel.Y, d.Y = -p.vel.Y*e.Bounce, 0
+			}
+		}
+		p.pos = p.pos.Add(d)
+		alive = append(alive, p)
+	}
+	e.particles = alive
+
+	for remaining := dt; remaining > 0 && e.playing; {
+		step := math.Min(remaining, e.Duration-e.elapsed)
+		for _, burst := range e.Bursts {
+			if burst.Time >= e.elapsed && burst.Time < e.elapsed+step {
+				e.emit(burst.Count)
+			}
+		}
+		e.rateAcc += e.Rate * step
+		count := int(e.rateAcc)
+		e.rateAcc -= float64(count)
+		e.emit(count)
+		e.elapsed += step
+		remaining -= step
+		if e.elapsed >= e.Duration {
+			e.elapsed = 0
+			e.playing = e.Loop
+		}
+	}
+}
+
+// emit spawns particles on the shape, which must be called with the lock held.
+func (e *ParticleEmitter) emit(count int) {
+	m := e.tf.Matrix()
+	for i := 0; i < count && len(e.particles) < e.MaxParticles; i++ {
+		deg := e.Direction + (e.rng.Float64()-0.5)*e.Spread
+		rad := linalg.Deg2Rad(deg)
+		// y-axis points down, so counterclockwise angles go up.
+		vel := linalg.NewVector2f64(math.Cos(rad), -math.Sin(rad)).Scale(e.randRange(e.SpeedMin, e.SpeedMax))
+		pos := e.spawnPoint()
+		if e.Space == SimulationSpace_World {
+			pos = m.MulPoint(pos)
+			if dir := m.MulVector(vel); dir != (linalg.Vector2f64{}) {
+				vel = dir.Normalize().Scale(vel.Magnitude())
+			}
+		}
+		e.particles = append(e.particles, particle{
+			pos:      pos,
+			vel:      vel,
+			lifetime: math.Max(e.randRange(e.LifetimeMin, e.LifetimeMax), 1e-6),
+			size:     e.randRange(e.StartSizeMin, e.StartSizeMax),
+		})
+	}
+}
+
+// spawnPoint returns a random point of the shape in local space.
+func (e *ParticleEmitter) spawnPoint() linalg.Vector2f64 {
+	switch e.Shape {
+	case EmitterShape_Circle:
+		// square root keeps points uniform over the area.
+		r := e.Radius * math.Sqrt(e.rng.Float64())
+		theta := e.rng.Float64() * 2 * math.Pi
+		return linalg.NewVector2f64(r*math.Cos(theta), r*math.Sin(theta))
+	case EmitterShape_Rectangle:
+		return linalg.NewVector2f64((e.rng.Float64()-0.5)*e.Size.X, (e.rng.Float64()-0.5)*e.Size.Y)
+	case EmitterShape_Edge:
+		return linalg.NewVector2f64((e.rng.Float64()-0.5)*e.Size.X, 0)
+	}
+	return linalg.Vector2f64{}
+}
+
+func (e *ParticleEmitter) randRange(min float64, max float64) float64 {
+	return min + (max-min)*e.rng.Float64()
+}
+
+// toWorld converts a position of a particle into world space.
+func (e *ParticleEmitter) toWorld(p linalg.Vector2f64) linalg.Vector2f64 {
+	if e.Space == SimulationSpace_Local {
+		return e.tf.TransformPoint(p)
+	}
+	return p
+}
+
+// ===== IMPLEMENTATION =====
+
+// Render queues particles into the batch, frames are centered on particles.
+func (e *ParticleEmitter) Render(batch *graphics.SpriteBatch) {
+	if !e.Enabled || len(e.frames) == 0 {
+		return
+	}
+	space := linalg.Identity3()
+	if e.Space == SimulationSpace_Local {
+		space = e.tf.Matrix()
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, p := range e.particles {
+		t := p.age / p.lifetime
+		frame := e.frames[int(t*float64(len(e.frames)))%len(e.frames)]
+		size := p.size * e.SizeOverLifetime.Evaluate(t)
+		m := space.Mul(linalg.NewTransformMat3(p.pos, 0, linalg.NewVector2f64(size, size)))
+		bounds := frame.GetImg().Bounds()
+		hw, hh := float64(bounds.Dx())/2, float64(bounds.Dy())/2
+		batch.DrawFrame(frame, [4]linalg.Vector2f64{
+			m.MulPoint(linalg.NewVector2f64(-hw, -hh)),
+			m.MulPoint(linalg.NewVector2f64(hw, -hh)),
+			m.MulPoint(linalg.NewVector2f64(hw, hh)),
+			m.MulPoint(linalg.NewVector2f64(-hw, hh)),
+		}, e.ColorOverLifetime.Evaluate(t))
+	}
+}
+
+func (e *ParticleEmitter) PostRender() {}
+
+func (e *ParticleEmitter) IsStatic() bool {
+	return e.isStatic
+}
+
+func (e *ParticleEmitter) Z() int64 {
+	return e.z
+}
+
+func (e *ParticleEmitter) SetZ(z int64) {
+	e.z = z
+}
+
+// GetLayer is an implementation of graphics.ILayered.
+func (e *ParticleEmitter) GetLayer() int {
+	return e.Layer
+}
+
+// GetSortingLayer is an implementation of graphics.ISortingLayered.
+func (e *ParticleEmitter) GetSortingLayer() graphics.SortingLayer {
+	return e.SortingLayer
+}
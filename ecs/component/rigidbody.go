@@ -5,6 +5,7 @@ import (
 
 	"galaxyzeta.io/engine/config"
 	"galaxyzeta.io/engine/infra/concurrency/lock"
+	"galaxyzeta.io/engine/linalg"
 )
 
 const NameRigidBody2D = "RigidBody"
@@ -15,6 +16,18 @@ type SpeedVector struct {
 	Speed        float64
 }
 
+// BodyType decides how a rigidbody is simulated by Physics2DSystem.
+type BodyType int8
+
+const (
+	// BodyType_Kinematic moves by speed vectors and gravity, and is simply blocked by solid colliders.
+	BodyType_Kinematic BodyType = iota
+	// BodyType_Dynamic is driven by forces and impulses, and resolves contacts with the impulse solver.
+	BodyType_Dynamic
+	// BodyType_Static never moves and is skipped by Physics2DSystem, dynamic bodies collide with it.
+	BodyType_Static
+)
+
 type RigidBody2D struct {
 	UseGravity    bool
 	GravityVector SpeedVector
@@ -22,16 +35,51 @@ type RigidBody2D struct {
 	Vspeed        float64
 	Hspeed        float64
 
+	// ===== dynamic body only =====
+	BodyType        BodyType
+	Velocity        linalg.Vector2f64 // pixels per second.
+	AngularVelocity float64           // degrees per second, clockwise on screen. The angle is the rotation of Transform2D.
+	Restitution     float64           // bounciness in [0, 1].
+	Friction        float64           // coulomb friction coefficient.
+	LinearDamping   float64           // velocity lost per second, in proportion.
+	AngularDamping  float64           // angular velocity lost per second, in proportion.
+	GravityScale    float64
+	FixedRotation   bool
+	NeverSleep      bool // prevents the body from sleeping when it stays at rest.
+
+	mass        float64
+	invMass     float64
+	inertia     float64
+	invInertia  float64
+	force       linalg.Vector2f64
+	torque      float64
+	pointForce  linalg.Vector2f64 // sum of forces applied at world points.
+	pointMoment float64           // sum of cross(point, force), torque is resolved by the system with body position.
+	sleeping    bool
+	sleepTime   float64 // seconds the body has been resting.
+
 	mu lock.SpinLock
 }
 
 func NewRigidBody2D() *RigidBody2D {
 	return &RigidBody2D{
-		speed: &list.List{},
-		mu:    lock.SpinLock{},
+		speed:        &list.List{},
+		mu:           lock.SpinLock{},
+		GravityScale: 1,
+		Friction:     0.3,
 	}
 }
 
+// NewDynamicRigidBody2D creates a dynamic rigidbody with given mass.
+// The inertia is computed from the collider by Physics2DSystem if not set.
+func NewDynamicRigidBody2D(mass float64) *RigidBody2D {
+	rb := NewRigidBody2D()
+	rb.BodyType = BodyType_Dynamic
+	rb.UseGravity = true
+	rb.SetMass(mass)
+	return rb
+}
+
 // GetName is an implementation of IComponent.
 func (rb *RigidBody2D) GetName() string {
 	return NameRigidBody2D
@@ -87,3 +135,135 @@ func (rb *RigidBody2D) SetHspeed(hspeed float64) {
 func (rb *RigidBody2D) SetVspeed(vspeed float64) {
 	rb.Vspeed = vspeed
 }
+
+// SetMass sets the mass of a dynamic body, non-positive mass makes the body immovable.
+// A dynamic body still without mass when registered to Physics2DSystem is given the default mass 1 with a warning.
+func (rb *RigidBody2D) SetMass(mass float64) {
+	if mass <= 0 {
+		rb.mass, rb.invMass = 0, 0
+		return
+	}
+	rb.mass, rb.invMass = mass, 1/mass
+}
+
+// SetInertia sets the moment of inertia, non-positive inertia makes the body unable to rotate.
+func (rb *RigidBody2D) SetInertia(inertia float64) {
+	if inertia <= 0 {
+		rb.inertia, rb.invInertia = 0, 0
+		return
+	}
+	rb.inertia, rb.invInertia = inertia, 1/inertia
+}
+
+func (rb *RigidBody2D) GetMass() float64 {
+	return rb.mass
+}
+
+func (rb *RigidBody2D) GetInvMass() float64 {
+	return rb.invMass
+}
+
+func (rb *RigidBody2D) GetInertia() float64 {
+	return rb.inertia
+}
+
+func (rb *RigidBody2D) GetInvInertia() float64 {
+	if rb.FixedRotation {
+		return 0
+	}
+	return rb.invInertia
+}
+
+// ApplyForce applies a force at the center of mass, it takes effect in the next physics step.
+func (rb *RigidBody2D) ApplyForce(f linalg.Vector2f64) {
+	rb.Lock()
+	rb.wakeUp()
+	rb.force = rb.force.Add(f)
+	rb.Unlock()
+}
+
+// ApplyForceAtPoint applies a force at a world point, which also generates torque.
+func (rb *RigidBody2D) ApplyForceAtPoint(f linalg.Vector2f64, point linalg.Vector2f64) {
+	rb.Lock()
+	rb.wakeUp()
+	rb.pointForce = rb.pointForce.Add(f)
+	rb.pointMoment += point.Mult(f)
+	rb.Unlock()
+}
+
+// ApplyTorque applies a torque in the next physics step.
+func (rb *RigidBody2D) ApplyTorque(torque float64) {
+	rb.Lock()
+	rb.wakeUp()
+	rb.torque += torque
+	rb.Unlock()
+}
+
+// ApplyImpulse changes velocity immediately.
+func (rb *RigidBody2D) ApplyImpulse(impulse linalg.Vector2f64) {
+	rb.Lock()
+	rb.wakeUp()
+	rb.Velocity = rb.Velocity.Add(impulse.Scale(rb.invMass))
+	rb.Unlock()
+}
+
+// ApplyImpulseAtPoint changes velocity and angular velocity immediately, r is the offset from the center of mass to the point.
+func (rb *RigidBody2D) ApplyImpulseAtPoint(impulse linalg.Vector2f64, r linalg.Vector2f64) {
+	rb.Lock()
+	rb.wakeUp()
+	rb.Velocity = rb.Velocity.Add(impulse.Scale(rb.invMass))
+	rb.AngularVelocity += linalg.Rad2Deg(r.Mult(impulse) * rb.GetInvInertia())
+	rb.Unlock()
+}
+
+// ConsumeForces returns accumulated force and torque around pos, and clears them.
+// It should only be called by system.
+func (rb *RigidBody2D) ConsumeForces(pos linalg.Vector2f64) (linalg.Vector2f64, float64) {
+	rb.Lock()
+	force := rb.force.Add(rb.pointForce)
+	torque := rb.torque + rb.pointMoment - pos.Mult(rb.pointForce)
+	rb.force, rb.pointForce = linalg.Vector2f64{}, linalg.Vector2f64{}
+	rb.torque, rb.pointMoment = 0, 0
+	rb.Unlock()
+	return force, torque
+}
+
+// IsSleeping tells whether a dynamic body is sleeping. Sleeping bodies are not simulated until woken up.
+func (rb *RigidBody2D) IsSleeping() bool {
+	return rb.sleeping
+}
+
+// WakeUp wakes a sleeping body. Applying forces or impulses wakes the body automatically,
+// but setting Velocity directly does not.
+func (rb *RigidBody2D) WakeUp() {
+	rb.Lock()
+	rb.wakeUp()
+	rb.Unlock()
+}
+
+// Sleep puts the body to sleep immediately and stops it.
+func (rb *RigidBody2D) Sleep() {
+	rb.Lock()
+	rb.sleeping = true
+	rb.Velocity = linalg.Vector2f64{}
+	rb.AngularVelocity = 0
+	rb.Hspeed, rb.Vspeed = 0, 0
+	rb.Unlock()
+}
+
+// AccumulateSleep should only be called by system. The body falls asleep after resting for timeToSleep seconds.
+func (rb *RigidBody2D) AccumulateSleep(dt float64, resting bool, timeToSleep float64) {
+	if !resting || rb.NeverSleep {
+		rb.sleepTime = 0
+		return
+	}
+	rb.sleepTime += dt
+	if rb.sleepTime >= timeToSleep {
+		rb.Sleep()
+	}
+}
+
+func (rb *RigidBody2D) wakeUp() {
+	rb.sleeping = false
+	rb.sleepTime = 0
+}
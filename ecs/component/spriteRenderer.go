@@ -12,12 +12,18 @@ type SpriteRenderer struct {
 	*graphics.Animator
 	Name     string
 	tf       *Transform2D
-	z        int64             // render depth. bigger Z means deeper behind current view.
-	Scale    linalg.Vector2f64 // scale value on X and Y
+	z        int64 // order in the sorting layer, renderers of bigger Z are drawn over those of smaller Z.
 	Pivot    *physics.Pivot
-	Offset   linalg.Vector2f64 // the offset from sprite to player, negative value means drawing at left of an object.
-	isStatic bool              // read only, if marked true, will not involve in Z-depth sorting.
-	Enabled  bool              // is visible or not
+	Offset   linalg.Vector2f64  // the offset from sprite to player, negative value means drawing at left of an object.
+	isStatic bool               // read only, if marked true, will not involve in Z-depth sorting.
+	Enabled  bool               // is visible or not
+	Material *graphics.Material // draws with the default shader if nil.
+	Layer    int                // drawn by cameras whose layer masks contain it.
+
+	SortingLayer graphics.SortingLayer // drawn over renderables on sorting layers of lower order.
+
+	frame     *graphics.GLFrame                        // frame shown when SyncFrame was last called.
+	listeners map[interface{}]func(sr *SpriteRenderer) // listeners are notified when the frame shown changes.
 }
 
 // GetName returns sprite renderer's name.
@@ -25,29 +31,36 @@ func (sr *SpriteRenderer) GetName() string {
 	return sr.Name
 }
 
-// NewSpriteRenderer returns a new renderer that render sprites.
+// NewSpriteRenderer returns a new renderer that render sprites, which are rotated and scaled by the transform.
 func NewSpriteRenderer(animator *graphics.Animator, tf *Transform2D, isStatic bool) *SpriteRenderer {
 	return &SpriteRenderer{
 		Animator: animator,
 		tf:       tf,
 		Enabled:  true,
-		Scale:    linalg.NewVector2f64(1, 1),
 		Name:     NameSpriteRenderer,
 		Pivot: &physics.Pivot{
 			Option: physics.PivotOption_TopLeft,
 		},
-		isStatic: isStatic,
+		isStatic:     isStatic,
+		SortingLayer: graphics.GetSortingLayer(graphics.SortingLayerDefault),
 	}
 }
 
+// NewSpriteRendererWithOptions returns a new renderer, the scale option is applied to the transform.
 func NewSpriteRendererWithOptions(animator *graphics.Animator, tf *Transform2D, isStatic bool, options graphics.RenderOptions) (sr *SpriteRenderer) {
 	sr = NewSpriteRenderer(animator, tf, isStatic)
 	if options.Scale != nil {
-		sr.Scale = *options.Scale
+		tf.SetScale(options.Scale.X, options.Scale.Y)
 	}
 	if options.Pivot != nil {
 		sr.Pivot = options.Pivot
 	}
+	sr.Material = options.Material
+	sr.Layer = options.Layer
+	if options.SortingLayer != nil {
+		sr.SortingLayer = *options.SortingLayer
+	}
+	sr.z = options.Z
 	return sr
 }
 
@@ -56,11 +69,8 @@ func (sr *SpriteRenderer) Spr() *graphics.SpriteInstance {
 	return sr.Animator.Spr()
 }
 
-func (sr *SpriteRenderer) Render(cam *graphics.Camera) {
-	sr.Spr().Render(cam, sr.tf.Pos, graphics.RenderOptions{
-		Scale: &sr.Scale,
-		Pivot: sr.Pivot,
-	})
+func (sr *SpriteRenderer) Render(batch *graphics.SpriteBatch) {
+	sr.Animator.DrawWithMaterial(batch, sr.tf.Matrix(), sr.Pivot, sr.Material)
 }
 
 func (sr *SpriteRenderer) IsStatic() bool {
@@ -71,14 +81,64 @@ func (sr *SpriteRenderer) Z() int64 {
 	return sr.z
 }
 
-func (sr *SpriteRenderer) PostRender() {
-	sr.Spr().DoFrameStep()
-}
+// PostRender does nothing, animation is stepped by Animation2DSystem on the game clock.
+func (sr *SpriteRenderer) PostRender() {}
 
 func (sr *SpriteRenderer) SetZ(z int64) {
 	sr.z = z
 }
 
+// GetLayer is an implementation of graphics.ILayered.
+func (sr *SpriteRenderer) GetLayer() int {
+	return sr.Layer
+}
+
+// GetSortingLayer is an implementation of graphics.ISortingLayered.
+func (sr *SpriteRenderer) GetSortingLayer() graphics.SortingLayer {
+	return sr.SortingLayer
+}
+
+// GetHitbox returns the hitbox of the sprite, rotated and scaled by the transform.
 func (sr *SpriteRenderer) GetHitbox() physics.Polygon {
+	return sr.GetLocalHitbox().Transformed(sr.tf.Rotation, sr.tf.Scale)
+}
+
+// GetLocalHitbox returns the hitbox of the sprite before rotation and scale.
+func (sr *SpriteRenderer) GetLocalHitbox() physics.Polygon {
 	return sr.Animator.Spr().GetHitbox(&sr.tf.Pos, physics.Pivot{Option: sr.Pivot.Option})
 }
+
+// GetHurtboxes returns hurtboxes of current frame, rotated and scaled by the transform.
+func (sr *SpriteRenderer) GetHurtboxes() []physics.Polygon {
+	hurtboxes := sr.Animator.Spr().GetHurtboxes(&sr.tf.Pos, physics.Pivot{Option: sr.Pivot.Option})
+	for idx := range hurtboxes {
+		hurtboxes[idx] = hurtboxes[idx].Transformed(sr.tf.Rotation, sr.tf.Scale)
+	}
+	return hurtboxes
+}
+
+// AddFrameChangeListener registers a listener with a key, it will be called whenever SyncFrame finds the frame shown changed.
+// Registering with an existing key replaces the old listener.
+func (sr *SpriteRenderer) AddFrameChangeListener(key interface{}, fx func(sr *SpriteRenderer)) {
+	if sr.listeners == nil {
+		sr.listeners = make(map[interface{}]func(sr *SpriteRenderer))
+	}
+	sr.listeners[key] = fx
+}
+
+// RemoveFrameChangeListener removes the listener registered with the key.
+func (sr *SpriteRenderer) RemoveFrameChangeListener(key interface{}) {
+	delete(sr.listeners, key)
+}
+
+// SyncFrame notifies listeners if the frame shown has changed since last call, which is done by Animation2DSystem every step.
+func (sr *SpriteRenderer) SyncFrame() {
+	frame := sr.Animator.Spr().CurrentFrame()
+	if frame == sr.frame {
+		return
+	}
+	sr.frame = frame
+	for _, fx := range sr.listeners {
+		fx(sr)
+	}
+}
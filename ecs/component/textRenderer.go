@@ -0,0 +1,114 @@
+package component
+
+import (
+	"sync"
+
+	"galaxyzeta.io/engine/graphics"
+	"galaxyzeta.io/engine/physics"
+)
+
+const NameTextRenderer string = "textRenderer"
+
+// TextRenderer draws a text with a font, which is rotated and scaled by the transform, and sorted together with sprites.
+// Text and options could be changed by game logic while being rendered.
+type TextRenderer struct {
+	Name     string
+	Font     *graphics.Font
+	Pivot    *physics.Pivot // the point of the text box placed on the transform.
+	tf       *Transform2D
+	z        int64 // order in the sorting layer.
+	isStatic bool  // read only, if marked true, will not involve in Z-depth sorting.
+	Enabled  bool  // is visible or not
+	Layer    int   // drawn by cameras whose layer masks contain it.
+
+	SortingLayer graphics.SortingLayer // drawn over renderables on sorting layers of lower order.
+
+	mu      sync.RWMutex
+	text    string
+	options graphics.TextOptions
+}
+
+// NewTextRenderer returns a new renderer drawing white, left aligned text.
+func NewTextRenderer(font *graphics.Font, text string, tf *Transform2D, isStatic bool) *TextRenderer {
+	return NewTextRendererWithOptions(font, text, tf, isStatic, graphics.NewTextOptions())
+}
+
+// NewTextRendererWithOptions returns a new renderer drawing text as options describe.
+func NewTextRendererWithOptions(font *graphics.Font, text string, tf *Transform2D, isStatic bool, options graphics.TextOptions) *TextRenderer {
+	return &TextRenderer{
+		Name:         NameTextRenderer,
+		Font:         font,
+		Pivot:        &physics.Pivot{Option: physics.PivotOption_TopLeft},
+		tf:           tf,
+		isStatic:     isStatic,
+		Enabled:      true,
+		text:         text,
+		options:      options,
+		SortingLayer: graphics.GetSortingLayer(graphics.SortingLayerDefault),
+	}
+}
+
+// GetName returns text renderer's name.
+func (tr *TextRenderer) GetName() string {
+	return tr.Name
+}
+
+func (tr *TextRenderer) SetText(text string) {
+	tr.mu.Lock()
+	tr.text = text
+	tr.mu.Unlock()
+}
+
+func (tr *TextRenderer) GetText() (ret string) {
+	tr.mu.RLock()
+	ret = tr.text
+	tr.mu.RUnlock()
+	return
+}
+
+func (tr *TextRenderer) SetOptions(options graphics.TextOptions) {
+	tr.mu.Lock()
+	tr.options = options
+	tr.mu.Unlock()
+}
+
+func (tr *TextRenderer) GetOptions() (ret graphics.TextOptions) {
+	tr.mu.RLock()
+	ret = tr.options
+	tr.mu.RUnlock()
+	return
+}
+
+func (tr *TextRenderer) Render(batch *graphics.SpriteBatch) {
+	if !tr.Enabled {
+		return
+	}
+	tr.mu.RLock()
+	text, options := tr.text, tr.options
+	tr.mu.RUnlock()
+	tr.Font.Draw(batch, text, tr.tf.Matrix(), tr.Pivot, options)
+}
+
+func (tr *TextRenderer) PostRender() {}
+
+func (tr *TextRenderer) IsStatic() bool {
+	return tr.isStatic
+}
+
+func (tr *TextRenderer) Z() int64 {
+	return tr.z
+}
+
+func (tr *TextRenderer) SetZ(z int64) {
+	tr.z = z
+}
+
+// GetLayer is an implementation of graphics.ILayered.
+func (tr *TextRenderer) GetLayer() int {
+	return tr.Layer
+}
+
+// GetSortingLayer is an implementation of graphics.ISortingLayered.
+func (tr *TextRenderer) GetSortingLayer() graphics.SortingLayer {
+	return tr.SortingLayer
+}
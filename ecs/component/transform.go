@@ -1,6 +1,7 @@
 package component
 
 import (
+	"galaxyzeta.io/engine/base"
 	"galaxyzeta.io/engine/config"
 	"galaxyzeta.io/engine/infra/concurrency/lock"
 	"galaxyzeta.io/engine/linalg"
@@ -8,14 +9,47 @@ import (
 
 const NameTransform2D = "Transform2D"
 
+// Transform2D owns position, rotation and scale of a gameObject.
+// Sprites and colliders are scaled first, then rotated around Pos, and finally placed on Pos.
+//
+// Pos, Rotation and Scale are always in world space. A transform with a parent also keeps them relative to the parent,
+// which are the source of truth: world values of children are recomputed from parents by PropagateToChildren.
+// Changing world values of a child, by methods or directly, updates its local values accordingly.
 type Transform2D struct {
-	prevPos linalg.Vector2f64
-	Pos     linalg.Vector2f64
-	mu      lock.SpinLock
+	prevPos      linalg.Vector2f64
+	prevRotation float64
+	prevScale    linalg.Vector2f64
+	Pos          linalg.Vector2f64
+	Rotation     float64           // degrees, clockwise on screen since y-axis points down.
+	Scale        linalg.Vector2f64 // scale on X and Y, negative value flips.
+	matrix       linalg.Mat3       // cached local to world matrix.
+	matrixOf     transformState    // state the cached matrix was built from.
+	mu           lock.SpinLock
+	listeners    map[interface{}]func(tf *Transform2D) // listeners are notified when position, rotation or scale changes
+
+	iobj2d        base.IGameObject2D // owner of the transform, set by core when the gameObject is created.
+	parent        *Transform2D
+	children      []*Transform2D
+	localPos      linalg.Vector2f64
+	localRotation float64
+	localScale    linalg.Vector2f64
+	synced        transformState // world values when local values were last synchronized.
+}
+
+// transformState is what the matrix of a Transform2D is built from.
+type transformState struct {
+	pos      linalg.Vector2f64
+	rotation float64
+	scale    linalg.Vector2f64
+	valid    bool
 }
 
 func NewTransform2D() *Transform2D {
-	return new(Transform2D)
+	return &Transform2D{
+		Scale:      linalg.NewVector2f64(1, 1),
+		prevScale:  linalg.NewVector2f64(1, 1),
+		localScale: linalg.NewVector2f64(1, 1),
+	}
 }
 
 // ===== IMPLEMENTATION =====
@@ -43,20 +77,257 @@ func (tf *Transform2D) Y() float64 {
 }
 
 // MemXY memorizes X, Y postion to prevX, prevY.
+// Listeners are notified if Pos, Rotation or Scale was modified directly during this step.
 func (tf *Transform2D) MemXY() {
+	if tf.Pos != tf.prevPos || tf.Rotation != tf.prevRotation || tf.Scale != tf.prevScale {
+		tf.notifyChange()
+	}
 	tf.prevPos = tf.Pos
+	tf.prevRotation = tf.Rotation
+	tf.prevScale = tf.Scale
 }
 
 // Transalte a delta distance.
 func (tf *Transform2D) Translate(x float64, y float64) {
 	tf.Pos.X += x
 	tf.Pos.Y += y
+	tf.worldChanged()
 }
 
 // Teleport to a given location.
 func (tf *Transform2D) Teleport(x float64, y float64) {
 	tf.Pos.X = x
 	tf.Pos.Y = y
+	tf.worldChanged()
+}
+
+// Rotate by a delta angle in degrees.
+func (tf *Transform2D) Rotate(deg float64) {
+	tf.Rotation += deg
+	tf.worldChanged()
+}
+
+// SetRotation sets the angle in degrees.
+func (tf *Transform2D) SetRotation(deg float64) {
+	tf.Rotation = deg
+	tf.worldChanged()
+}
+
+// SetScale sets scale on X and Y.
+func (tf *Transform2D) SetScale(x float64, y float64) {
+	tf.Scale.X = x
+	tf.Scale.Y = y
+	tf.worldChanged()
+}
+
+// Matrix returns the local to world matrix, it is rebuilt only when position, rotation or scale changed.
+func (tf *Transform2D) Matrix() linalg.Mat3 {
+	state := tf.worldState()
+	if state != tf.matrixOf {
+		tf.matrix = linalg.NewTransformMat3(tf.Pos, tf.Rotation, tf.Scale)
+		tf.matrixOf = state
+	}
+	return tf.matrix
+}
+
+// TransformPoint converts a point from local space into world space.
+func (tf *Transform2D) TransformPoint(local linalg.Vector2f64) linalg.Vector2f64 {
+	return tf.Matrix().MulPoint(local)
+}
+
+// AddChangeListener registers a listener with a key, it will be called whenever position, rotation or scale changes.
+// Registering with an existing key replaces the old listener.
+func (tf *Transform2D) AddChangeListener(key interface{}, fx func(tf *Transform2D)) {
+	if tf.listeners == nil {
+		tf.listeners = make(map[interface{}]func(tf *Transform2D))
+	}
+	tf.listeners[key] = fx
+}
+
+// RemoveChangeListener removes the listener registered with the key.
+func (tf *Transform2D) RemoveChangeListener(key interface{}) {
+	delete(tf.listeners, key)
+}
+
+// worldChanged synchronizes local values of a child after its world values changed, and notifies listeners.
+func (tf *Transform2D) worldChanged() {
+	if tf.parent != nil {
+		tf.syncLocal()
+	}
+	tf.notifyChange()
+}
+
+func (tf *Transform2D) notifyChange() {
+	for _, fx := range tf.listeners {
+		fx(tf)
+	}
+}
+
+// ===== HIERARCHY =====
+
+// SetIGameObject2D sets the owner of the transform, which is done by core when the gameObject is created.
+func (tf *Transform2D) SetIGameObject2D(iobj base.IGameObject2D) {
+	tf.iobj2d = iobj
+}
+
+// I returns the owner of the transform, nil if the transform is not attached to any created gameObject.
+func (tf *Transform2D) I() base.IGameObject2D {
+	return tf.iobj2d
+}
+
+// SetParent attaches the transform to a parent and keeps its world position, rotation and scale.
+// Nil parent detaches the transform. Panics if the parent is the transform itself or one of its descendants.
+func (tf *Transform2D) SetParent(parent *Transform2D) {
+	if parent == tf.parent {
+		return
+	}
+	for p := parent; p != nil; p = p.parent {
+		if p == tf {
+			panic("cannot set a transform or its descendant as its parent")
+		}
+	}
+	if tf.parent != nil {
+		siblings := tf.parent.children
+		for idx, child := range siblings {
+			if child == tf {
+				tf.parent.children = append(siblings[:idx], siblings[idx+1:]...)
+				break
+			}
+		}
+	}
+	tf.parent = parent
+	if parent != nil {
+		parent.children = append(parent.children, tf)
+		tf.syncLocal()
+	}
+}
+
+// GetParent returns the parent transform, nil if it is a root.
+func (tf *Transform2D) GetParent() *Transform2D {
+	return tf.parent
+}
+
+// GetChildren returns a replica of direct children.
+func (tf *Transform2D) GetChildren() []*Transform2D {
+	ret := make([]*Transform2D, len(tf.children))
+	copy(ret, tf.children)
+	return ret
+}
+
+// ChildCount returns the number of direct children.
+func (tf *Transform2D) ChildCount() int {
+	return len(tf.children)
+}
+
+// GetLocalPos returns the position relative to the parent, which is the world position for a root.
+func (tf *Transform2D) GetLocalPos() linalg.Vector2f64 {
+	if tf.parent == nil {
+		return tf.Pos
+	}
+	tf.syncLocalIfEdited()
+	return tf.localPos
+}
+
+// SetLocalPos sets the position relative to the parent, world position of a child is updated at once.
+func (tf *Transform2D) SetLocalPos(x float64, y float64) {
+	if tf.parent == nil {
+		tf.Teleport(x, y)
+		return
+	}
+	tf.syncLocalIfEdited()
+	tf.localPos = linalg.NewVector2f64(x, y)
+	tf.syncWorld()
+}
+
+// GetLocalRotation returns the angle relative to the parent in degrees.
+func (tf *Transform2D) GetLocalRotation() float64 {
+	if tf.parent == nil {
+		return tf.Rotation
+	}
+	tf.syncLocalIfEdited()
+	return tf.localRotation
+}
+
+// SetLocalRotation sets the angle relative to the parent in degrees, world rotation of a child is updated at once.
+func (tf *Transform2D) SetLocalRotation(deg float64) {
+	if tf.parent == nil {
+		tf.SetRotation(deg)
+		return
+	}
+	tf.syncLocalIfEdited()
+	tf.localRotation = deg
+	tf.syncWorld()
+}
+
+// GetLocalScale returns the scale relative to the parent.
+func (tf *Transform2D) GetLocalScale() linalg.Vector2f64 {
+	if tf.parent == nil {
+		return tf.Scale
+	}
+	tf.syncLocalIfEdited()
+	return tf.localScale
+}
+
+// SetLocalScale sets the scale relative to the parent, world scale of a child is updated at once.
+func (tf *Transform2D) SetLocalScale(x float64, y float64) {
+	if tf.parent == nil {
+		tf.SetScale(x, y)
+		return
+	}
+	tf.syncLocalIfEdited()
+	tf.localScale = linalg.NewVector2f64(x, y)
+	tf.syncWorld()
+}
+
+// PropagateToChildren recomputes world values of all descendants from their local values.
+// It is called on root transforms by core each step, after user steps.
+func (tf *Transform2D) PropagateToChildren() {
+	for _, child := range tf.children {
+		child.syncLocalIfEdited()
+		child.syncWorld()
+		child.PropagateToChildren()
+	}
+}
+
+// syncWorld recomputes world values of a child from its parent and local values, listeners are notified if they changed.
+// Rotation and scale are simply combined, so a rotated child of an unevenly scaled parent is not skewed.
+func (tf *Transform2D) syncWorld() {
+	p := tf.parent
+	before := tf.worldState()
+	tf.Pos = p.TransformPoint(tf.localPos)
+	tf.Rotation = p.Rotation + tf.localRotation
+	tf.Scale = linalg.NewVector2f64(p.Scale.X*tf.localScale.X, p.Scale.Y*tf.localScale.Y)
+	tf.synced = tf.worldState()
+	if tf.synced != before {
+		tf.notifyChange()
+	}
+}
+
+// syncLocalIfEdited updates local values if world values of a child were modified directly.
+func (tf *Transform2D) syncLocalIfEdited() {
+	if tf.worldState() != tf.synced {
+		tf.syncLocal()
+	}
+}
+
+// syncLocal recomputes local values of a child from its world values.
+func (tf *Transform2D) syncLocal() {
+	p := tf.parent
+	if inv, ok := p.Matrix().Inverse(); ok {
+		tf.localPos = inv.MulPoint(tf.Pos)
+	}
+	tf.localRotation = tf.Rotation - p.Rotation
+	if p.Scale.X != 0 {
+		tf.localScale.X = tf.Scale.X / p.Scale.X
+	}
+	if p.Scale.Y != 0 {
+		tf.localScale.Y = tf.Scale.Y / p.Scale.Y
+	}
+	tf.synced = tf.worldState()
+}
+
+func (tf *Transform2D) worldState() transformState {
+	return transformState{pos: tf.Pos, rotation: tf.Rotation, scale: tf.Scale, valid: true}
 }
 
 // ===== LOCK METHODS =====
@@ -0,0 +1,75 @@
+package system
+
+import (
+	"time"
+
+	"galaxyzeta.io/engine/base"
+	"galaxyzeta.io/engine/ecs/component"
+	cc "galaxyzeta.io/engine/infra/concurrency"
+)
+
+var NameAnimation2DSystem = "sys_Animation2D"
+
+// Animation2DSystem advances animators of sprite renderers by the game clock,
+// and delivers their events to callbacks of the owning objects.
+type Animation2DSystem struct {
+	*base.SystemBase
+	fixedStep
+	obj2sr map[base.IGameObject2D]*component.SpriteRenderer
+}
+
+func NewAnimation2DSystem(priority int) *Animation2DSystem {
+	return &Animation2DSystem{
+		SystemBase: base.NewSystemBase(priority),
+		fixedStep:  newFixedStep(),
+		obj2sr:     make(map[base.IGameObject2D]*component.SpriteRenderer),
+	}
+}
+
+func (s *Animation2DSystem) execute(iobj base.IGameObject2D, sr *component.SpriteRenderer) {
+	callbacks := iobj.Obj().Callbacks
+	for _, event := range sr.Animator.Step(time.Duration(s.timeStep * float64(time.Second))) {
+		if event.Finished {
+			if fx := callbacks.OnAnimationFinished; fx != nil {
+				fx(iobj, event.State)
+			}
+		} else if fx := callbacks.OnAnimationEvent; fx != nil {
+			fx(iobj, event.Name)
+		}
+	}
+	// colliders following per-frame hitboxes are refreshed here, even if the object does not move.
+	sr.SyncFrame()
+}
+
+// ===== IMPLEMENTATION =====
+
+func (s *Animation2DSystem) Execute(executor *cc.Executor) {
+	// callbacks are user code, so they are called sequentially on the game thread.
+	for iobj, sr := range s.obj2sr {
+		s.execute(iobj, sr)
+	}
+}
+
+func (s *Animation2DSystem) GetSystemBase() *base.SystemBase {
+	return s.SystemBase
+}
+
+func (s *Animation2DSystem) GetName() string {
+	return NameAnimation2DSystem
+}
+
+func (s *Animation2DSystem) Register(iobj base.IGameObject2D) {
+	s.obj2sr[iobj] = iobj.Obj().GetComponent(component.NameSpriteRenderer).(*component.SpriteRenderer)
+}
+
+func (s *Animation2DSystem) Unregister(iobj base.IGameObject2D) {
+	delete(s.obj2sr, iobj)
+}
+
+func (s *Animation2DSystem) Activate(iobj base.IGameObject2D) {
+	s.Register(iobj)
+}
+
+func (s *Animation2DSystem) Deactivate(iobj base.IGameObject2D) {
+	s.Unregister(iobj)
+}
@@ -0,0 +1,78 @@
+package system
+
+import (
+	"time"
+
+	"galaxyzeta.io/engine/base"
+	"galaxyzeta.io/engine/graphics"
+	cc "galaxyzeta.io/engine/infra/concurrency"
+)
+
+var NameCamera2DSystem = "sys_Camera2D"
+var NameCameraRender2DSystem = "sys_CameraRender2D"
+
+// Camera2DSystem updates controllers of cameras driven by a tick.
+// A system of the physics tick is a normal system stepping by the game clock,
+// and a system of the render tick is a graphics system updating before anything is drawn, by the time elapsed between frames.
+type Camera2DSystem struct {
+	*base.SystemBase
+	fixedStep
+	tick      graphics.CameraTick
+	lastFrame time.Time
+}
+
+func NewCamera2DSystem(priority int, tick graphics.CameraTick) *Camera2DSystem {
+	return &Camera2DSystem{
+		SystemBase: base.NewSystemBase(priority),
+		fixedStep:  newFixedStep(),
+		tick:       tick,
+	}
+}
+
+// PreRender is an implementation of IPreRenderSystem, only the system of the render tick updates here.
+func (s *Camera2DSystem) PreRender() {
+	if s.tick != graphics.CameraTick_Render {
+		return
+	}
+	now := time.Now()
+	if !s.lastFrame.IsZero() {
+		s.update(now.Sub(s.lastFrame).Seconds())
+	}
+	s.lastFrame = now
+}
+
+func (s *Camera2DSystem) update(dt float64) {
+	for idx := 0; idx < graphics.GetCameraCount(); idx++ {
+		controller := graphics.GetCamera(idx).Controller()
+		if controller.Tick == s.tick {
+			controller.Update(dt)
+		}
+	}
+}
+
+// ===== IMPLEMENTATION =====
+
+func (s *Camera2DSystem) Execute(_ *cc.Executor) {
+	if s.tick == graphics.CameraTick_Physics {
+		s.update(s.timeStep)
+	}
+}
+
+func (s *Camera2DSystem) GetSystemBase() *base.SystemBase {
+	return s.SystemBase
+}
+
+func (s *Camera2DSystem) GetName() string {
+	if s.tick == graphics.CameraTick_Render {
+		return NameCameraRender2DSystem
+	}
+	return NameCamera2DSystem
+}
+
+func (s *Camera2DSystem) Register(iobj base.IGameObject2D) {}
+
+func (s *Camera2DSystem) Unregister(iobj base.IGameObject2D) {}
+
+func (s *Camera2DSystem) Activate(iobj base.IGameObject2D) {}
+
+func (s *Camera2DSystem) Deactivate(iobj base.IGameObject2D) {}
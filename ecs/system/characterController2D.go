@@ -0,0 +1,209 @@
+package system
+
+import (
+	"math"
+
+	"galaxyzeta.io/engine/base"
+	"galaxyzeta.io/engine/collision"
+	"galaxyzeta.io/engine/ecs/component"
+	cc "galaxyzeta.io/engine/infra/concurrency"
+	"galaxyzeta.io/engine/linalg"
+	"galaxyzeta.io/engine/physics"
+)
+
+var NameCharacterController2DSystem = "sys_CharacterController2D"
+
+const maxResolveIterations = 4
+
+// characterWrapper wraps components required by a character.
+type characterWrapper struct {
+	*component.CharacterController2D
+	tf  *component.Transform2D
+	col *component.Collider
+}
+
+// CharacterController2DSystem moves characters by collide-and-slide against colliders in the collision system.
+type CharacterController2DSystem struct {
+	*base.SystemBase
+	fixedStep
+	csys     collision.ICollisionSystem
+	obj2data map[base.IGameObject2D]characterWrapper
+}
+
+func NewCharacterController2DSystem(priority int, csys collision.ICollisionSystem) *CharacterController2DSystem {
+	return &CharacterController2DSystem{
+		SystemBase: base.NewSystemBase(priority),
+		fixedStep:  newFixedStep(),
+		csys:       csys,
+		obj2data:   make(map[base.IGameObject2D]characterWrapper),
+	}
+}
+
+func (s *CharacterController2DSystem) execute(item characterWrapper) {
+	dt := s.timeStep
+	if item.col.Sr != nil {
+		item.col.SyncTransform()
+	}
+	// carried by moving platform
+	if delta := item.GetGroundDelta(); delta != (linalg.Vector2f64{}) {
+		item.tf.Translate(delta.X, delta.Y)
+	}
+
+	wasGrounded := item.IsGrounded()
+	groundNormal := item.GetGroundNormal()
+	jumped := item.TryConsumeJump(dt)
+	item.Velocity.X = item.GetMoveX()
+	if !wasGrounded || jumped {
+		item.Velocity.Y = math.Min(item.Velocity.Y+item.Gravity*dt, item.MaxFallSpeed)
+	} else {
+		// keep pressing the ground, so that it is still detected next step.
+		item.Velocity.Y = item.Gravity * dt
+	}
+	item.ResetContacts()
+
+	// walk along the ground, so that slopes are climbed at the same speed.
+	dx := item.Velocity.X * dt
+	if wasGrounded && !jumped && groundNormal.Y != 0 {
+		tangent := linalg.NewVector2f64(-groundNormal.Y, groundNormal.X)
+		s.moveAndResolve(item, tangent.Scale(dx), wasGrounded)
+	} else {
+		s.moveAndResolve(item, linalg.NewVector2f64(dx, 0), wasGrounded)
+	}
+	s.moveAndResolve(item, linalg.NewVector2f64(0, item.Velocity.Y*dt), false)
+
+	if wasGrounded && !jumped && !item.IsGrounded() {
+		s.snapToGround(item)
+	}
+}
+
+// moveAndResolve translates the character, and pushes it out of blocking colliders.
+func (s *CharacterController2DSystem) moveAndResolve(item characterWrapper, delta linalg.Vector2f64, canStep bool) {
+	item.tf.Translate(delta.X, delta.Y)
+	minGroundY := math.Cos(linalg.Deg2Rad(item.MaxSlopeDeg))
+	for i := 0; i < maxResolveIterations; i++ {
+		m, other, ok := s.deepestContact(item, item.col.Shape, delta)
+		if !ok {
+			return
+		}
+		push := m.Normal.Scale(-1)
+		switch {
+		case -push.Y >= minGroundY:
+			// push out vertically, otherwise the character slides down slopes.
+			item.tf.Translate(0, m.Depth/push.Y)
+			item.SetGround(other, push)
+			if item.Velocity.Y > 0 {
+				item.Velocity.Y = 0
+			}
+		case push.Y >= minGroundY:
+			item.tf.Translate(push.X*m.Depth, push.Y*m.Depth)
+			item.SetCeiling()
+			if item.Velocity.Y < 0 {
+				item.Velocity.Y = 0
+			}
+		default:
+			if canStep && s.stepUp(item) {
+				continue
+			}
+			item.tf.Translate(push.X*m.Depth, 0)
+			if push.X < 0 {
+				item.SetWall(1)
+			} else {
+				item.SetWall(-1)
+			}
+			if item.Velocity.X*push.X < 0 {
+				item.Velocity.X = 0
+			}
+		}
+	}
+}
+
+// stepUp lifts the character over a low obstacle, ground snapping will put it on the obstacle later.
+func (s *CharacterController2DSystem) stepUp(item characterWrapper) bool {
+	if item.StepHeight <= 0 {
+		return false
+	}
+	if _, _, blocked := s.deepestContact(item, item.col.Shape.Shift(0, -item.StepHeight), linalg.Vector2f64{}); blocked {
+		return false
+	}
+	item.tf.Translate(0, -item.StepHeight)
+	s.snapToGround(item)
+	return true
+}
+
+// snapToGround keeps the character on the ground when walking down slopes or stairs.
+func (s *CharacterController2DSystem) snapToGround(item characterWrapper) {
+	probe := item.StepHeight + item.SkinWidth
+	minGroundY := math.Cos(linalg.Deg2Rad(item.MaxSlopeDeg))
+	m, other, ok := s.deepestContact(item, item.col.Shape.Shift(0, probe), linalg.NewVector2f64(0, probe))
+	if !ok || m.Normal.Y < minGroundY {
+		return
+	}
+	item.tf.Translate(0, probe-m.Depth/m.Normal.Y)
+	item.SetGround(other, m.Normal.Scale(-1))
+}
+
+// deepestContact finds the blocking collider overlapping most with the shape.
+// One-way colliders block only if the character comes from the facing side, and has not sunk deeper than motion.
+func (s *CharacterController2DSystem) deepestContact(item characterWrapper, shape physics.IShape, motion linalg.Vector2f64) (physics.Manifold, *component.Collider, bool) {
+	var best physics.Manifold
+	var bestCol *component.Collider
+	probe := component.Collider{Shape: shape}
+	for _, other := range s.csys.QueryNeighborCollidersWithCollider(probe, collision.ActiveOnly) {
+		if other == item.col || other.I() == nil {
+			continue
+		}
+		if !other.OneWay && !other.I().Obj().HasTag(item.SolidTag) {
+			continue
+		}
+		m, ok := physics.Collide(shape, other.Shape)
+		if !ok || m.Depth <= best.Depth {
+			continue
+		}
+		if other.OneWay {
+			facing := other.GetOneWayNormal()
+			sunk := math.Max(-motion.Dot(facing), 0)
+			if !other.BlocksFrom(m.Normal.Scale(-1)) || item.Velocity.Dot(facing) > 0 || m.Depth > sunk+item.SkinWidth {
+				continue
+			}
+		}
+		best, bestCol = m, other
+	}
+	return best, bestCol, bestCol != nil
+}
+
+// ===== IMPLEMENTATION =====
+
+func (s *CharacterController2DSystem) Execute(executor *cc.Executor) {
+	// characters are few, and they may stand on each other, so they are moved sequentially.
+	for _, item := range s.obj2data {
+		s.execute(item)
+	}
+}
+
+func (s *CharacterController2DSystem) GetSystemBase() *base.SystemBase {
+	return s.SystemBase
+}
+
+func (s *CharacterController2DSystem) GetName() string {
+	return NameCharacterController2DSystem
+}
+
+func (s *CharacterController2DSystem) Register(iobj base.IGameObject2D) {
+	s.obj2data[iobj] = characterWrapper{
+		CharacterController2D: iobj.Obj().GetComponent(component.NameCharacterController2D).(*component.CharacterController2D),
+		tf:                    iobj.Obj().GetComponent(component.NameTransform2D).(*component.Transform2D),
+		col:                   iobj.Obj().GetComponent(component.NameCollider).(*component.Collider),
+	}
+}
+
+func (s *CharacterController2DSystem) Unregister(iobj base.IGameObject2D) {
+	delete(s.obj2data, iobj)
+}
+
+func (s *CharacterController2DSystem) Activate(iobj base.IGameObject2D) {
+	s.Register(iobj)
+}
+
+func (s *CharacterController2DSystem) Deactivate(iobj base.IGameObject2D) {
+	s.Unregister(iobj)
+}
@@ -0,0 +1,189 @@
+package system
+
+import (
+	"testing"
+
+	"galaxyzeta.io/engine/base"
+	"galaxyzeta.io/engine/ecs/component"
+	"galaxyzeta.io/engine/infra/require"
+	"galaxyzeta.io/engine/linalg"
+	"galaxyzeta.io/engine/physics"
+)
+
+// addPolygon creates a solid polygon anchored at (x, y).
+func (s *testScene) addPolygon(x float64, y float64, vertices ...linalg.Vector2f64) *testObj {
+	obj := &testObj{GameObject2D: base.NewGameObject2D("polygon")}
+	tf := component.NewTransform2D()
+	tf.Teleport(x, y)
+	poly := physics.NewPolygon(&tf.Pos, linalg.Vector2f64{}, 0, vertices)
+	obj.RegisterComponent(tf).RegisterComponent(component.NewPolygonCollider(*poly, obj)).AppendTags("solid")
+	s.csys.Register(obj)
+	return obj
+}
+
+// addCharacter creates a 16*32 character whose feet are at (x, y).
+func (s *testScene) addCharacter(x float64, y float64) (*testObj, *component.CharacterController2D) {
+	obj := &testObj{GameObject2D: base.NewGameObject2D("character")}
+	tf := component.NewTransform2D()
+	tf.Teleport(x, y)
+	controller := component.NewCharacterController2D()
+	bottom := physics.Pivot{Option: physics.PivotOption_BottomCenter}
+	obj.RegisterComponent(tf).
+		RegisterComponent(component.NewBoxCollider(&tf.Pos, 16, 32, bottom, obj)).
+		RegisterComponent(controller)
+	s.csys.Register(obj)
+	s.chars.Register(obj)
+	return obj, controller
+}
+
+// newFloorScene creates a floor whose top is at y = 100, and a character standing on it at x = 0.
+func newFloorScene() (*testScene, *testObj, *component.CharacterController2D) {
+	scene := newTestScene()
+	scene.addBox(-200, 100, 400, 20, nil)
+	obj, controller := scene.addCharacter(0, 100)
+	scene.step(2)
+	require.EqBool(true, controller.IsGrounded())
+	return scene, obj, controller
+}
+
+func TestCharacterWalksOnSlopes(t *testing.T) {
+	scene, obj, controller := newFloorScene()
+	// a 45 degree slope rising to the right from x = 20.
+	scene.addPolygon(20, 100, linalg.NewVector2f64(0, 0), linalg.NewVector2f64(200, -200), linalg.NewVector2f64(200, 0))
+	controller.Move(120)
+	scene.step(60)
+	pos := obj.tf().Pos
+	t.Log(pos)
+	require.EqBool(true, controller.IsGrounded())
+	require.EqBool(true, pos.X > 60 && pos.Y < 100-30)
+	// the slope is climbed at the walking speed, instead of the horizontal speed.
+	require.EqBool(true, pos.X < 20+120)
+
+	// standing still does not slide down.
+	controller.Move(0)
+	scene.step(10)
+	rest := obj.tf().Pos
+	scene.step(30)
+	t.Log(rest, obj.tf().Pos)
+	require.EqBool(true, nearVec(rest, obj.tf().Pos, 1e-6))
+
+	// slopes steeper than MaxSlopeDeg are walls, which could not be climbed.
+	controller.MaxSlopeDeg = 30
+	controller.Move(120)
+	scene.step(30)
+	t.Log(obj.tf().Pos)
+	require.EqBool(true, obj.tf().Pos.X < rest.X && obj.tf().Pos.Y > rest.Y)
+}
+
+func TestCharacterStepsUp(t *testing.T) {
+	scene, obj, controller := newFloorScene()
+	// a 3 pixels high step is lower than StepHeight, while a 10 pixels high one is a wall.
+	scene.addBox(30, 97, 60, 3, nil)
+	scene.addBox(150, 90, 60, 10, nil)
+	controller.Move(120)
+	scene.step(30)
+	t.Log(obj.tf().Pos)
+	require.EqBool(true, controller.IsGrounded())
+	require.EqBool(true, near(obj.tf().Pos.Y, 97, 1e-6) && obj.tf().Pos.X > 40)
+	scene.step(60)
+	t.Log(obj.tf().Pos)
+	require.EqBool(true, controller.IsOnWall() && controller.GetWallDirection() == 1)
+	require.EqBool(true, near(obj.tf().Pos.X, 150-8, 1e-6))
+}
+
+// fallOffLedge walks the character off the right edge of the floor of newFloorScene, and returns once it is airborne.
+func fallOffLedge(scene *testScene, obj *testObj, controller *component.CharacterController2D) {
+	obj.tf().Teleport(200, 100)
+	controller.Move(300)
+	for i := 0; i < 60 && controller.IsGrounded(); i++ {
+		scene.step(1)
+	}
+	require.EqBool(false, controller.IsGrounded())
+	controller.Move(0)
+}
+
+func TestCharacterCoyoteTime(t *testing.T) {
+	scene, obj, controller := newFloorScene()
+	fallOffLedge(scene, obj, controller)
+	// jumping right after leaving the ground is still allowed.
+	scene.step(2)
+	controller.Jump()
+	scene.step(1)
+	t.Log(controller.Velocity)
+	require.EqBool(true, controller.Velocity.Y < 0)
+
+	scene, obj, controller = newFloorScene()
+	fallOffLedge(scene, obj, controller)
+	// but not after CoyoteTime.
+	scene.step(12)
+	controller.Jump()
+	scene.step(1)
+	t.Log(controller.Velocity)
+	require.EqBool(true, controller.Velocity.Y > 0)
+}
+
+// jumpsAfterLanding drops the character from a height with jump pressed, and tells whether it jumps after landing.
+func jumpsAfterLanding(height float64) bool {
+	scene := newTestScene()
+	scene.addBox(-200, 100, 400, 20, nil)
+	_, controller := scene.addCharacter(0, 100-height)
+	controller.Jump()
+	landed := false
+	for i := 0; i < 60; i++ {
+		scene.step(1)
+		landed = landed || controller.IsGrounded()
+		if landed && controller.Velocity.Y < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCharacterJumpBuffer(t *testing.T) {
+	// landing within JumpBufferTime after pressing jump.
+	require.EqBool(true, jumpsAfterLanding(4))
+	// landing too late.
+	require.EqBool(false, jumpsAfterLanding(60))
+}
+
+// jumpApex jumps from the floor, releases jump after given steps, and returns the highest position of feet.
+func jumpApex(releaseAfter int) float64 {
+	scene, obj, controller := newFloorScene()
+	controller.Jump()
+	apex := obj.tf().Pos.Y
+	for i := 0; i < 60; i++ {
+		if i == releaseAfter {
+			controller.ReleaseJump()
+		}
+		scene.step(1)
+		if obj.tf().Pos.Y < apex {
+			apex = obj.tf().Pos.Y
+		}
+	}
+	return apex
+}
+
+func TestCharacterVariableJumpHeight(t *testing.T) {
+	full := jumpApex(60)
+	short := jumpApex(1)
+	t.Log(full, short)
+	// JumpSpeed of 500 and Gravity of 2000 reach about 62.5 pixels high.
+	require.EqBool(true, near(100-full, 62.5, 10))
+	require.EqBool(true, 100-short < (100-full)/2)
+}
+
+func TestCharacterCarriedByPlatform(t *testing.T) {
+	scene := newTestScene()
+	platform := scene.addBox(-50, 100, 100, 10, nil)
+	obj, controller := scene.addCharacter(0, 100)
+	scene.step(2)
+	require.EqBool(true, controller.IsGrounded())
+	// the platform is moved by editing its transform, such as in OnStep.
+	for i := 0; i < 30; i++ {
+		platform.tf().Translate(2, -1)
+		scene.step(1)
+	}
+	t.Log(platform.tf().Pos, obj.tf().Pos)
+	require.EqBool(true, controller.IsGrounded() && controller.GetGround() == platform.col())
+	require.EqBool(true, nearVec(obj.tf().Pos, linalg.NewVector2f64(60, 70), 1e-6))
+}
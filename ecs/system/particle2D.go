@@ -0,0 +1,75 @@
+package system
+
+import (
+	"sync"
+
+	"galaxyzeta.io/engine/base"
+	"galaxyzeta.io/engine/collision"
+	"galaxyzeta.io/engine/ecs/component"
+	cc "galaxyzeta.io/engine/infra/concurrency"
+	"galaxyzeta.io/engine/linalg"
+)
+
+var NameParticle2DSystem = "sys_Particle2D"
+
+// Particle2DSystem simulates particle emitters by the game clock, particles collide with solid colliders of the collision system.
+type Particle2DSystem struct {
+	*base.SystemBase
+	fixedStep
+	csys        collision.ICollisionSystem
+	obj2emitter map[base.IGameObject2D]*component.ParticleEmitter
+}
+
+func NewParticle2DSystem(priority int, csys collision.ICollisionSystem) *Particle2DSystem {
+	return &Particle2DSystem{
+		SystemBase:  base.NewSystemBase(priority),
+		fixedStep:   newFixedStep(),
+		csys:        csys,
+		obj2emitter: make(map[base.IGameObject2D]*component.ParticleEmitter),
+	}
+}
+
+func (s *Particle2DSystem) execute(emitter *component.ParticleEmitter) {
+	emitter.Step(s.timeStep, func(p linalg.Vector2f64) bool {
+		return collision.ColliderAtWithTag(s.csys, p, emitter.SolidTag, collision.ActiveOnly) != nil
+	})
+}
+
+// ===== IMPLEMENTATION =====
+
+func (s *Particle2DSystem) Execute(executor *cc.Executor) {
+	// emitters are independent of each other, so they are simulated in parallel.
+	wg := sync.WaitGroup{}
+	for _, emitter := range s.obj2emitter {
+		emitter := emitter
+		executor.AsyncExecute(func() (interface{}, error) {
+			s.execute(emitter)
+			return nil, nil
+		}, &wg)
+	}
+	wg.Wait()
+}
+
+func (s *Particle2DSystem) GetSystemBase() *base.SystemBase {
+	return s.SystemBase
+}
+
+func (s *Particle2DSystem) GetName() string {
+	return NameParticle2DSystem
+}
+
+func (s *Particle2DSystem) Register(iobj base.IGameObject2D) {
+	s.obj2emitter[iobj] = iobj.Obj().GetComponent(component.NameParticleEmitter).(*component.ParticleEmitter)
+}
+
+func (s *Particle2DSystem) Unregister(iobj base.IGameObject2D) {
+	delete(s.obj2emitter, iobj)
+}
+
+func (s *Particle2DSystem) Activate(iobj base.IGameObject2D) {
+	s.Register(iobj)
+}
+
+func (s *Particle2DSystem) Deactivate(iobj base.IGameObject2D) {
+	s.Unregister(iobj)
+}
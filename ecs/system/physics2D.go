@@ -10,40 +10,137 @@ import (
 	"galaxyzeta.io/engine/collision"
 	"galaxyzeta.io/engine/ecs/component"
 	cc "galaxyzeta.io/engine/infra/concurrency"
+	"galaxyzeta.io/engine/infra/concurrency/lock"
 	"galaxyzeta.io/engine/infra/logger"
 	"galaxyzeta.io/engine/linalg"
+	"galaxyzeta.io/engine/physics"
 )
 
 var NamePhysics2DSystem = "sys_Physics2D"
 
+const kinematicBatchSize = 32
+
 // PhysicalComponentWrapper wraps RigidBody2D and Transform component.
 type PhysicalComponentWrapper struct {
 	*component.RigidBody2D
 	*component.Transform2D
-	*component.PolygonCollider
+	*component.Collider
 }
 
+// Physics2DSystem moves kinematic bodies by their speed vectors, and simulates dynamic bodies with an impulse solver.
+// GameObjects with effectors could also subscribe to it, effectors are applied to dynamic bodies overlapping them.
 type Physics2DSystem struct {
 	*base.SystemBase
-	csys     collision.ICollisionSystem
-	obj2data map[base.IGameObject2D]PhysicalComponentWrapper
-	logger   *logger.Logger
+	fixedStep
+	csys      collision.ICollisionSystem
+	obj2data  map[base.IGameObject2D]PhysicalComponentWrapper
+	effectors map[base.IGameObject2D]effectorWrapper
+	logger    *logger.Logger
+	gravity   linalg.Vector2f64 // gravity acceleration of dynamic bodies.
+	// impulses of last step, used to warm start the solver.
+	contactCache map[contactKey]*solverContact
+	// surroundings of sleeping bodies in last step, changes of which wake them up.
+	sleepSnapshots map[*component.Collider]sleepSnapshot
+	joints         []IJoint2D
+	jointedPairs   map[contactKey]struct{}         // colliders not colliding because of joints.
+	inactive       map[base.IGameObject2D]struct{} // deactivated gameObjects, whose joints are kept but not solved.
+
+	debugEnabled bool
+	debugMu      lock.SpinLock
+	debugBodies  []DebugBody  // snapshot of bodies after last step, read by render thread.
+	debugJoints  []DebugJoint // snapshot of joints after last step, read by render thread.
+}
+
+// DebugBody is a snapshot of a body for debug drawing.
+type DebugBody struct {
+	Shape    physics.IShape
+	BodyType component.BodyType
+	Sleeping bool
+}
+
+// DebugJoint is a snapshot of a joint for debug drawing, CenterB equals AnchorB if the joint is pinned to the world.
+type DebugJoint struct {
+	CenterA linalg.Vector2f64
+	AnchorA linalg.Vector2f64
+	AnchorB linalg.Vector2f64
+	CenterB linalg.Vector2f64
 }
 
 func NewPhysics2DSystem(prioriy int, csys collision.ICollisionSystem) *Physics2DSystem {
 	return &Physics2DSystem{
 		obj2data:   make(map[base.IGameObject2D]PhysicalComponentWrapper, 64),
+		effectors:  make(map[base.IGameObject2D]effectorWrapper),
+		inactive:   make(map[base.IGameObject2D]struct{}),
 		SystemBase: base.NewSystemBase(prioriy),
+		fixedStep:  newFixedStep(),
 		csys:       csys,
 		logger:     logger.New("Physics2D"),
+		gravity:    DefaultGravity,
 	}
 }
 
+// SetDebugEnabled decides whether to take snapshots of bodies for debug drawing after each step.
+func (s *Physics2DSystem) SetDebugEnabled(enabled bool) {
+	s.debugEnabled = enabled
+}
+
+// DebugBodies returns the snapshot of bodies taken after last step. It is thread-safe.
+func (s *Physics2DSystem) DebugBodies() []DebugBody {
+	s.debugMu.Lock()
+	defer s.debugMu.Unlock()
+	return s.debugBodies
+}
+
+// DebugJoints returns the snapshot of joints taken after last step. It is thread-safe.
+func (s *Physics2DSystem) DebugJoints() []DebugJoint {
+	s.debugMu.Lock()
+	defer s.debugMu.Unlock()
+	return s.debugJoints
+}
+
+func (s *Physics2DSystem) takeDebugSnapshot() {
+	bodies := make([]DebugBody, 0, len(s.obj2data))
+	for _, item := range s.obj2data {
+		if item.Collider == nil {
+			continue
+		}
+		bodies = append(bodies, DebugBody{
+			Shape:    item.Shape,
+			BodyType: item.RigidBody2D.BodyType,
+			Sleeping: item.RigidBody2D.IsSleeping(),
+		})
+	}
+	joints := make([]DebugJoint, 0, len(s.joints))
+	for _, joint := range s.joints {
+		jb := joint.GetJointBase()
+		anchorA, anchorB := jb.GetWorldAnchors()
+		centerB := anchorB
+		if jb.BodyB != nil {
+			centerB = worldAnchor(jb.BodyB, linalg.Vector2f64{})
+		}
+		joints = append(joints, DebugJoint{
+			CenterA: worldAnchor(jb.BodyA, linalg.Vector2f64{}),
+			AnchorA: anchorA,
+			AnchorB: anchorB,
+			CenterB: centerB,
+		})
+	}
+	s.debugMu.Lock()
+	s.debugBodies = bodies
+	s.debugJoints = joints
+	s.debugMu.Unlock()
+}
+
+// SetGravity sets gravity acceleration of dynamic bodies, in pixels per second squared.
+func (s *Physics2DSystem) SetGravity(g linalg.Vector2f64) {
+	s.gravity = g
+}
+
 func (s *Physics2DSystem) execute(item PhysicalComponentWrapper) {
 	// if item dynamically follows an SpriteRenderer's hitbox,
-	// set its item.Collider dynamically.
+	// rebuild its item.Shape once the frame has changed.
 	if item.Sr != nil {
-		item.Collider = item.Sr.GetHitbox()
+		item.Collider.SyncTransform()
 	}
 	// handle speed vectors
 	linkedList := item.RigidBody2D.GetSpeedList()
@@ -77,7 +174,7 @@ func (s *Physics2DSystem) execute(item PhysicalComponentWrapper) {
 		gdeg := linalg.Deg2Rad(linalg.InvertDeg(item.GravityVector.Direction))
 		gdx := item.GravityVector.Speed * math.Cos(gdeg)
 		gdy := item.GravityVector.Speed * math.Sin(gdeg)
-		if collision.HasColliderAtPolygonWithTag(s.csys, item.Collider.Shift(dx+gdx, dy+gdy), "solid", collision.ActiveOnly) {
+		if collision.HasColliderAtPolygonWithTag(s.csys, item.Shape.Shift(dx+gdx, dy+gdy), "solid", collision.ActiveOnly) {
 			// grounded
 			item.GravityVector.Speed = 0
 		} else {
@@ -93,17 +190,17 @@ func (s *Physics2DSystem) execute(item PhysicalComponentWrapper) {
 	item.RigidBody2D.SetVspeed(dy)
 
 	// calc position
-	if item.PolygonCollider == nil {
+	if item.Collider == nil {
 		return
 	}
 	// reject collision caused movement
-	if !collision.HasColliderAtPolygonWithTag(s.csys, item.Collider.Shift(dx, 0), "solid", collision.ActiveOnly) {
-		item.Transform2D.Pos.X += dx
+	if !collision.HasColliderAtPolygonWithTag(s.csys, item.Shape.Shift(dx, 0), "solid", collision.ActiveOnly) {
+		item.Transform2D.Translate(dx, 0)
 	} else {
 		fmt.Print(1)
 	}
-	if !collision.HasColliderAtPolygonWithTag(s.csys, item.Collider.Shift(0, dy), "solid", collision.ActiveOnly) {
-		item.Transform2D.Pos.Y += dy
+	if !collision.HasColliderAtPolygonWithTag(s.csys, item.Shape.Shift(0, dy), "solid", collision.ActiveOnly) {
+		item.Transform2D.Translate(0, dy)
 	} else {
 		fmt.Print(1)
 	}
@@ -112,14 +209,41 @@ func (s *Physics2DSystem) execute(item PhysicalComponentWrapper) {
 // ===== IMPLEMENTATION =====
 
 func (s *Physics2DSystem) Execute(executor *cc.Executor) {
-	wg := sync.WaitGroup{}
+	// kinematic bodies are independent, they are moved in batches to reduce scheduling overhead.
+	kinematics := make([]PhysicalComponentWrapper, 0, len(s.obj2data))
 	for _, item := range s.obj2data {
+		if item.RigidBody2D.BodyType != component.BodyType_Kinematic {
+			continue
+		}
+		if item.RigidBody2D.GetSpeedList().Len() == 0 && !item.UseGravity && item.Sr == nil {
+			// nothing could move the body, it rests in place.
+			item.RigidBody2D.SetHspeed(0)
+			item.RigidBody2D.SetVspeed(0)
+			continue
+		}
+		kinematics = append(kinematics, item)
+	}
+	wg := sync.WaitGroup{}
+	for i := 0; i < len(kinematics); i += kinematicBatchSize {
+		end := i + kinematicBatchSize
+		if end > len(kinematics) {
+			end = len(kinematics)
+		}
+		batch := kinematics[i:end]
 		executor.AsyncExecute(func() (interface{}, error) {
-			s.execute(item)
+			for _, item := range batch {
+				s.execute(item)
+			}
 			return nil, nil
 		}, &wg)
 	}
 	wg.Wait()
+	s.applyEffectors()
+	// contacts between dynamic bodies depend on each other, so they are solved sequentially.
+	s.stepDynamics()
+	if s.debugEnabled {
+		s.takeDebugSnapshot()
+	}
 }
 
 func (s *Physics2DSystem) GetSystemBase() *base.SystemBase {
@@ -131,24 +255,50 @@ func (s *Physics2DSystem) GetName() string {
 }
 
 func (s *Physics2DSystem) Register(iobj base.IGameObject2D) {
+	if effector, ok := newEffectorWrapper(iobj.Obj()); ok {
+		s.effectors[iobj] = effector
+		if _, ok := iobj.Obj().TryGetComponent(component.NameRigidBody2D); !ok {
+			// an effector without rigidbody, such as a conveyor belt.
+			return
+		}
+	}
 	rb := iobj.Obj().GetComponent(component.NameRigidBody2D).(*component.RigidBody2D)
 	tf := iobj.Obj().GetComponent(component.NameTransform2D).(*component.Transform2D)
-	pc := iobj.Obj().GetComponent(component.NamePolygonCollider).(*component.PolygonCollider)
+	pc := iobj.Obj().GetComponent(component.NameCollider).(*component.Collider)
+	bindColliderTransform(iobj, pc)
+	if rb.BodyType == component.BodyType_Dynamic {
+		if rb.GetMass() == 0 {
+			// a massless dynamic body is likely a config error, it should be made static to be immovable.
+			s.logger.Warnf("dynamic body %s has no mass, the default mass 1 is used", iobj.Obj().Name)
+			rb.SetMass(1)
+		}
+		if rb.GetInertia() == 0 {
+			rb.SetInertia(physics.MomentOfInertia(pc.Shape, rb.GetMass()))
+		}
+	}
 	s.obj2data[iobj] = PhysicalComponentWrapper{
-		RigidBody2D:     rb,
-		Transform2D:     tf,
-		PolygonCollider: pc,
+		RigidBody2D: rb,
+		Transform2D: tf,
+		Collider:    pc,
 	}
 }
 
+// Unregister removes the gameObject as it is destroyed, joints connected to it are removed as well.
 func (s *Physics2DSystem) Unregister(iobj base.IGameObject2D) {
 	delete(s.obj2data, iobj)
+	delete(s.effectors, iobj)
+	delete(s.inactive, iobj)
+	s.removeJointsOf(iobj)
 }
 
 func (s *Physics2DSystem) Activate(iobj base.IGameObject2D) {
+	delete(s.inactive, iobj)
 	s.Register(iobj)
 }
 
+// Deactivate stops simulating the gameObject, joints connected to it are kept until it is activated again.
 func (s *Physics2DSystem) Deactivate(iobj base.IGameObject2D) {
-	s.Unregister(iobj)
+	delete(s.obj2data, iobj)
+	delete(s.effectors, iobj)
+	s.inactive[iobj] = struct{}{}
 }
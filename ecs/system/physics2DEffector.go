@@ -0,0 +1,140 @@
+package system
+
+import (
+	"math"
+
+	"galaxyzeta.io/engine/base"
+	"galaxyzeta.io/engine/collision"
+	"galaxyzeta.io/engine/ecs/component"
+	"galaxyzeta.io/engine/linalg"
+	"galaxyzeta.io/engine/physics"
+)
+
+// effectorWrapper wraps effectors of a gameObject and the collider they act through, absent effectors are nil.
+type effectorWrapper struct {
+	col      *component.Collider
+	conveyor *component.ConveyorEffector2D
+	bounce   *component.BounceEffector2D
+	buoyancy *component.BuoyancyEffector2D
+	point    *component.PointEffector2D
+}
+
+// newEffectorWrapper collects effectors of a gameObject, ok is false if it has none.
+func newEffectorWrapper(obj *base.GameObject2D) (ret effectorWrapper, ok bool) {
+	if c, found := obj.TryGetComponent(component.NameConveyorEffector2D); found {
+		ret.conveyor = c.(*component.ConveyorEffector2D)
+	}
+	if c, found := obj.TryGetComponent(component.NameBounceEffector2D); found {
+		ret.bounce = c.(*component.BounceEffector2D)
+	}
+	if c, found := obj.TryGetComponent(component.NameBuoyancyEffector2D); found {
+		ret.buoyancy = c.(*component.BuoyancyEffector2D)
+	}
+	if c, found := obj.TryGetComponent(component.NamePointEffector2D); found {
+		ret.point = c.(*component.PointEffector2D)
+	}
+	if ret.conveyor == nil && ret.bounce == nil && ret.buoyancy == nil && ret.point == nil {
+		return ret, false
+	}
+	ret.col = obj.GetComponent(component.NameCollider).(*component.Collider)
+	return ret, true
+}
+
+// applyEffectors changes velocity or applies forces to dynamic bodies overlapping effectors.
+// It runs before stepDynamics, so that changes take effect in the same step.
+func (s *Physics2DSystem) applyEffectors() {
+	if len(s.effectors) == 0 {
+		return
+	}
+	dynamics := make(map[*component.Collider]PhysicalComponentWrapper)
+	for _, item := range s.obj2data {
+		if item.RigidBody2D.BodyType == component.BodyType_Dynamic && item.Collider != nil {
+			dynamics[item.Collider] = item
+		}
+	}
+	for _, e := range s.effectors {
+		if e.col.Sr != nil {
+			e.col.SyncTransform()
+		}
+		for _, other := range s.csys.QueryNeighborCollidersWithCollider(*e.col, collision.ActiveOnly) {
+			item, ok := dynamics[other]
+			if !ok {
+				continue
+			}
+			m, hit := physics.Collide(e.col.Shape, other.Shape)
+			if !hit || !e.col.BlocksFrom(m.Normal) {
+				continue
+			}
+			e.apply(item, m, s.gravity)
+		}
+	}
+}
+
+// conveyorSpeed returns the surface speed of a collider, zero if it is not a conveyor belt.
+func (s *Physics2DSystem) conveyorSpeed(col *component.Collider) float64 {
+	if col.I() == nil {
+		return 0
+	}
+	if e, ok := s.effectors[col.I()]; ok && e.conveyor != nil {
+		return e.conveyor.Speed
+	}
+	return 0
+}
+
+// apply acts on a body, m is the manifold from the effector to the body.
+func (e effectorWrapper) apply(item PhysicalComponentWrapper, m physics.Manifold, gravity linalg.Vector2f64) {
+	rb := item.RigidBody2D
+	if e.conveyor != nil && e.conveyor.Speed != 0 {
+		tangent := m.Normal.NormalVec()
+		dv := (e.conveyor.Speed - rb.Velocity.Dot(tangent)) * math.Max(0, math.Min(e.conveyor.ForceScale, 1))
+		rb.WakeUp()
+		rb.Velocity = rb.Velocity.Add(tangent.Scale(dv))
+	}
+	// only bodies landing on or resting on the pad are launched, otherwise it keeps pushing the leaving body.
+	if vn := rb.Velocity.Dot(m.Normal); e.bounce != nil && vn < e.bounce.Speed && vn <= sleepLinearVelocity {
+		rb.WakeUp()
+		rb.Velocity = rb.Velocity.Add(m.Normal.Scale(e.bounce.Speed - vn))
+	}
+	// area effectors leave sleeping bodies alone, they have reached a balance.
+	if rb.IsSleeping() {
+		return
+	}
+	if e.buoyancy != nil {
+		e.applyBuoyancy(item, gravity)
+	}
+	if e.point != nil {
+		center := boundingBoxCenter(e.col.Shape.GetBoundingBox())
+		delta := boundingBoxCenter(item.Shape.GetBoundingBox()).Sub(center)
+		if dist := delta.Magnitude(); dist > 0 {
+			rb.ApplyForce(delta.Scale(e.point.ForceAt(dist) / dist))
+		}
+	}
+}
+
+// applyBuoyancy pushes the body up against gravity and applies drag, in proportion to its submerged height.
+func (e effectorWrapper) applyBuoyancy(item PhysicalComponentWrapper, gravity linalg.Vector2f64) {
+	rb := item.RigidBody2D
+	bb := item.Shape.GetBoundingBox()
+	top := bb.GetTopLeftPoint().Y
+	bottom := bb.GetBottomLeftPoint().Y
+	surface := e.col.Shape.GetBoundingBox().GetTopLeftPoint().Y
+	if bottom <= top {
+		return
+	}
+	submerged := math.Max(0, math.Min((bottom-math.Max(top, surface))/(bottom-top), 1))
+	if submerged == 0 {
+		return
+	}
+	force := rb.Velocity.Sub(e.buoyancy.FlowVelocity).Scale(-e.buoyancy.LinearDrag * rb.GetMass() * submerged)
+	if rb.UseGravity {
+		force = force.Add(gravity.Scale(-rb.GravityScale * rb.GetMass() * e.buoyancy.Density * submerged))
+	}
+	rb.ApplyForce(force)
+	if rb.AngularVelocity != 0 {
+		rb.ApplyTorque(-linalg.Deg2Rad(rb.AngularVelocity) * e.buoyancy.AngularDrag * rb.GetInertia() * submerged)
+	}
+}
+
+func boundingBoxCenter(bb physics.BoundingBox) linalg.Vector2f64 {
+	return bb.GetTopLeftPoint().Add(bb.GetBottomRightPoint()).Scale(0.5)
+}
@@ -0,0 +1,665 @@
+package system
+
+import (
+	"math"
+
+	"galaxyzeta.io/engine/base"
+	"galaxyzeta.io/engine/ecs/component"
+	"galaxyzeta.io/engine/linalg"
+)
+
+const (
+	jointBaumgarte = 0.2  // portion of joint error fixed each step by velocity bias.
+	jointEpsilon   = 1e-9 // anchors closer than this in pixels are considered coincident.
+)
+
+// IJoint2D is a constraint between two bodies, solved by Physics2DSystem along with contacts.
+type IJoint2D interface {
+	GetJointBase() *Joint2DBase
+	prepare(dt float64)     // prepare computes effective masses, and applies impulses of last step for warm starting.
+	solve(dt float64)       // solve applies impulses to satisfy the constraint, it is called once per iteration.
+	linearImpulse() float64 // linearImpulse returns the magnitude of linear impulse accumulated in this step.
+}
+
+// Joint2DBase holds properties shared by all joints.
+// Anchors are in body space, which is scaled and rotated by the transform and placed on its position,
+// while bodies rotate around their centroids. If BodyB is nil, the joint is pinned to the world and LocalAnchorB is a world point.
+type Joint2DBase struct {
+	BodyA            base.IGameObject2D
+	BodyB            base.IGameObject2D
+	LocalAnchorA     linalg.Vector2f64
+	LocalAnchorB     linalg.Vector2f64
+	BreakForce       float64 // the joint breaks if its reaction force exceeds this, zero means unbreakable.
+	CollideConnected bool    // whether the connected bodies still collide with each other.
+
+	broken        bool
+	reactionForce float64
+	// bound while solving
+	a      *solverBody
+	b      *solverBody
+	angleA float64 // radians
+	angleB float64
+	ra     linalg.Vector2f64 // anchors relative to centroids of bodies in world space.
+	rb     linalg.Vector2f64
+}
+
+func newJoint2DBase(a base.IGameObject2D, b base.IGameObject2D, anchorA linalg.Vector2f64, anchorB linalg.Vector2f64) Joint2DBase {
+	if a == nil {
+		panic("body A of a joint should not be nil")
+	}
+	return Joint2DBase{
+		BodyA:        a,
+		BodyB:        b,
+		LocalAnchorA: anchorA,
+		LocalAnchorB: anchorB,
+	}
+}
+
+func (j *Joint2DBase) GetJointBase() *Joint2DBase {
+	return j
+}
+
+// IsBroken tells whether the joint has been broken by a force over BreakForce.
+func (j *Joint2DBase) IsBroken() bool {
+	return j.broken
+}
+
+// GetReactionForce returns the force the joint applied to keep the bodies together in last step.
+func (j *Joint2DBase) GetReactionForce() float64 {
+	return j.reactionForce
+}
+
+// GetWorldAnchors returns the anchors on both bodies in world space.
+func (j *Joint2DBase) GetWorldAnchors() (linalg.Vector2f64, linalg.Vector2f64) {
+	return worldAnchor(j.BodyA, j.LocalAnchorA), worldAnchor(j.BodyB, j.LocalAnchorB)
+}
+
+func (j *Joint2DBase) bind(a *solverBody, b *solverBody) {
+	j.a, j.b = a, b
+	j.angleA = linalg.Deg2Rad(bodyAngle(j.BodyA))
+	j.angleB = linalg.Deg2Rad(bodyAngle(j.BodyB))
+	j.ra = worldAnchor(j.BodyA, j.LocalAnchorA).Sub(a.center)
+	j.rb = worldAnchor(j.BodyB, j.LocalAnchorB).Sub(b.center)
+}
+
+// separation returns the vector from anchor A to anchor B.
+func (j *Joint2DBase) separation() linalg.Vector2f64 {
+	return j.b.center.Add(j.rb).Sub(j.a.center.Add(j.ra))
+}
+
+// relativeVelocity returns velocity of anchor B relative to anchor A.
+func (j *Joint2DBase) relativeVelocity() linalg.Vector2f64 {
+	return j.b.pointVelocity(j.b.center.Add(j.rb)).Sub(j.a.pointVelocity(j.a.center.Add(j.ra)))
+}
+
+// applyLinear applies impulse p to B at its anchor, and -p to A.
+func (j *Joint2DBase) applyLinear(p linalg.Vector2f64) {
+	j.a.velocity = j.a.velocity.Sub(p.Scale(j.a.invMass))
+	j.a.angular -= j.ra.Mult(p) * j.a.invInertia
+	j.b.velocity = j.b.velocity.Add(p.Scale(j.b.invMass))
+	j.b.angular += j.rb.Mult(p) * j.b.invInertia
+}
+
+// applyAngular applies angular impulse l to B, and -l to A.
+func (j *Joint2DBase) applyAngular(l float64) {
+	j.a.angular -= l * j.a.invInertia
+	j.b.angular += l * j.b.invInertia
+}
+
+// applyAlong applies impulse l along dir, sa and sb are lever arms of A and B crossed with dir.
+func (j *Joint2DBase) applyAlong(dir linalg.Vector2f64, sa float64, sb float64, l float64) {
+	j.a.velocity = j.a.velocity.Sub(dir.Scale(l * j.a.invMass))
+	j.a.angular -= l * sa * j.a.invInertia
+	j.b.velocity = j.b.velocity.Add(dir.Scale(l * j.b.invMass))
+	j.b.angular += l * sb * j.b.invInertia
+}
+
+// pointMatrix returns the 2x2 effective mass matrix of a point-to-point constraint as k11, k12, k22.
+func (j *Joint2DBase) pointMatrix() (float64, float64, float64) {
+	mA, mB, iA, iB := j.a.invMass, j.b.invMass, j.a.invInertia, j.b.invInertia
+	k11 := mA + mB + iA*j.ra.Y*j.ra.Y + iB*j.rb.Y*j.rb.Y
+	k12 := -iA*j.ra.X*j.ra.Y - iB*j.rb.X*j.rb.Y
+	k22 := mA + mB + iA*j.ra.X*j.ra.X + iB*j.rb.X*j.rb.X
+	return k11, k12, k22
+}
+
+// solvePoint solves the 2x2 system of a point-to-point constraint, it returns zero if the matrix is singular.
+func solvePoint(k11 float64, k12 float64, k22 float64, rhs linalg.Vector2f64) linalg.Vector2f64 {
+	det := k11*k22 - k12*k12
+	if det == 0 {
+		return linalg.Vector2f64{}
+	}
+	return linalg.NewVector2f64((k22*rhs.X-k12*rhs.Y)/det, (k11*rhs.Y-k12*rhs.X)/det)
+}
+
+// lineMass returns the effective mass along dir, sa and sb are lever arms of A and B crossed with dir.
+func (j *Joint2DBase) lineMass(sa float64, sb float64) float64 {
+	k := j.a.invMass + j.b.invMass + j.a.invInertia*sa*sa + j.b.invInertia*sb*sb
+	if k == 0 {
+		return 0
+	}
+	return 1 / k
+}
+
+func (j *Joint2DBase) angularMass() float64 {
+	if k := j.a.invInertia + j.b.invInertia; k != 0 {
+		return 1 / k
+	}
+	return 0
+}
+
+// limitBias returns the velocity bias of an inequality constraint, C is positive when the limit is not reached.
+// A positive C allows bodies to approach the limit in this step without crossing it.
+func limitBias(c float64, dt float64) float64 {
+	if c > 0 {
+		return c / dt
+	}
+	return jointBaumgarte * c / dt
+}
+
+// ===== distance joint =====
+
+// DistanceJoint2D keeps the anchors of two bodies at a fixed distance, like a rigid rod.
+// As a rope, it only keeps them from getting farther than Length.
+type DistanceJoint2D struct {
+	Joint2DBase
+	Length float64 // zero means the distance when the joint is solved for the first time.
+	Rope   bool
+
+	n       linalg.Vector2f64
+	mass    float64
+	bias    float64
+	slack   bool
+	impulse float64
+}
+
+func NewDistanceJoint2D(a base.IGameObject2D, b base.IGameObject2D, anchorA linalg.Vector2f64, anchorB linalg.Vector2f64, length float64) *DistanceJoint2D {
+	return &DistanceJoint2D{
+		Joint2DBase: newJoint2DBase(a, b, anchorA, anchorB),
+		Length:      length,
+	}
+}
+
+func (j *DistanceJoint2D) prepare(dt float64) {
+	d := j.separation()
+	dist := d.Magnitude()
+	if j.Length <= 0 {
+		j.Length = dist
+	}
+	c := dist - j.Length
+	j.slack = dist < jointEpsilon || (j.Rope && c <= 0)
+	if j.slack {
+		j.impulse = 0
+		return
+	}
+	j.n = d.Scale(1 / dist)
+	j.mass = j.lineMass(j.ra.Mult(j.n), j.rb.Mult(j.n))
+	j.bias = jointBaumgarte * c / dt
+	j.applyLinear(j.n.Scale(j.impulse))
+}
+
+func (j *DistanceJoint2D) solve(dt float64) {
+	if j.slack {
+		return
+	}
+	l := -j.mass * (j.relativeVelocity().Dot(j.n) + j.bias)
+	old := j.impulse
+	j.impulse += l
+	if j.Rope {
+		// a rope could only pull.
+		j.impulse = math.Min(j.impulse, 0)
+	}
+	j.applyLinear(j.n.Scale(j.impulse - old))
+}
+
+func (j *DistanceJoint2D) linearImpulse() float64 {
+	return math.Abs(j.impulse)
+}
+
+// ===== spring joint =====
+
+// SpringJoint2D pulls the anchors of two bodies to a rest length softly, like a spring with a damper.
+type SpringJoint2D struct {
+	Joint2DBase
+	Length       float64 // rest length, zero means the distance when the joint is solved for the first time.
+	Frequency    float64 // oscillations per second, zero makes the spring rigid.
+	DampingRatio float64 // zero means no damping, one means critical damping.
+
+	n       linalg.Vector2f64
+	mass    float64
+	bias    float64
+	gamma   float64
+	slack   bool
+	impulse float64
+}
+
+func NewSpringJoint2D(a base.IGameObject2D, b base.IGameObject2D, anchorA linalg.Vector2f64, anchorB linalg.Vector2f64, length float64, frequency float64, dampingRatio float64) *SpringJoint2D {
+	return &SpringJoint2D{
+		Joint2DBase:  newJoint2DBase(a, b, anchorA, anchorB),
+		Length:       length,
+		Frequency:    frequency,
+		DampingRatio: dampingRatio,
+	}
+}
+
+func (j *SpringJoint2D) prepare(dt float64) {
+	d := j.separation()
+	dist := d.Magnitude()
+	if j.Length <= 0 {
+		j.Length = dist
+	}
+	j.slack = dist < jointEpsilon
+	if j.slack {
+		j.impulse = 0
+		return
+	}
+	j.n = d.Scale(1 / dist)
+	mass := j.lineMass(j.ra.Mult(j.n), j.rb.Mult(j.n))
+	c := dist - j.Length
+	j.mass, j.bias, j.gamma = mass, jointBaumgarte*c/dt, 0
+	if j.Frequency > 0 && mass > 0 {
+		// soft constraint, see "Soft Constraints" by Erin Catto.
+		omega := 2 * math.Pi * j.Frequency
+		stiffness := mass * omega * omega
+		damping := 2 * mass * j.DampingRatio * omega
+		j.gamma = 1 / (dt * (damping + dt*stiffness))
+		j.bias = c * dt * stiffness * j.gamma
+		j.mass = 1 / (1/mass + j.gamma)
+	}
+	j.applyLinear(j.n.Scale(j.impulse))
+}
+
+func (j *SpringJoint2D) solve(dt float64) {
+	if j.slack {
+		return
+	}
+	l := -j.mass * (j.relativeVelocity().Dot(j.n) + j.bias + j.gamma*j.impulse)
+	j.impulse += l
+	j.applyLinear(j.n.Scale(l))
+}
+
+func (j *SpringJoint2D) linearImpulse() float64 {
+	return math.Abs(j.impulse)
+}
+
+// ===== revolute joint =====
+
+// RevoluteJoint2D pins two bodies together at the anchors, and lets them rotate freely, like a hinge.
+// The relative angle could be limited, and a motor could drive the rotation.
+type RevoluteJoint2D struct {
+	Joint2DBase
+	ReferenceAngle float64 // relative angle of B to A in degrees, where the joint angle is zero.
+	EnableLimit    bool
+	LowerAngle     float64 // degrees.
+	UpperAngle     float64 // degrees.
+	EnableMotor    bool
+	MotorSpeed     float64 // degrees per second.
+	MaxMotorTorque float64
+
+	k11, k12, k22 float64
+	bias          linalg.Vector2f64
+	axialMass     float64
+	angle         float64 // radians.
+	impulse       linalg.Vector2f64
+	motorImpulse  float64
+	lowerImpulse  float64
+	upperImpulse  float64
+}
+
+// NewRevoluteJoint2D creates a hinge, the current relative angle of bodies is used as the reference angle.
+func NewRevoluteJoint2D(a base.IGameObject2D, b base.IGameObject2D, anchorA linalg.Vector2f64, anchorB linalg.Vector2f64) *RevoluteJoint2D {
+	return &RevoluteJoint2D{
+		Joint2DBase:    newJoint2DBase(a, b, anchorA, anchorB),
+		ReferenceAngle: bodyAngle(b) - bodyAngle(a),
+	}
+}
+
+// GetJointAngle returns the relative angle of B to A in degrees, minus the reference angle.
+func (j *RevoluteJoint2D) GetJointAngle() float64 {
+	return bodyAngle(j.BodyB) - bodyAngle(j.BodyA) - j.ReferenceAngle
+}
+
+func (j *RevoluteJoint2D) prepare(dt float64) {
+	j.k11, j.k12, j.k22 = j.pointMatrix()
+	j.bias = j.separation().Scale(jointBaumgarte / dt)
+	j.axialMass = j.angularMass()
+	j.angle = j.angleB - j.angleA - linalg.Deg2Rad(j.ReferenceAngle)
+	if !j.EnableLimit || j.axialMass == 0 {
+		j.lowerImpulse, j.upperImpulse = 0, 0
+	}
+	if !j.EnableMotor || j.axialMass == 0 {
+		j.motorImpulse = 0
+	}
+	j.applyLinear(j.impulse)
+	j.applyAngular(j.motorImpulse + j.lowerImpulse - j.upperImpulse)
+}
+
+func (j *RevoluteJoint2D) solve(dt float64) {
+	if j.EnableMotor && j.axialMass != 0 {
+		cdot := j.b.angular - j.a.angular - linalg.Deg2Rad(j.MotorSpeed)
+		old := j.motorImpulse
+		maxImpulse := j.MaxMotorTorque * dt
+		j.motorImpulse = math.Max(-maxImpulse, math.Min(old-j.axialMass*cdot, maxImpulse))
+		j.applyAngular(j.motorImpulse - old)
+	}
+	p := solvePoint(j.k11, j.k12, j.k22, j.relativeVelocity().Add(j.bias).Scale(-1))
+	j.impulse = j.impulse.Add(p)
+	j.applyLinear(p)
+	// limits are solved last, since they are harder to recover than the point constraint.
+	if j.EnableLimit && j.axialMass != 0 {
+		bias := limitBias(j.angle-linalg.Deg2Rad(j.LowerAngle), dt)
+		old := j.lowerImpulse
+		j.lowerImpulse = math.Max(old-j.axialMass*(j.b.angular-j.a.angular+bias), 0)
+		j.applyAngular(j.lowerImpulse - old)
+
+		bias = limitBias(linalg.Deg2Rad(j.UpperAngle)-j.angle, dt)
+		old = j.upperImpulse
+		j.upperImpulse = math.Max(old-j.axialMass*(j.a.angular-j.b.angular+bias), 0)
+		j.applyAngular(old - j.upperImpulse)
+	}
+}
+
+func (j *RevoluteJoint2D) linearImpulse() float64 {
+	return j.impulse.Magnitude()
+}
+
+// ===== prismatic joint =====
+
+// PrismaticJoint2D lets B slide along an axis fixed on A without rotating relative to A, like a slider.
+// The translation could be limited, and a motor could drive the sliding.
+type PrismaticJoint2D struct {
+	Joint2DBase
+	LocalAxisA       linalg.Vector2f64 // unit vector in body space of A.
+	ReferenceAngle   float64           // relative angle of B to A in degrees.
+	EnableLimit      bool
+	LowerTranslation float64
+	UpperTranslation float64
+	EnableMotor      bool
+	MotorSpeed       float64 // pixels per second.
+	MaxMotorForce    float64
+
+	axis, perp     linalg.Vector2f64
+	a1, a2, s1, s2 float64 // lever arms crossed with axis and perp.
+	axialMass      float64
+	perpMass       float64
+	rotMass        float64
+	translation    float64
+	perpBias       float64
+	angleBias      float64
+	perpImpulse    float64
+	angularImpulse float64
+	motorImpulse   float64
+	lowerImpulse   float64
+	upperImpulse   float64
+}
+
+// NewPrismaticJoint2D creates a slider along axis in body space of A, the current relative angle is kept.
+func NewPrismaticJoint2D(a base.IGameObject2D, b base.IGameObject2D, anchorA linalg.Vector2f64, anchorB linalg.Vector2f64, axis linalg.Vector2f64) *PrismaticJoint2D {
+	return &PrismaticJoint2D{
+		Joint2DBase:    newJoint2DBase(a, b, anchorA, anchorB),
+		LocalAxisA:     axis.Normalize(),
+		ReferenceAngle: bodyAngle(b) - bodyAngle(a),
+	}
+}
+
+// GetJointTranslation returns the distance B slid along the axis.
+func (j *PrismaticJoint2D) GetJointTranslation() float64 {
+	anchorA, anchorB := j.GetWorldAnchors()
+	return anchorB.Sub(anchorA).Dot(j.LocalAxisA.Rotate(bodyAngle(j.BodyA)))
+}
+
+func (j *PrismaticJoint2D) prepare(dt float64) {
+	d := j.separation()
+	j.axis = j.LocalAxisA.Rotate(linalg.Rad2Deg(j.angleA))
+	j.perp = j.axis.NormalVec()
+	armA := d.Add(j.ra)
+	j.a1, j.a2 = armA.Mult(j.axis), j.rb.Mult(j.axis)
+	j.s1, j.s2 = armA.Mult(j.perp), j.rb.Mult(j.perp)
+	j.axialMass = j.lineMass(j.a1, j.a2)
+	j.perpMass = j.lineMass(j.s1, j.s2)
+	j.rotMass = j.angularMass()
+	j.translation = j.axis.Dot(d)
+	j.perpBias = jointBaumgarte * j.perp.Dot(d) / dt
+	j.angleBias = jointBaumgarte * (j.angleB - j.angleA - linalg.Deg2Rad(j.ReferenceAngle)) / dt
+	if !j.EnableLimit {
+		j.lowerImpulse, j.upperImpulse = 0, 0
+	}
+	if !j.EnableMotor {
+		j.motorImpulse = 0
+	}
+	axial := j.motorImpulse + j.lowerImpulse - j.upperImpulse
+	j.applyAlong(j.axis, j.a1, j.a2, axial)
+	j.applyAlong(j.perp, j.s1, j.s2, j.perpImpulse)
+	j.applyAngular(j.angularImpulse)
+}
+
+// axialVelocity returns the sliding speed of B relative to A.
+func (j *PrismaticJoint2D) axialVelocity() float64 {
+	return j.axis.Dot(j.b.velocity.Sub(j.a.velocity)) + j.a2*j.b.angular - j.a1*j.a.angular
+}
+
+func (j *PrismaticJoint2D) solve(dt float64) {
+	if j.EnableMotor && j.axialMass != 0 {
+		old := j.motorImpulse
+		maxImpulse := j.MaxMotorForce * dt
+		j.motorImpulse = math.Max(-maxImpulse, math.Min(old+j.axialMass*(j.MotorSpeed-j.axialVelocity()), maxImpulse))
+		j.applyAlong(j.axis, j.a1, j.a2, j.motorImpulse-old)
+	}
+	if j.EnableLimit && j.axialMass != 0 {
+		bias := limitBias(j.translation-j.LowerTranslation, dt)
+		old := j.lowerImpulse
+		j.lowerImpulse = math.Max(old-j.axialMass*(j.axialVelocity()+bias), 0)
+		j.applyAlong(j.axis, j.a1, j.a2, j.lowerImpulse-old)
+
+		bias = limitBias(j.UpperTranslation-j.translation, dt)
+		old = j.upperImpulse
+		j.upperImpulse = math.Max(old-j.axialMass*(-j.axialVelocity()+bias), 0)
+		j.applyAlong(j.axis, j.a1, j.a2, old-j.upperImpulse)
+	}
+	if j.perpMass != 0 {
+		cdot := j.perp.Dot(j.b.velocity.Sub(j.a.velocity)) + j.s2*j.b.angular - j.s1*j.a.angular
+		l := -j.perpMass * (cdot + j.perpBias)
+		j.perpImpulse += l
+		j.applyAlong(j.perp, j.s1, j.s2, l)
+	}
+	if j.rotMass != 0 {
+		l := -j.rotMass * (j.b.angular - j.a.angular + j.angleBias)
+		j.angularImpulse += l
+		j.applyAngular(l)
+	}
+}
+
+func (j *PrismaticJoint2D) linearImpulse() float64 {
+	return math.Hypot(j.perpImpulse, j.lowerImpulse-j.upperImpulse)
+}
+
+// ===== weld joint =====
+
+// WeldJoint2D glues two bodies together, keeping both the anchors and the relative angle.
+type WeldJoint2D struct {
+	Joint2DBase
+	ReferenceAngle float64 // relative angle of B to A in degrees.
+
+	k11, k12, k22  float64
+	bias           linalg.Vector2f64
+	rotMass        float64
+	angleBias      float64
+	impulse        linalg.Vector2f64
+	angularImpulse float64
+}
+
+// NewWeldJoint2D creates a weld joint, the current relative angle of bodies is kept.
+func NewWeldJoint2D(a base.IGameObject2D, b base.IGameObject2D, anchorA linalg.Vector2f64, anchorB linalg.Vector2f64) *WeldJoint2D {
+	return &WeldJoint2D{
+		Joint2DBase:    newJoint2DBase(a, b, anchorA, anchorB),
+		ReferenceAngle: bodyAngle(b) - bodyAngle(a),
+	}
+}
+
+func (j *WeldJoint2D) prepare(dt float64) {
+	j.k11, j.k12, j.k22 = j.pointMatrix()
+	j.bias = j.separation().Scale(jointBaumgarte / dt)
+	j.rotMass = j.angularMass()
+	j.angleBias = jointBaumgarte * (j.angleB - j.angleA - linalg.Deg2Rad(j.ReferenceAngle)) / dt
+	j.applyLinear(j.impulse)
+	j.applyAngular(j.angularImpulse)
+}
+
+func (j *WeldJoint2D) solve(dt float64) {
+	if j.rotMass != 0 {
+		l := -j.rotMass * (j.b.angular - j.a.angular + j.angleBias)
+		j.angularImpulse += l
+		j.applyAngular(l)
+	}
+	p := solvePoint(j.k11, j.k12, j.k22, j.relativeVelocity().Add(j.bias).Scale(-1))
+	j.impulse = j.impulse.Add(p)
+	j.applyLinear(p)
+}
+
+func (j *WeldJoint2D) linearImpulse() float64 {
+	return j.impulse.Magnitude()
+}
+
+// ===== system =====
+
+// AddJoint adds a joint to the simulation, bodies of the joint should subscribe to this system.
+func (s *Physics2DSystem) AddJoint(joint IJoint2D) {
+	s.joints = append(s.joints, joint)
+}
+
+// RemoveJoint removes a joint from the simulation.
+func (s *Physics2DSystem) RemoveJoint(joint IJoint2D) {
+	for i, j := range s.joints {
+		if j == joint {
+			s.joints = append(s.joints[:i], s.joints[i+1:]...)
+			return
+		}
+	}
+}
+
+// GetJoints returns joints in the simulation, broken joints are not included.
+func (s *Physics2DSystem) GetJoints() []IJoint2D {
+	return s.joints
+}
+
+// removeJointsOf removes joints connected to the gameObject.
+func (s *Physics2DSystem) removeJointsOf(iobj base.IGameObject2D) {
+	kept := s.joints[:0]
+	for _, j := range s.joints {
+		if jb := j.GetJointBase(); jb.BodyA != iobj && jb.BodyB != iobj {
+			kept = append(kept, j)
+		}
+	}
+	s.joints = kept
+}
+
+// bindJoints binds joints to solver bodies. A sleeping body is woken up if jointed to an awake one,
+// joints between sleeping or non-dynamic bodies, or connected to inactive bodies are skipped.
+// Joints to be solved and woken bodies are returned.
+func (s *Physics2DSystem) bindJoints(bodies map[*component.Collider]*solverBody, dt float64) ([]IJoint2D, []*solverBody) {
+	s.jointedPairs = make(map[contactKey]struct{})
+	if len(s.joints) == 0 {
+		return nil, nil
+	}
+	active := make([]IJoint2D, 0, len(s.joints))
+	woken := make([]*solverBody, 0)
+	for _, joint := range s.joints {
+		jb := joint.GetJointBase()
+		if s.isInactive(jb.BodyA) || s.isInactive(jb.BodyB) {
+			continue
+		}
+		a, colA := s.jointBody(jb.BodyA, bodies, dt)
+		b, colB := s.jointBody(jb.BodyB, bodies, dt)
+		if !jb.CollideConnected && colA != nil && colB != nil {
+			s.jointedPairs[contactKey{a: colA, b: colB}] = struct{}{}
+		}
+		if !a.awake && !b.awake {
+			continue
+		}
+		for _, body := range []*solverBody{a, b} {
+			if body.item != nil && !body.awake {
+				body.item.RigidBody2D.WakeUp()
+				s.activate(body, dt)
+				woken = append(woken, body)
+			}
+		}
+		jb.bind(a, b)
+		active = append(active, joint)
+	}
+	return active, woken
+}
+
+// jointBody returns the solver body of a gameObject, a non-dynamic one is treated as static.
+func (s *Physics2DSystem) jointBody(iobj base.IGameObject2D, bodies map[*component.Collider]*solverBody, dt float64) (*solverBody, *component.Collider) {
+	if iobj == nil {
+		return &solverBody{}, nil
+	}
+	item, ok := s.obj2data[iobj]
+	if !ok {
+		body := &solverBody{}
+		if tf, ok := iobj.Obj().TryGetComponent(component.NameTransform2D); ok {
+			body.center = tf.(*component.Transform2D).Pos
+		}
+		return body, nil
+	}
+	if body, ok := bodies[item.Collider]; ok {
+		return body, item.Collider
+	}
+	return newStaticSolverBody(item.Collider, dt), item.Collider
+}
+
+// isInactive tells whether the gameObject has been deactivated, nil means the world which is always active.
+func (s *Physics2DSystem) isInactive(iobj base.IGameObject2D) bool {
+	if iobj == nil {
+		return false
+	}
+	_, ok := s.inactive[iobj]
+	return ok
+}
+
+// isJointed tells whether contacts between the pair of colliders are disabled by a joint.
+func (s *Physics2DSystem) isJointed(key contactKey) bool {
+	if _, ok := s.jointedPairs[key]; ok {
+		return true
+	}
+	_, ok := s.jointedPairs[contactKey{a: key.b, b: key.a}]
+	return ok
+}
+
+// breakJoints breaks joints whose reaction force exceeds their limits.
+func (s *Physics2DSystem) breakJoints(joints []IJoint2D, dt float64) {
+	if len(joints) == 0 {
+		return
+	}
+	for _, joint := range joints {
+		jb := joint.GetJointBase()
+		jb.reactionForce = joint.linearImpulse() / dt
+		if jb.BreakForce > 0 && jb.reactionForce > jb.BreakForce {
+			jb.broken = true
+		}
+		jb.a, jb.b = nil, nil
+	}
+	kept := s.joints[:0]
+	for _, j := range s.joints {
+		if !j.GetJointBase().broken {
+			kept = append(kept, j)
+		}
+	}
+	s.joints = kept
+}
+
+// bodyAngle returns the rotation of a gameObject in degrees, zero for the world.
+func bodyAngle(iobj base.IGameObject2D) float64 {
+	if iobj == nil {
+		return 0
+	}
+	return iobj.Obj().GetComponent(component.NameTransform2D).(*component.Transform2D).Rotation
+}
+
+// worldAnchor converts a local anchor to world space, nil gameObject means the anchor is already in world space.
+func worldAnchor(iobj base.IGameObject2D, local linalg.Vector2f64) linalg.Vector2f64 {
+	if iobj == nil {
+		return local
+	}
+	return iobj.Obj().GetComponent(component.NameTransform2D).(*component.Transform2D).TransformPoint(local)
+}
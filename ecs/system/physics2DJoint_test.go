@@ -0,0 +1,131 @@
+package system
+
+import (
+	"testing"
+
+	"galaxyzeta.io/engine/ecs/component"
+	"galaxyzeta.io/engine/infra/require"
+	"galaxyzeta.io/engine/linalg"
+	"galaxyzeta.io/engine/physics"
+)
+
+// addBall creates a dynamic 10*10 box centered at (x, y), its local anchor (5, 5) is the center.
+func (s *testScene) addBall(x float64, y float64) *testObj {
+	return s.addBox(x-5, y-5, 10, 10, component.NewDynamicRigidBody2D(1))
+}
+
+var ballCenter = linalg.NewVector2f64(5, 5)
+
+func TestPendulumKeepsLength(t *testing.T) {
+	scene := newTestScene()
+	ball := scene.addBall(100, 0)
+	joint := NewDistanceJoint2D(ball, nil, ballCenter, linalg.Vector2f64{}, 0)
+	scene.phys.AddJoint(joint)
+	lowest := 0.0
+	for i := 0; i < 120; i++ {
+		scene.step(1)
+		anchorA, anchorB := joint.GetWorldAnchors()
+		length := anchorA.Sub(anchorB).Magnitude()
+		require.EqBool(true, near(length, 100, 2))
+		if anchorA.Y > lowest {
+			lowest = anchorA.Y
+		}
+	}
+	t.Log(lowest)
+	// the ball swings down to the bottom.
+	require.EqBool(true, lowest > 99)
+}
+
+func TestRopeGoesSlack(t *testing.T) {
+	scene := newTestScene()
+	ball := scene.addBall(0, 50)
+	rope := NewDistanceJoint2D(ball, nil, ballCenter, linalg.Vector2f64{}, 100)
+	rope.Rope = true
+	scene.phys.AddJoint(rope)
+	// the ball falls freely while the rope is shorter than its length.
+	scene.step(1)
+	require.EqBool(true, near(ball.rb().Velocity.Y, DefaultGravity.Y/60, 1e-6))
+	require.EqBool(true, rope.GetReactionForce() == 0)
+	scene.step(60)
+	anchorA, _ := rope.GetWorldAnchors()
+	t.Log(anchorA, rope.GetReactionForce())
+	require.EqBool(true, near(anchorA.Y, 100, 1))
+	require.EqBool(true, rope.GetReactionForce() > 0)
+
+	// pushing the ball towards the anchor is not resisted.
+	ball.rb().Velocity = linalg.NewVector2f64(0, -300)
+	scene.step(1)
+	t.Log(ball.rb().Velocity)
+	require.EqBool(true, ball.rb().Velocity.Y < -280)
+}
+
+func TestRevoluteLimitHolds(t *testing.T) {
+	scene := newTestScene()
+	// a 100*10 plank hinged to the world at its left end, gravity turns it clockwise.
+	plank := scene.addBox(0, 0, 100, 10, component.NewDynamicRigidBody2D(1))
+	hinge := NewRevoluteJoint2D(plank, nil, linalg.NewVector2f64(0, 5), linalg.NewVector2f64(0, 5))
+	hinge.EnableLimit = true
+	hinge.LowerAngle, hinge.UpperAngle = -30, 30
+	scene.phys.AddJoint(hinge)
+	for i := 0; i < 120; i++ {
+		scene.step(1)
+		require.EqBool(true, hinge.GetJointAngle() > -32)
+	}
+	anchorA, anchorB := hinge.GetWorldAnchors()
+	t.Log(hinge.GetJointAngle(), anchorA, anchorB)
+	require.EqBool(true, near(hinge.GetJointAngle(), -30, 2))
+	require.EqBool(true, nearVec(anchorA, anchorB, 1))
+}
+
+func TestJointBreaks(t *testing.T) {
+	scene := newTestScene()
+	// holding a ball of mass 1 against gravity takes a force of 980.
+	weak := NewDistanceJoint2D(scene.addBall(0, 50), nil, ballCenter, linalg.Vector2f64{}, 0)
+	weak.BreakForce = 500
+	strong := NewDistanceJoint2D(scene.addBall(100, 50), nil, ballCenter, linalg.NewVector2f64(100, 0), 0)
+	strong.BreakForce = 2000
+	scene.phys.AddJoint(weak)
+	scene.phys.AddJoint(strong)
+	scene.step(10)
+	t.Log(weak.GetReactionForce(), strong.GetReactionForce())
+	require.EqBool(true, weak.IsBroken() && !strong.IsBroken())
+	require.EqInt(1, len(scene.phys.GetJoints()))
+	require.EqBool(true, scene.phys.GetJoints()[0] == IJoint2D(strong))
+}
+
+func TestJointsOfInactiveBodies(t *testing.T) {
+	scene := newTestScene()
+	ball := scene.addBall(0, 100)
+	joint := NewDistanceJoint2D(ball, nil, ballCenter, linalg.Vector2f64{}, 0)
+	scene.phys.AddJoint(joint)
+	scene.step(1)
+	// deactivation keeps the joint, which works again after activation.
+	scene.phys.Deactivate(ball)
+	scene.step(10)
+	require.EqInt(1, len(scene.phys.GetJoints()))
+	scene.phys.Activate(ball)
+	ball.rb().Velocity = linalg.NewVector2f64(300, 0)
+	scene.step(30)
+	anchorA, _ := joint.GetWorldAnchors()
+	t.Log(anchorA)
+	require.EqBool(true, near(anchorA.Magnitude(), 100, 1))
+	// destroying the body removes the joint.
+	scene.phys.Unregister(ball)
+	require.EqInt(0, len(scene.phys.GetJoints()))
+}
+
+func TestJointAnchorsFollowScale(t *testing.T) {
+	scene := newTestScene()
+	// the ball is scaled to 20*20, so its local anchor (5, 5) is at (10, 10) from the position, which is still the center.
+	ball := scene.addBox(90, -10, 10, 10, component.NewDynamicRigidBody2D(1))
+	ball.tf().SetScale(2, 2)
+	scene.step(1)
+	joint := NewDistanceJoint2D(ball, nil, ballCenter, linalg.Vector2f64{}, 100)
+	scene.phys.AddJoint(joint)
+	for i := 0; i < 60; i++ {
+		scene.step(1)
+		anchorA, anchorB := joint.GetWorldAnchors()
+		require.EqBool(true, near(anchorA.Sub(anchorB).Magnitude(), 100, 2))
+		require.EqBool(true, nearVec(anchorA, physics.Centroid(ball.col().Shape), 1e-6))
+	}
+}
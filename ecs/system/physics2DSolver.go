@@ -0,0 +1,483 @@
+package system
+
+import (
+	"math"
+
+	"galaxyzeta.io/engine/collision"
+	"galaxyzeta.io/engine/ecs/component"
+	"galaxyzeta.io/engine/linalg"
+	"galaxyzeta.io/engine/physics"
+)
+
+const (
+	solverIterations     = 8
+	correctionPercent    = 0.4  // how much of the penetration is resolved each step.
+	correctionSlop       = 0.5  // penetration allowed in pixels, prevents jittering.
+	restitutionThreshold = 60.0 // relative speed in pixels per second, below which contacts do not bounce.
+	sleepLinearVelocity  = 5.0  // pixels per second.
+	sleepAngularVelocity = 2.0  // degrees per second.
+	timeToSleep          = 0.5  // seconds a body should stay at rest before sleeping.
+	warmStartDistance    = 2.0  // contact points closer than this in pixels are considered the same between steps.
+	sleepMargin          = 1.0  // pixels around a sleeping body, in which changes of neighbours wake it up.
+)
+
+// DefaultGravity is the gravity acceleration of dynamic bodies in pixels per second squared.
+var DefaultGravity = linalg.NewVector2f64(0, 980)
+
+// solverBody caches the state of a body while solving contacts.
+// Static or kinematic colliders have zero inverse mass.
+type solverBody struct {
+	awake      bool
+	moving     bool                      // whether the body was moving before forces applied in this step, moving bodies wake others.
+	queried    bool                      // contacts against this body have been collected.
+	item       *PhysicalComponentWrapper // nil if not a dynamic body.
+	center     linalg.Vector2f64         // centroid of the collider, around which the body rotates.
+	velocity   linalg.Vector2f64
+	angular    float64 // radians per second.
+	invMass    float64
+	invInertia float64
+}
+
+// sleepSnapshot records the surroundings of a sleeping body, which is woken up once they change.
+type sleepSnapshot struct {
+	box       physics.BoundingBox
+	neighbors map[*component.Collider]neighborState
+}
+
+type neighborState struct {
+	box     physics.BoundingBox
+	dynamic bool // dynamic neighbours wake sleeping bodies by contacts, so only their removal is watched.
+}
+
+// contactKey identifies a pair of colliders in contact.
+type contactKey struct {
+	a *component.Collider
+	b *component.Collider
+}
+
+type solverContact struct {
+	key         contactKey
+	a           *solverBody
+	b           *solverBody
+	manifold    physics.Manifold
+	restitution float64
+	friction    float64
+	// tangentSpeed is the relative tangent velocity friction drives to, non-zero on conveyor belts.
+	tangentSpeed float64
+	// impulses accumulated on each contact point through iterations, they are clamped as a whole.
+	normalImpulse  []float64
+	tangentImpulse []float64
+	bounce         []float64 // target normal velocity after the collision.
+}
+
+// stepDynamics simulates all awake dynamic bodies sequentially with an iterative impulse solver.
+func (s *Physics2DSystem) stepDynamics() {
+	dt := s.timeStep
+	bodies := make(map[*component.Collider]*solverBody)
+	for _, item := range s.obj2data {
+		if item.RigidBody2D.BodyType != component.BodyType_Dynamic || item.Collider == nil {
+			continue
+		}
+		item := item
+		bodies[item.Collider] = &solverBody{item: &item, center: physics.Centroid(item.Shape)}
+	}
+	if len(bodies) == 0 {
+		return
+	}
+	s.wakeByChangedNeighbors(bodies)
+
+	awake := make([]*solverBody, 0)
+	for _, body := range bodies {
+		if !body.item.RigidBody2D.IsSleeping() {
+			s.activate(body, dt)
+			awake = append(awake, body)
+		}
+	}
+	if len(awake) == 0 {
+		return
+	}
+
+	joints, woken := s.bindJoints(bodies, dt)
+	awake = append(awake, woken...)
+	contacts, woken := s.collectContacts(awake, bodies, dt)
+	awake = append(awake, woken...)
+	s.warmStart(contacts)
+	for _, j := range joints {
+		j.prepare(dt)
+	}
+	for i := 0; i < solverIterations; i++ {
+		for _, j := range joints {
+			j.solve(dt)
+		}
+		for _, c := range contacts {
+			c.solve()
+		}
+	}
+	s.breakJoints(joints, dt)
+
+	for _, body := range awake {
+		rb := body.item.RigidBody2D
+		rb.Velocity = body.velocity
+		rb.AngularVelocity = linalg.Rad2Deg(body.angular)
+		tf := body.item.Transform2D
+		delta := body.velocity.Scale(dt)
+		rotating := body.invInertia != 0 && rb.AngularVelocity != 0
+		if rotating {
+			// the body rotates around its centroid, which moves the transform if it is anchored elsewhere.
+			offset := tf.Pos.Sub(body.center)
+			delta = delta.Add(offset.Rotate(rb.AngularVelocity * dt).Sub(offset))
+		}
+		tf.Translate(delta.X, delta.Y)
+		if rotating {
+			// the collider follows the rotation of the transform.
+			tf.SetRotation(math.Mod(tf.Rotation+rb.AngularVelocity*dt, 360))
+			body.item.Collider.SyncTransform()
+		}
+		rb.SetHspeed(body.velocity.X * dt)
+		rb.SetVspeed(body.velocity.Y * dt)
+		rb.AccumulateSleep(dt, !body.restless(), timeToSleep)
+	}
+	for _, c := range contacts {
+		c.correctPosition()
+	}
+}
+
+// warmStart applies impulses solved in last step to contacts that persist, which makes stacks stable.
+// Contacts are then cached for the next step.
+func (s *Physics2DSystem) warmStart(contacts []*solverContact) {
+	cache := make(map[contactKey]*solverContact, len(contacts))
+	for _, c := range contacts {
+		prev, ok := s.contactCache[c.key]
+		if !ok {
+			prev, ok = s.contactCache[contactKey{a: c.key.b, b: c.key.a}]
+		}
+		if ok {
+			for i, p := range c.manifold.Contacts {
+				for j, q := range prev.manifold.Contacts {
+					if p.Sub(q).Magnitude() < warmStartDistance {
+						c.normalImpulse[i] = prev.normalImpulse[j]
+						c.tangentImpulse[i] = prev.tangentImpulse[j]
+						break
+					}
+				}
+				ra := p.Sub(c.a.center)
+				rb := p.Sub(c.b.center)
+				impulse := c.manifold.Normal.Scale(c.normalImpulse[i]).Add(c.manifold.Normal.NormalVec().Scale(c.tangentImpulse[i]))
+				c.applyImpulse(impulse, ra, rb)
+			}
+		}
+		cache[c.key] = c
+	}
+	s.contactCache = cache
+}
+
+// activate prepares an awake body for solving.
+func (s *Physics2DSystem) activate(body *solverBody, dt float64) {
+	item := body.item
+	// a collider following the hitbox of a sprite renderer may have changed its frame.
+	item.Collider.SyncTransform()
+	body.center = physics.Centroid(item.Shape)
+	// forces are not counted, otherwise gravity keeps every body restless.
+	body.moving = isRestless(item.RigidBody2D.Velocity, item.RigidBody2D.AngularVelocity)
+	s.integrateForces(*item, body.center, dt)
+	body.awake = true
+	body.velocity = item.RigidBody2D.Velocity
+	body.angular = linalg.Deg2Rad(item.RigidBody2D.AngularVelocity)
+	body.invMass = item.RigidBody2D.GetInvMass()
+	body.invInertia = item.RigidBody2D.GetInvInertia()
+}
+
+// integrateForces applies forces to velocities, torques are taken around center.
+func (s *Physics2DSystem) integrateForces(item PhysicalComponentWrapper, center linalg.Vector2f64, dt float64) {
+	rb := item.RigidBody2D
+	force, torque := rb.ConsumeForces(center)
+	acc := force.Scale(rb.GetInvMass())
+	if rb.UseGravity && rb.GetInvMass() != 0 {
+		acc = acc.Add(s.gravity.Scale(rb.GravityScale))
+	}
+	rb.Velocity = rb.Velocity.Add(acc.Scale(dt))
+	rb.AngularVelocity += linalg.Rad2Deg(torque * rb.GetInvInertia() * dt)
+	rb.Velocity = rb.Velocity.Scale(1 / (1 + dt*rb.LinearDamping))
+	rb.AngularVelocity /= 1 + dt*rb.AngularDamping
+}
+
+// wakeByChangedNeighbors wakes sleeping bodies whose surroundings changed since last step.
+// That is, the body itself was moved, a non-dynamic neighbour was moved, added, deactivated or destroyed,
+// or a dynamic neighbour was gone. So bodies never float after their supports are removed,
+// and platforms moved either by speed vectors or by editing transforms carry bodies on them.
+func (s *Physics2DSystem) wakeByChangedNeighbors(bodies map[*component.Collider]*solverBody) {
+	snapshots := make(map[*component.Collider]sleepSnapshot, len(s.sleepSnapshots))
+	for col, body := range bodies {
+		if !body.item.RigidBody2D.IsSleeping() {
+			continue
+		}
+		current := s.takeSleepSnapshot(col, bodies)
+		if prev, ok := s.sleepSnapshots[col]; ok && !prev.matches(current) {
+			body.item.RigidBody2D.WakeUp()
+			continue
+		}
+		snapshots[col] = current
+	}
+	s.sleepSnapshots = snapshots
+}
+
+// takeSleepSnapshot records active colliders whose bounding boxes are within sleepMargin of the body.
+func (s *Physics2DSystem) takeSleepSnapshot(col *component.Collider, bodies map[*component.Collider]*solverBody) sleepSnapshot {
+	box := col.Shape.GetBoundingBox()
+	rect := box.ToRectangle()
+	area := physics.NewRectangle(rect.Left-sleepMargin, rect.Top-sleepMargin, rect.Width+2*sleepMargin, rect.Height+2*sleepMargin)
+	snapshot := sleepSnapshot{box: box, neighbors: make(map[*component.Collider]neighborState)}
+	for _, other := range s.csys.QueryNeighborCollidersWithCollider(component.Collider{Shape: area}, collision.ActiveOnly) {
+		if other == col {
+			continue
+		}
+		otherBox := other.Shape.GetBoundingBox()
+		if !area.IntersectWithRectangle(otherBox.ToRectangle()) {
+			continue
+		}
+		_, dynamic := bodies[other]
+		snapshot.neighbors[other] = neighborState{box: otherBox, dynamic: dynamic}
+	}
+	return snapshot
+}
+
+// matches tells whether the surroundings are unchanged, dynamic neighbours could come and move freely.
+func (prev sleepSnapshot) matches(current sleepSnapshot) bool {
+	if prev.box != current.box {
+		return false
+	}
+	for col, state := range prev.neighbors {
+		now, ok := current.neighbors[col]
+		if !ok || !state.dynamic && now.box != state.box {
+			return false
+		}
+	}
+	for col, state := range current.neighbors {
+		if _, ok := prev.neighbors[col]; !ok && !state.dynamic {
+			return false
+		}
+	}
+	return true
+}
+
+// collectContacts finds contacts of awake bodies against dynamic bodies, static bodies and colliders tagged solid.
+// A sleeping body is woken up if hit by a moving body, otherwise it is treated as static.
+// Woken bodies are queried in the same step so that they are not pushed through their supports, and they are returned.
+func (s *Physics2DSystem) collectContacts(awake []*solverBody, bodies map[*component.Collider]*solverBody, dt float64) ([]*solverContact, []*solverBody) {
+	contacts := make([]*solverContact, 0)
+	woken := make([]*solverBody, 0)
+	queue := append([]*solverBody(nil), awake...)
+	for idx := 0; idx < len(queue); idx++ {
+		a := queue[idx]
+		col := a.item.Collider
+		for _, other := range s.csys.QueryNeighborCollidersWithCollider(*col, collision.ActiveOnly) {
+			if other == col {
+				continue
+			}
+			b, ok := bodies[other]
+			if ok && b.queried {
+				// the pair has been handled.
+				continue
+			}
+			if s.isJointed(contactKey{a: col, b: other}) {
+				continue
+			}
+			if ok && !b.awake {
+				if a.moving {
+					b.item.RigidBody2D.WakeUp()
+					s.activate(b, dt)
+					woken = append(woken, b)
+					queue = append(queue, b)
+				} else {
+					b = &solverBody{item: b.item, center: b.center}
+				}
+			}
+			if !ok {
+				if !isSolid(other) {
+					continue
+				}
+				b = newStaticSolverBody(other, dt)
+			}
+			m, hit := physics.Collide(col.Shape, other.Shape)
+			if !hit {
+				continue
+			}
+			key := contactKey{a: col, b: other}
+			touching := s.wasTouching(key)
+			if passesOneWay(other, m.Normal.Scale(-1), a.velocity.Sub(b.velocity), m.Depth, touching, dt) ||
+				passesOneWay(col, m.Normal, b.velocity.Sub(a.velocity), m.Depth, touching, dt) {
+				continue
+			}
+			c := newSolverContact(a, b, m)
+			c.key = key
+			c.tangentSpeed = s.conveyorSpeed(col) + s.conveyorSpeed(other)
+			contacts = append(contacts, c)
+		}
+		a.queried = true
+	}
+	return contacts, woken
+}
+
+// wasTouching tells whether the pair of colliders were in contact in last step.
+func (s *Physics2DSystem) wasTouching(key contactKey) bool {
+	if _, ok := s.contactCache[key]; ok {
+		return true
+	}
+	_, ok := s.contactCache[contactKey{a: key.b, b: key.a}]
+	return ok
+}
+
+// passesOneWay tells whether a contact with a one-way collider should be ignored.
+// The normal points from the one-way collider to the other body, and velocity is that of the other body relative to it.
+// Bodies are blocked only if they come from the facing side, and have not sunk deeper than they could in one step.
+// Contacts persisting from last step stay blocked, because penetration is resolved gradually.
+func passesOneWay(col *component.Collider, normal linalg.Vector2f64, velocity linalg.Vector2f64, depth float64, touching bool, dt float64) bool {
+	if !col.OneWay {
+		return false
+	}
+	if !col.BlocksFrom(normal) {
+		return true
+	}
+	vn := velocity.Dot(col.GetOneWayNormal())
+	if touching {
+		return false
+	}
+	return vn > 0 || depth > 2*correctionSlop-vn*dt
+}
+
+// isSolid tells whether a non-dynamic collider blocks dynamic bodies.
+// One-way colliders are always solid from the facing side.
+func isSolid(col *component.Collider) bool {
+	if col.I() == nil {
+		return false
+	}
+	if col.OneWay || col.I().Obj().HasTag("solid") {
+		return true
+	}
+	rb, ok := col.I().Obj().GetAllComponents()[component.NameRigidBody2D]
+	return ok && rb.(*component.RigidBody2D).BodyType == component.BodyType_Static
+}
+
+// newStaticSolverBody wraps a solid collider, a kinematic rigidbody on it makes the collider a moving platform.
+func newStaticSolverBody(col *component.Collider, dt float64) *solverBody {
+	body := &solverBody{}
+	body.center = physics.Centroid(col.Shape)
+	comps := col.I().Obj().GetAllComponents()
+	if rb, ok := comps[component.NameRigidBody2D]; ok && dt > 0 {
+		rb := rb.(*component.RigidBody2D)
+		body.velocity = linalg.NewVector2f64(rb.GetHspeed()/dt, rb.GetVspeed()/dt)
+	}
+	return body
+}
+
+func newSolverContact(a *solverBody, b *solverBody, m physics.Manifold) *solverContact {
+	c := &solverContact{a: a, b: b, manifold: m}
+	var ra, rb *component.RigidBody2D
+	if a.item != nil {
+		ra = a.item.RigidBody2D
+	}
+	if b.item != nil {
+		rb = b.item.RigidBody2D
+	}
+	// a solid collider without rigidbody uses the other's material.
+	switch {
+	case ra != nil && rb != nil:
+		c.restitution = math.Max(ra.Restitution, rb.Restitution)
+		c.friction = math.Sqrt(ra.Friction * rb.Friction)
+	case ra != nil:
+		c.restitution, c.friction = ra.Restitution, ra.Friction
+	default:
+		c.restitution, c.friction = rb.Restitution, rb.Friction
+	}
+	count := len(m.Contacts)
+	c.normalImpulse = make([]float64, count)
+	c.tangentImpulse = make([]float64, count)
+	c.bounce = make([]float64, count)
+	for i, p := range m.Contacts {
+		// resting contacts should not bounce.
+		if vn := c.relativeVelocity(p).Dot(m.Normal); -vn > restitutionThreshold {
+			c.bounce[i] = -c.restitution * vn
+		}
+	}
+	return c
+}
+
+// relativeVelocity returns velocity of b relative to a at a world point.
+func (c *solverContact) relativeVelocity(p linalg.Vector2f64) linalg.Vector2f64 {
+	return c.b.pointVelocity(p).Sub(c.a.pointVelocity(p))
+}
+
+func (c *solverContact) solve() {
+	n := c.manifold.Normal
+	t := n.NormalVec()
+	for i, p := range c.manifold.Contacts {
+		ra := p.Sub(c.a.center)
+		rb := p.Sub(c.b.center)
+
+		// coulomb friction along the tangent, bounded by the normal impulse of last iteration.
+		if k := c.effectiveMass(ra, rb, t); k != 0 {
+			jt := (c.tangentSpeed - c.relativeVelocity(p).Dot(t)) / k
+			maxFriction := c.normalImpulse[i] * c.friction
+			old := c.tangentImpulse[i]
+			c.tangentImpulse[i] = math.Max(-maxFriction, math.Min(old+jt, maxFriction))
+			c.applyImpulse(t.Scale(c.tangentImpulse[i]-old), ra, rb)
+		}
+
+		k := c.effectiveMass(ra, rb, n)
+		if k == 0 {
+			continue
+		}
+		j := (c.bounce[i] - c.relativeVelocity(p).Dot(n)) / k
+		old := c.normalImpulse[i]
+		// the accumulated impulse could only push bodies apart.
+		c.normalImpulse[i] = math.Max(old+j, 0)
+		c.applyImpulse(n.Scale(c.normalImpulse[i]-old), ra, rb)
+	}
+}
+
+// effectiveMass returns the inverse of mass felt by an impulse along dir at a contact point.
+func (c *solverContact) effectiveMass(ra linalg.Vector2f64, rb linalg.Vector2f64, dir linalg.Vector2f64) float64 {
+	raCd := ra.Mult(dir)
+	rbCd := rb.Mult(dir)
+	return c.a.invMass + c.b.invMass + raCd*raCd*c.a.invInertia + rbCd*rbCd*c.b.invInertia
+}
+
+func (c *solverContact) applyImpulse(impulse linalg.Vector2f64, ra linalg.Vector2f64, rb linalg.Vector2f64) {
+	c.a.velocity = c.a.velocity.Sub(impulse.Scale(c.a.invMass))
+	c.a.angular -= ra.Mult(impulse) * c.a.invInertia
+	c.b.velocity = c.b.velocity.Add(impulse.Scale(c.b.invMass))
+	c.b.angular += rb.Mult(impulse) * c.b.invInertia
+}
+
+// correctPosition pushes bodies apart linearly to fix the penetration left by the impulse solver.
+func (c *solverContact) correctPosition() {
+	invMassSum := c.a.invMass + c.b.invMass
+	if invMassSum == 0 {
+		return
+	}
+	depth := math.Max(c.manifold.Depth-correctionSlop, 0)
+	correction := c.manifold.Normal.Scale(depth / invMassSum * correctionPercent)
+	if c.a.item != nil && c.a.invMass != 0 {
+		d := correction.Scale(-c.a.invMass)
+		c.a.item.Transform2D.Translate(d.X, d.Y)
+	}
+	if c.b.item != nil && c.b.invMass != 0 {
+		d := correction.Scale(c.b.invMass)
+		c.b.item.Transform2D.Translate(d.X, d.Y)
+	}
+}
+
+// pointVelocity returns the velocity of a world point on the body.
+func (b *solverBody) pointVelocity(p linalg.Vector2f64) linalg.Vector2f64 {
+	r := p.Sub(b.center)
+	return b.velocity.Add(linalg.NewVector2f64(-b.angular*r.Y, b.angular*r.X))
+}
+
+// restless tells whether the body moves fast enough to stay awake.
+func (b *solverBody) restless() bool {
+	return isRestless(b.velocity, linalg.Rad2Deg(b.angular))
+}
+
+func isRestless(velocity linalg.Vector2f64, angularDeg float64) bool {
+	return velocity.Magnitude() > sleepLinearVelocity || math.Abs(angularDeg) > sleepAngularVelocity
+}
@@ -0,0 +1,248 @@
+package system
+
+import (
+	"math"
+	"testing"
+
+	"galaxyzeta.io/engine/base"
+	"galaxyzeta.io/engine/ecs/component"
+	cc "galaxyzeta.io/engine/infra/concurrency"
+	"galaxyzeta.io/engine/infra/require"
+	"galaxyzeta.io/engine/linalg"
+	"galaxyzeta.io/engine/physics"
+)
+
+type testObj struct {
+	*base.GameObject2D
+}
+
+func (o *testObj) Obj() *base.GameObject2D {
+	return o.GameObject2D
+}
+
+func (o *testObj) tf() *component.Transform2D {
+	return o.GetComponent(component.NameTransform2D).(*component.Transform2D)
+}
+
+func (o *testObj) col() *component.Collider {
+	return o.GetComponent(component.NameCollider).(*component.Collider)
+}
+
+func (o *testObj) rb() *component.RigidBody2D {
+	return o.GetComponent(component.NameRigidBody2D).(*component.RigidBody2D)
+}
+
+// testScene steps physics systems over a quad tree the way the game loop does.
+type testScene struct {
+	csys     *QuadTreeCollision2DSystem
+	phys     *Physics2DSystem
+	chars    *CharacterController2DSystem
+	executor *cc.Executor
+}
+
+func newTestScene() *testScene {
+	csys := NewQuadTreeCollision2DSystem(0, physics.NewRectangle(-1024, -1024, 2048, 2048), 4, 64)
+	executor := cc.NewExecutor(1)
+	executor.Run()
+	return &testScene{
+		csys:     csys,
+		phys:     NewPhysics2DSystem(1, csys),
+		chars:    NewCharacterController2DSystem(1, csys),
+		executor: executor,
+	}
+}
+
+// addBox creates a w*h box whose top-left corner is placed at (x, y), it is a solid without rigidbody if rb is nil.
+func (s *testScene) addBox(x float64, y float64, w float64, h float64, rb *component.RigidBody2D) *testObj {
+	obj := &testObj{GameObject2D: base.NewGameObject2D("box")}
+	tf := component.NewTransform2D()
+	tf.Teleport(x, y)
+	topLeft := physics.Pivot{Option: physics.PivotOption_TopLeft}
+	obj.RegisterComponent(tf).RegisterComponent(component.NewBoxCollider(&tf.Pos, w, h, topLeft, obj))
+	s.csys.Register(obj)
+	if rb == nil {
+		obj.AppendTags("solid")
+		return obj
+	}
+	obj.RegisterComponent(rb)
+	s.phys.Register(obj)
+	return obj
+}
+
+func (s *testScene) step(n int) {
+	for i := 0; i < n; i++ {
+		s.chars.Execute(s.executor)
+		s.phys.Execute(s.executor)
+		s.csys.Execute(s.executor)
+	}
+}
+
+func near(a float64, b float64, eps float64) bool {
+	return math.Abs(a-b) < eps
+}
+
+func nearVec(a linalg.Vector2f64, b linalg.Vector2f64, eps float64) bool {
+	return a.Sub(b).Magnitude() < eps
+}
+
+func TestSolverRotatesAroundCentroid(t *testing.T) {
+	scene := newTestScene()
+	rb := component.NewDynamicRigidBody2D(1)
+	rb.UseGravity = false
+	rb.AngularVelocity = 90
+	box := scene.addBox(0, 0, 20, 20, rb)
+	scene.step(60)
+	t.Log(box.tf().Pos, box.tf().Rotation, physics.Centroid(box.col().Shape))
+	// a quarter turn around the center moves the top-left anchor to the top-right.
+	require.EqBool(true, near(box.tf().Rotation, 90, 1e-6))
+	require.EqBool(true, nearVec(physics.Centroid(box.col().Shape), linalg.NewVector2f64(10, 10), 1e-6))
+	require.EqBool(true, nearVec(box.tf().Pos, linalg.NewVector2f64(20, 0), 1e-6))
+
+	// a force through the centroid does not spin the body.
+	rb.AngularVelocity = 0
+	rb.ApplyForceAtPoint(linalg.NewVector2f64(600, 0), physics.Centroid(box.col().Shape))
+	scene.step(1)
+	require.EqBool(true, rb.AngularVelocity == 0 && rb.Velocity.X > 0)
+}
+
+func TestSolverLandsFlat(t *testing.T) {
+	scene := newTestScene()
+	scene.addBox(-100, 40, 200, 20, nil)
+	rb := component.NewDynamicRigidBody2D(1)
+	box := scene.addBox(0, 0, 20, 20, rb)
+	scene.step(120)
+	t.Log(box.tf().Pos, box.tf().Rotation)
+	// contacts are symmetric about the centroid, even though the box is anchored at its corner.
+	require.EqBool(true, near(box.tf().Rotation, 0, 0.1))
+	require.EqBool(true, near(box.tf().Pos.X, 0, 0.1))
+	require.EqBool(true, near(box.tf().Pos.Y, 20, 1))
+}
+
+// newRestingScene drops a box on the ground and waits until it sleeps.
+func newRestingScene() (*testScene, *testObj, *testObj) {
+	scene := newTestScene()
+	ground := scene.addBox(-100, 40, 200, 20, nil)
+	box := scene.addBox(0, 0, 20, 20, component.NewDynamicRigidBody2D(1))
+	scene.step(120)
+	require.EqBool(true, box.rb().IsSleeping())
+	return scene, ground, box
+}
+
+func TestSleepingBodyStaysAsleep(t *testing.T) {
+	scene, _, box := newRestingScene()
+	far := scene.addBox(500, 500, 20, 20, nil)
+	far.tf().Translate(10, 0)
+	scene.step(30)
+	require.EqBool(true, box.rb().IsSleeping())
+}
+
+func TestSleepingBodyWakesWhenSupportChanges(t *testing.T) {
+	changes := map[string]func(scene *testScene, ground *testObj){
+		"translated": func(scene *testScene, ground *testObj) {
+			ground.tf().Translate(0, 10)
+		},
+		"teleported": func(scene *testScene, ground *testObj) {
+			ground.tf().Teleport(-100, 100)
+		},
+		"deactivated": func(scene *testScene, ground *testObj) {
+			scene.csys.Deactivate(ground)
+		},
+		"destroyed": func(scene *testScene, ground *testObj) {
+			scene.csys.Unregister(ground)
+		},
+	}
+	for name, change := range changes {
+		scene, ground, box := newRestingScene()
+		y := box.tf().Pos.Y
+		change(scene, ground)
+		scene.step(10)
+		t.Log(name, box.tf().Pos)
+		require.EqBool(false, box.rb().IsSleeping())
+		require.EqBool(true, box.tf().Pos.Y > y)
+	}
+}
+
+func TestSleepingBodyWakesOnMovingPlatform(t *testing.T) {
+	scene, ground, box := newRestingScene()
+	y := box.tf().Pos.Y
+	// the platform is moved by editing its transform such as in OnStep, until it slides away from below the box.
+	for i := 0; i < 30; i++ {
+		ground.tf().Translate(5, 0)
+		scene.step(1)
+	}
+	t.Log(box.tf().Pos)
+	require.EqBool(false, box.rb().IsSleeping())
+	require.EqBool(true, box.tf().Pos.Y > y)
+}
+
+func TestWokenBodyRestsOnFloor(t *testing.T) {
+	scene, _, box := newRestingScene()
+	y := box.tf().Pos.Y
+	// a falling box wakes the sleeping one, whose contact with the floor is solved in the same step.
+	falling := component.NewDynamicRigidBody2D(1)
+	falling.Velocity = linalg.NewVector2f64(0, 600)
+	scene.addBox(0, -15, 20, 20, falling)
+	scene.step(1)
+	t.Log(y, box.tf().Pos)
+	require.EqBool(false, box.rb().IsSleeping())
+	require.EqBool(true, near(box.tf().Pos.Y, y, 0.01))
+	// it is pressed into the floor no deeper than the solver resolves gradually.
+	for i := 0; i < 30; i++ {
+		scene.step(1)
+		require.EqBool(true, box.tf().Pos.Y < y+2)
+	}
+}
+
+func TestBodyTypes(t *testing.T) {
+	scene := newTestScene()
+	static := component.NewRigidBody2D()
+	static.BodyType = component.BodyType_Static
+	static.Velocity = linalg.NewVector2f64(100, 0)
+	ground := scene.addBox(-100, 40, 200, 20, static)
+	kinematic := component.NewRigidBody2D()
+	kinematic.AddForce(component.SpeedVector{Speed: 2})
+	mover := scene.addBox(300, 0, 20, 20, kinematic)
+	box := scene.addBox(0, 0, 20, 20, component.NewDynamicRigidBody2D(1))
+
+	scene.step(1)
+	// dynamic bodies are accelerated by gravity.
+	require.EqBool(true, near(box.rb().Velocity.Y, DefaultGravity.Y/60, 1e-6))
+	scene.step(59)
+	t.Log(ground.tf().Pos, mover.tf().Pos, box.tf().Pos)
+	// static bodies never move, but block dynamic bodies without being tagged solid.
+	require.EqBool(true, ground.tf().Pos == linalg.NewVector2f64(-100, 40))
+	require.EqBool(true, near(box.tf().Pos.Y, 20, 1))
+	// kinematic bodies move by speed vectors per step, and ignore gravity unless told.
+	require.EqBool(true, nearVec(mover.tf().Pos, linalg.NewVector2f64(420, 0), 1e-6))
+}
+
+func TestColliderFollowsTransform(t *testing.T) {
+	scene := newTestScene()
+	bar := scene.addBox(0, 0, 40, 10, component.NewDynamicRigidBody2D(1))
+	// moving does not rebuild the shape, which is anchored on the position.
+	shape := bar.col().Shape
+	bar.tf().Translate(10, 0)
+	require.EqBool(true, bar.col().Shape == shape)
+	require.EqBool(true, bar.col().Shape.GetBoundingBox().GetTopLeftPoint() == linalg.NewVector2f64(10, 0))
+	// rotating rebuilds the shape in the update of the collision system.
+	bar.tf().SetRotation(90)
+	require.EqBool(true, bar.col().Shape == shape)
+	scene.csys.Execute(scene.executor)
+	rect := bar.col().Shape.GetBoundingBox().ToRectangle()
+	t.Log(rect)
+	require.EqBool(true, near(rect.Width, 10, 1e-9) && near(rect.Height, 40, 1e-9))
+}
+
+func TestKinematicBodiesMoveInBatches(t *testing.T) {
+	scene := newTestScene()
+	movers := make([]*testObj, 0)
+	for i := 0; i < 4*kinematicBatchSize; i++ {
+		rb := component.NewRigidBody2D()
+		rb.AddForce(component.SpeedVector{Speed: 1, Direction: 270})
+		movers = append(movers, scene.addBox(float64(i%16)*30, float64(i/16)*30, 20, 20, rb))
+	}
+	scene.step(10)
+	for i, mover := range movers {
+		require.EqBool(true, nearVec(mover.tf().Pos, linalg.NewVector2f64(float64(i%16)*30, float64(i/16)*30+10), 1e-6))
+	}
+}
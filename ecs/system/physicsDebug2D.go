@@ -0,0 +1,114 @@
+package system
+
+import (
+	"galaxyzeta.io/engine/base"
+	"galaxyzeta.io/engine/ecs/component"
+	"galaxyzeta.io/engine/graphics"
+	cc "galaxyzeta.io/engine/infra/concurrency"
+	"galaxyzeta.io/engine/linalg"
+	"galaxyzeta.io/engine/physics"
+)
+
+var NamePhysicsDebug2DSystem = "sys_PhysicsDebug2D"
+
+var (
+	debugColorDynamic   = linalg.NewRgbaF64(0, 1, 0, 1)
+	debugColorSleeping  = linalg.NewRgbaF64(0.5, 0.5, 0.5, 1)
+	debugColorKinematic = linalg.NewRgbaF64(1, 1, 0, 1)
+	debugColorStatic    = linalg.NewRgbaF64(0, 0.5, 1, 1)
+	debugColorJoint     = linalg.NewRgbaF64(1, 0.5, 0, 1)
+)
+
+// PhysicsDebug2DSystem is a graphics system drawing outlines of all physical bodies.
+// Awake dynamic bodies are green, sleeping ones are gray, kinematic ones are yellow and static ones are blue.
+// Joints are orange lines connecting body positions through the anchors.
+// It draws nothing until enabled.
+type PhysicsDebug2DSystem struct {
+	*base.SystemBase
+	physics *Physics2DSystem
+	enabled bool
+}
+
+func NewPhysicsDebug2DSystem(priority int, physicsSystem *Physics2DSystem) *PhysicsDebug2DSystem {
+	return &PhysicsDebug2DSystem{
+		SystemBase: base.NewSystemBase(priority),
+		physics:    physicsSystem,
+	}
+}
+
+// SetEnabled turns debug drawing on or off.
+func (s *PhysicsDebug2DSystem) SetEnabled(enabled bool) {
+	s.enabled = enabled
+	s.physics.SetDebugEnabled(enabled)
+}
+
+func (s *PhysicsDebug2DSystem) IsEnabled() bool {
+	return s.enabled
+}
+
+func (s *PhysicsDebug2DSystem) execute(_ *cc.Executor) {
+	if !s.enabled {
+		return
+	}
+	for _, body := range s.physics.DebugBodies() {
+		color := debugColorKinematic
+		switch {
+		case body.BodyType == component.BodyType_Static:
+			color = debugColorStatic
+		case body.BodyType == component.BodyType_Dynamic && body.Sleeping:
+			color = debugColorSleeping
+		case body.BodyType == component.BodyType_Dynamic:
+			color = debugColorDynamic
+		}
+		drawShape(body.Shape, color)
+	}
+	for _, joint := range s.physics.DebugJoints() {
+		graphics.DrawSegment(linalg.Segmentf64{Point1: joint.CenterA, Point2: joint.AnchorA}, debugColorJoint)
+		graphics.DrawSegment(linalg.Segmentf64{Point1: joint.AnchorA, Point2: joint.AnchorB}, debugColorJoint)
+		graphics.DrawSegment(linalg.Segmentf64{Point1: joint.AnchorB, Point2: joint.CenterB}, debugColorJoint)
+	}
+}
+
+func drawShape(shape physics.IShape, color linalg.RgbaF64) {
+	switch shape := shape.(type) {
+	case physics.Circle:
+		graphics.DrawCircle(shape.GetWorldCenter(), shape.Radius, color)
+	case *physics.Circle:
+		drawShape(*shape, color)
+	case physics.Capsule:
+		graphics.DrawPolygon(shape.GetWorldOutline(8), color)
+	case *physics.Capsule:
+		drawShape(*shape, color)
+	case physics.Rectangle:
+		graphics.DrawRectangle(shape.GetWorldRect(), color)
+	case *physics.Rectangle:
+		drawShape(*shape, color)
+	case physics.Polygon:
+		graphics.DrawPolygon(shape.GetWorldVertices(), color)
+	case *physics.Polygon:
+		drawShape(*shape, color)
+	}
+}
+
+// ===== IMPLEMENTATION =====
+
+func (s *PhysicsDebug2DSystem) Execute(executor *cc.Executor) {
+	s.execute(executor)
+}
+
+func (s *PhysicsDebug2DSystem) GetSystemBase() *base.SystemBase {
+	return s.SystemBase
+}
+
+func (s *PhysicsDebug2DSystem) GetName() string {
+	return NamePhysicsDebug2DSystem
+}
+
+// Register does nothing, bodies are collected from Physics2DSystem.
+func (s *PhysicsDebug2DSystem) Register(iobj base.IGameObject2D) {}
+
+func (s *PhysicsDebug2DSystem) Unregister(iobj base.IGameObject2D) {}
+
+func (s *PhysicsDebug2DSystem) Activate(iobj base.IGameObject2D) {}
+
+func (s *PhysicsDebug2DSystem) Deactivate(iobj base.IGameObject2D) {}
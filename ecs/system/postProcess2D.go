@@ -0,0 +1,55 @@
+package system
+
+import (
+	"galaxyzeta.io/engine/base"
+	"galaxyzeta.io/engine/graphics"
+	cc "galaxyzeta.io/engine/infra/concurrency"
+)
+
+var NamePostProcess2DSystem = "sys_PostProcess2D"
+
+// PostProcess2DSystem is a graphics system running the post-processing chain of each camera drawing the frame.
+// The scene is redirected into an off-screen target before the camera draws anything, and presented with effects applied once the system executes,
+// so it should be executed right after Renderer2DSystem. Graphics systems executed later, such as debug drawing, are not affected by effects.
+type PostProcess2DSystem struct {
+	*base.SystemBase
+	processor *graphics.PostProcessor // chain of the camera drawing.
+}
+
+func NewPostProcess2DSystem(priority int) *PostProcess2DSystem {
+	return &PostProcess2DSystem{
+		SystemBase: base.NewSystemBase(priority),
+	}
+}
+
+// PreRenderCamera is an implementation of ICameraRenderSystem.
+func (s *PostProcess2DSystem) PreRenderCamera() {
+	s.processor = graphics.GetRenderingCamera().PostProcessor()
+	s.processor.Begin()
+}
+
+// ===== IMPLEMENTATION =====
+
+func (s *PostProcess2DSystem) Execute(_ *cc.Executor) {
+	if s.processor == nil {
+		return
+	}
+	s.processor.End()
+	s.processor = nil
+}
+
+func (s *PostProcess2DSystem) GetSystemBase() *base.SystemBase {
+	return s.SystemBase
+}
+
+func (s *PostProcess2DSystem) GetName() string {
+	return NamePostProcess2DSystem
+}
+
+func (s *PostProcess2DSystem) Register(iobj base.IGameObject2D) {}
+
+func (s *PostProcess2DSystem) Unregister(iobj base.IGameObject2D) {}
+
+func (s *PostProcess2DSystem) Activate(iobj base.IGameObject2D) {}
+
+func (s *PostProcess2DSystem) Deactivate(iobj base.IGameObject2D) {}
@@ -1,8 +1,6 @@
 package system
 
 import (
-	"fmt"
-
 	"galaxyzeta.io/engine/base"
 	"galaxyzeta.io/engine/collision"
 	"galaxyzeta.io/engine/ecs/component"
@@ -34,34 +32,7 @@ type QuadTreeCollision2DSystem struct {
 }
 
 func (s *QuadTreeCollision2DSystem) execute(executor *cc.Executor) {
-	rmPolygonColliders := []*component.PolygonCollider{}
-	rmNodes := []*collision.QTreeNode{}
-	s.qt.Traverse(func(pc *component.PolygonCollider, node *collision.QTreeNode, at collision.AreaType, idx int) bool {
-		if at == collision.Inline {
-			// inline object: checks intersection with its child nodes.
-			if val := node.GetIntersectedSection(pc.Collider.GetBoundingBox()); val >= 0 {
-				rmNodes = append(rmNodes, node)
-				rmPolygonColliders = append(rmPolygonColliders, pc)
-			}
-		} else {
-			// not inline object: checks intersection with its currently related nodes
-			pcRect := pc.Collider.GetBoundingBox().ToRectangle()
-			if !pcRect.Intersect(node.GetArea()) {
-				if pc.I().Obj().Name == "player" {
-					fmt.Print("")
-				}
-				rmPolygonColliders = append(rmPolygonColliders, pc)
-				rmNodes = append(rmNodes, node)
-			}
-		}
-		return false
-	})
-	for idx, elem := range rmPolygonColliders {
-		rmNodes[idx].Delete(elem)
-	}
-	for _, elem := range rmPolygonColliders {
-		s.qt.Insert(elem)
-	}
+	s.qt.Update()
 }
 
 // ===== debug only =====
@@ -75,30 +46,36 @@ func (s *QuadTreeCollision2DSystem) Traverse(needLock bool, f collision.QTreeTra
 
 // ===== Functional Implementation =====
 
-func (s *QuadTreeCollision2DSystem) QueryNeighborCollidersWithCollider(col component.PolygonCollider, mode collision.QueryMode) []*component.PolygonCollider {
-	col.Collider.GetBoundingBox()
-	return s.QueryNeighborCollidersWithRect(col.Collider.GetBoundingBox().ToRectangle(), mode)
+func (s *QuadTreeCollision2DSystem) QueryNeighborCollidersWithCollider(col component.Collider, mode collision.QueryMode) []*component.Collider {
+	return s.QueryNeighborCollidersWithRect(col.Shape.GetBoundingBox().ToRectangle(), mode)
 }
 
-func (s *QuadTreeCollision2DSystem) QueryNeighborCollidersWithColliderAndFilter(col component.PolygonCollider, filter func(*component.PolygonCollider) bool, mode collision.QueryMode) []*component.PolygonCollider {
-	return s.QueryNeighborCollidersWithPositionAndFilter(*col.Collider.GetAnchor(), filter, mode)
+func (s *QuadTreeCollision2DSystem) QueryNeighborCollidersWithColliderAndFilter(col component.Collider, filter func(*component.Collider) bool, mode collision.QueryMode) []*component.Collider {
+	li := s.QueryNeighborCollidersWithRect(col.Shape.GetBoundingBox().ToRectangle(), mode)
+	var ret []*component.Collider
+	for _, collider := range li {
+		if filter(collider) {
+			ret = append(ret, collider)
+		}
+	}
+	return ret
 }
 
-func (s *QuadTreeCollision2DSystem) QueryNeighborCollidersWithPosition(pos linalg.Vector2f64, mode collision.QueryMode) []*component.PolygonCollider {
+func (s *QuadTreeCollision2DSystem) QueryNeighborCollidersWithPosition(pos linalg.Vector2f64, mode collision.QueryMode) []*component.Collider {
 	return s.qt.QueryByPoint(pos, mode)
 }
 
-func (s *QuadTreeCollision2DSystem) QueryNeighborCollidersWithRect(r physics.Rectangle, mode collision.QueryMode) []*component.PolygonCollider {
+func (s *QuadTreeCollision2DSystem) QueryNeighborCollidersWithRect(r physics.Rectangle, mode collision.QueryMode) []*component.Collider {
 	return s.qt.QueryByRect(r, mode)
 }
 
-func (s *QuadTreeCollision2DSystem) QueryNeighborCollidersWithRay(r physics.Ray, mode collision.QueryMode) []*component.PolygonCollider {
+func (s *QuadTreeCollision2DSystem) QueryNeighborCollidersWithRay(r physics.Ray, mode collision.QueryMode) []*component.Collider {
 	return s.qt.QueryByRay(r, mode)
 }
 
-func (s *QuadTreeCollision2DSystem) QueryNeighborCollidersWithPositionAndFilter(pos linalg.Vector2f64, filter func(*component.PolygonCollider) bool, mode collision.QueryMode) []*component.PolygonCollider {
+func (s *QuadTreeCollision2DSystem) QueryNeighborCollidersWithPositionAndFilter(pos linalg.Vector2f64, filter func(*component.Collider) bool, mode collision.QueryMode) []*component.Collider {
 	li := s.qt.QueryByPoint(pos, mode)
-	var ret []*component.PolygonCollider
+	var ret []*component.Collider
 	for _, collider := range li {
 		if filter(collider) {
 			ret = append(ret, collider)
@@ -122,30 +99,49 @@ func (s *QuadTreeCollision2DSystem) GetName() string {
 }
 
 func (s *QuadTreeCollision2DSystem) Register(iobj base.IGameObject2D) {
-	ipc := iobj.Obj().GetComponent(component.NamePolygonCollider)
-	pc := ipc.(*component.PolygonCollider)
+	ipc := iobj.Obj().GetComponent(component.NameCollider)
+	pc := ipc.(*component.Collider)
+	bindColliderTransform(iobj, pc)
 	s.qt.Insert(pc)
+	// colliders are relocated only when their transforms have changed.
+	if itf, ok := iobj.Obj().GetAllComponents()[component.NameTransform2D]; ok {
+		itf.(*component.Transform2D).AddChangeListener(s, func(*component.Transform2D) {
+			s.qt.MarkDirty(pc)
+		})
+	}
+	if pc.Sr != nil {
+		pc.Sr.AddFrameChangeListener(s, func(*component.SpriteRenderer) {
+			s.qt.MarkDirty(pc)
+		})
+	}
 }
 
 func (s *QuadTreeCollision2DSystem) Unregister(iobj base.IGameObject2D) {
-	testpc := iobj.Obj().GetComponent(component.NamePolygonCollider)
-	s.qt.TraverseWithLock(func(pc *component.PolygonCollider, qn *collision.QTreeNode, _ collision.AreaType, _ int) bool {
-		if testpc == pc {
-			qn.UnsafeDelete(pc)
-			return true
-		}
-		return false
-	})
+	pc := iobj.Obj().GetComponent(component.NameCollider).(*component.Collider)
+	if itf, ok := iobj.Obj().GetAllComponents()[component.NameTransform2D]; ok {
+		itf.(*component.Transform2D).RemoveChangeListener(s)
+	}
+	if pc.Sr != nil {
+		pc.Sr.RemoveFrameChangeListener(s)
+	}
+	s.qt.Delete(pc)
 }
 
 func (s *QuadTreeCollision2DSystem) Activate(iobj base.IGameObject2D) {
-	ipc := iobj.Obj().GetComponent(component.NamePolygonCollider)
-	pc := ipc.(*component.PolygonCollider)
+	ipc := iobj.Obj().GetComponent(component.NameCollider)
+	pc := ipc.(*component.Collider)
 	s.qt.Activate(pc)
 }
 
 func (s *QuadTreeCollision2DSystem) Deactivate(iobj base.IGameObject2D) {
-	ipc := iobj.Obj().GetComponent(component.NamePolygonCollider)
-	pc := ipc.(*component.PolygonCollider)
+	ipc := iobj.Obj().GetComponent(component.NameCollider)
+	pc := ipc.(*component.Collider)
 	s.qt.Deactivate(pc)
 }
+
+// bindColliderTransform makes the collider follow rotation and scale of the transform of the gameObject, if it has one.
+func bindColliderTransform(iobj base.IGameObject2D, pc *component.Collider) {
+	if itf, ok := iobj.Obj().TryGetComponent(component.NameTransform2D); ok {
+		pc.BindTransform(itf.(*component.Transform2D))
+	}
+}
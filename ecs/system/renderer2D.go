@@ -13,9 +13,12 @@ var NameRenderer2DSystem = "sys_Renderer2D"
 
 type Renderer2DSystem struct {
 	*base.SystemBase
-	renderers       []graphics.IRenderable       // dynamically re-arranged according to elements' Z coordinate.
-	staticRenderers []graphics.IRenderable       // will not be sorted, has a static Z coordinate. Register to this slice to optimize your game performace.
-	indexer         map[graphics.IRenderable]int // indexer is meant to be updated after every iteration of rendering. It is useful when we try to delete an element
+	renderers       []graphics.IRenderable       // dynamically re-arranged according to elements' sorting layers and Z coordinate.
+	staticRenderers []graphics.IRenderable       // will not be sorted, has a static sorting layer and Z coordinate. Register to this slice to optimize your game performace.
+	indexer         map[graphics.IRenderable]int // position of each renderable in its slice, which is updated whenever it moves. It is useful when we try to delete an element
+	batch           *graphics.SpriteBatch        // renderables are drawn into the batch, which is flushed only on texture or shader changes.
+	stats           graphics.RenderStats         // stats of the frame being drawn, summed over cameras.
+	frameStats      graphics.RenderStats         // stats of the last frame drawn.
 	logger          *logger.Logger
 }
 
@@ -25,40 +28,59 @@ func NewRenderer2DSystem(priority int) *Renderer2DSystem {
 		indexer:         map[graphics.IRenderable]int{},
 		renderers:       []graphics.IRenderable{},
 		staticRenderers: []graphics.IRenderable{},
+		batch:           graphics.NewSpriteBatch(graphics.DefaultBatchSize),
 		logger:          logger.New(NameRenderer2DSystem),
 	}
 }
 
-func (ren *Renderer2DSystem) execute(_ *cc.Executor) {
-	cam := graphics.GetCurrentCamera()
+// PreRender is an implementation of IPreRenderSystem, renderers are sorted once for all cameras drawing the frame.
+func (ren *Renderer2DSystem) PreRender() {
+	ren.frameStats = ren.stats
+	ren.stats = graphics.RenderStats{}
 	// sort spriteRenderers first
 	sort.SliceStable(ren.renderers, func(i, j int) bool {
-		return ren.renderers[i].Z() < ren.renderers[j].Z()
+		return graphics.DrawsBefore(ren.renderers[i], ren.renderers[j])
 	})
+	for idx, r := range ren.renderers {
+		ren.indexer[r] = idx
+	}
+}
+
+// execute draws the scene through the camera drawing, renderables on layers out of its layer mask are skipped.
+func (ren *Renderer2DSystem) execute(_ *cc.Executor) {
+	cam := graphics.GetRenderingCamera()
+	mask := cam.GetLayerMask()
+	ren.batch.Begin(cam)
 	ptr1, ptr2 := 0, 0
-	idx := 0
 	for ptr1 < len(ren.renderers) && ptr2 < len(ren.staticRenderers) {
-		if ren.renderers[ptr1].Z() >= ren.staticRenderers[ptr2].Z() {
-			ren.doRenderExecute(&ptr1, &idx, cam, ren.renderers)
+		if !graphics.DrawsBefore(ren.staticRenderers[ptr2], ren.renderers[ptr1]) {
+			ren.doRenderExecute(&ptr1, ren.renderers, mask)
 		} else {
-			ren.doRenderExecute(&ptr2, &idx, cam, ren.staticRenderers)
+			ren.doRenderExecute(&ptr2, ren.staticRenderers, mask)
 		}
 	}
 	for ptr1 < len(ren.renderers) {
-		ren.doRenderExecute(&ptr1, &idx, cam, ren.renderers)
+		ren.doRenderExecute(&ptr1, ren.renderers, mask)
 	}
 	for ptr2 < len(ren.staticRenderers) {
-		ren.doRenderExecute(&ptr2, &idx, cam, ren.staticRenderers)
+		ren.doRenderExecute(&ptr2, ren.staticRenderers, mask)
 	}
+	ren.batch.End()
+	ren.stats = ren.stats.Add(ren.batch.Stats())
 }
 
-func (ren *Renderer2DSystem) doRenderExecute(ptr *int, idx *int, cam *graphics.Camera, targetSlice []graphics.IRenderable) {
-	sr := ren.renderers[*ptr]
-	sr.Render(cam)
-	ren.indexer[sr] = *idx
-	sr.PostRender()
+func (ren *Renderer2DSystem) doRenderExecute(ptr *int, targetSlice []graphics.IRenderable, mask uint32) {
+	sr := targetSlice[*ptr]
+	if mask&graphics.LayerMask(graphics.LayerOf(sr)) != 0 {
+		sr.Render(ren.batch)
+		sr.PostRender()
+	}
 	*ptr++
-	*idx++
+}
+
+// GetRenderStats returns draw calls, vertices and quads of the last rendered frame, summed over cameras.
+func (ren *Renderer2DSystem) GetRenderStats() graphics.RenderStats {
+	return ren.frameStats
 }
 
 // ===== IMPLEMENTATION =====
@@ -83,10 +105,13 @@ func (s *Renderer2DSystem) Register(iobj base.IGameObject2D) {
 			continue
 		}
 		if ren.IsStatic() {
-			pos := s.binarySearchStatic(ren.Z())
-			copy(s.staticRenderers[pos:], s.staticRenderers[pos+1:])
+			pos := s.binarySearchStatic(ren)
+			s.staticRenderers = append(s.staticRenderers, nil)
+			copy(s.staticRenderers[pos+1:], s.staticRenderers[pos:])
 			s.staticRenderers[pos] = ren
-			s.indexer[ren] = pos
+			for i := pos; i < len(s.staticRenderers); i++ {
+				s.indexer[s.staticRenderers[i]] = i
+			}
 		} else {
 			s.renderers = append(s.renderers, ren)
 			s.indexer[ren] = len(s.renderers) - 1
@@ -95,22 +120,17 @@ func (s *Renderer2DSystem) Register(iobj base.IGameObject2D) {
 
 }
 
-// TODO need test
-func (s *Renderer2DSystem) binarySearchStatic(z int64) int {
+// binarySearchStatic returns the position to insert a static renderable at, which is after static renderables drawn no later than it.
+func (s *Renderer2DSystem) binarySearchStatic(ren graphics.IRenderable) int {
 	left, right := 0, len(s.staticRenderers)-1
 	for left <= right {
-		mid := left + (right-left)<<1
-		if s.staticRenderers[mid].Z() < z {
-			left = mid + 1
-		} else if s.staticRenderers[mid].Z() == z {
-			return mid
-		} else {
+		mid := left + (right-left)>>1
+		if graphics.DrawsBefore(ren, s.staticRenderers[mid]) {
 			right = mid - 1
+		} else {
+			left = mid + 1
 		}
 	}
-	if left < 0 {
-		return 0
-	}
 	return left
 }
 
@@ -127,7 +147,9 @@ func (s *Renderer2DSystem) Unregister(iobj base.IGameObject2D) {
 				s.staticRenderers[j] = s.staticRenderers[i]
 				s.indexer[s.staticRenderers[j]] = j
 			}
+			s.staticRenderers[len(s.staticRenderers)-1] = nil
 			s.staticRenderers = s.staticRenderers[:len(s.staticRenderers)-1]
+			delete(s.indexer, ren)
 		} else {
 			// order here is not important here
 			// because all elements will be sorted again before next rendering process.
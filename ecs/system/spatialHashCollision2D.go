@@ -0,0 +1,108 @@
+package system
+
+import (
+	"galaxyzeta.io/engine/base"
+	"galaxyzeta.io/engine/collision"
+	"galaxyzeta.io/engine/ecs/component"
+	cc "galaxyzeta.io/engine/infra/concurrency"
+	"galaxyzeta.io/engine/linalg"
+	"galaxyzeta.io/engine/physics"
+)
+
+func NewSpatialHashCollision2DSystem(priority int, cellWidth float64, cellHeight float64) *SpatialHashCollision2DSystem {
+	return &SpatialHashCollision2DSystem{
+		SystemBase: base.NewSystemBase(priority),
+		sh:         collision.NewSpatialHash(cellWidth, cellHeight),
+	}
+}
+
+// SpatialHashCollision2DSystem manages all game colliders with a uniform grid.
+// It is registered with the same name as QuadTreeCollision2DSystem, so only one of them could be used at a time.
+type SpatialHashCollision2DSystem struct {
+	*base.SystemBase
+	sh *collision.SpatialHash
+}
+
+func (s *SpatialHashCollision2DSystem) execute(executor *cc.Executor) {
+	s.sh.Refresh()
+}
+
+// ===== debug only =====
+func (s *SpatialHashCollision2DSystem) Traverse(f func(*component.Collider, bool) bool) {
+	s.sh.Traverse(f)
+}
+
+// ===== Functional Implementation =====
+
+func (s *SpatialHashCollision2DSystem) QueryNeighborCollidersWithCollider(col component.Collider, mode collision.QueryMode) []*component.Collider {
+	return s.QueryNeighborCollidersWithRect(col.Shape.GetBoundingBox().ToRectangle(), mode)
+}
+
+func (s *SpatialHashCollision2DSystem) QueryNeighborCollidersWithColliderAndFilter(col component.Collider, filter func(*component.Collider) bool, mode collision.QueryMode) []*component.Collider {
+	li := s.QueryNeighborCollidersWithRect(col.Shape.GetBoundingBox().ToRectangle(), mode)
+	var ret []*component.Collider
+	for _, collider := range li {
+		if filter(collider) {
+			ret = append(ret, collider)
+		}
+	}
+	return ret
+}
+
+func (s *SpatialHashCollision2DSystem) QueryNeighborCollidersWithPosition(pos linalg.Vector2f64, mode collision.QueryMode) []*component.Collider {
+	return s.sh.QueryByPoint(pos, mode)
+}
+
+func (s *SpatialHashCollision2DSystem) QueryNeighborCollidersWithRect(r physics.Rectangle, mode collision.QueryMode) []*component.Collider {
+	return s.sh.QueryByRect(r, mode)
+}
+
+func (s *SpatialHashCollision2DSystem) QueryNeighborCollidersWithRay(r physics.Ray, mode collision.QueryMode) []*component.Collider {
+	return s.sh.QueryByRay(r, mode)
+}
+
+func (s *SpatialHashCollision2DSystem) QueryNeighborCollidersWithPositionAndFilter(pos linalg.Vector2f64, filter func(*component.Collider) bool, mode collision.QueryMode) []*component.Collider {
+	li := s.sh.QueryByPoint(pos, mode)
+	var ret []*component.Collider
+	for _, collider := range li {
+		if filter(collider) {
+			ret = append(ret, collider)
+		}
+	}
+	return ret
+}
+
+// ===== IMPLEMENTATION =====
+
+func (s *SpatialHashCollision2DSystem) Execute(executor *cc.Executor) {
+	s.execute(executor)
+}
+
+func (s *SpatialHashCollision2DSystem) GetSystemBase() *base.SystemBase {
+	return s.SystemBase
+}
+
+func (s *SpatialHashCollision2DSystem) GetName() string {
+	return NameCollision2Dsystem
+}
+
+func (s *SpatialHashCollision2DSystem) Register(iobj base.IGameObject2D) {
+	pc := iobj.Obj().GetComponent(component.NameCollider).(*component.Collider)
+	bindColliderTransform(iobj, pc)
+	s.sh.Insert(pc)
+}
+
+func (s *SpatialHashCollision2DSystem) Unregister(iobj base.IGameObject2D) {
+	pc := iobj.Obj().GetComponent(component.NameCollider).(*component.Collider)
+	s.sh.Delete(pc)
+}
+
+func (s *SpatialHashCollision2DSystem) Activate(iobj base.IGameObject2D) {
+	pc := iobj.Obj().GetComponent(component.NameCollider).(*component.Collider)
+	s.sh.Activate(pc)
+}
+
+func (s *SpatialHashCollision2DSystem) Deactivate(iobj base.IGameObject2D) {
+	pc := iobj.Obj().GetComponent(component.NameCollider).(*component.Collider)
+	s.sh.Deactivate(pc)
+}
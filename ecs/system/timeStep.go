@@ -0,0 +1,18 @@
+package system
+
+// fixedStep is embedded by systems stepping by the game clock, whose steps take a fixed amount of seconds.
+type fixedStep struct {
+	timeStep float64
+}
+
+func newFixedStep() fixedStep {
+	return fixedStep{timeStep: 1.0 / 60}
+}
+
+// SetTimeStep sets seconds elapsed in each step, it should match the physics fps.
+func (s *fixedStep) SetTimeStep(dt float64) {
+	if dt <= 0 {
+		panic("time step must be positive")
+	}
+	s.timeStep = dt
+}
@@ -0,0 +1,15 @@
+#version 410 core
+uniform sampler2D tex;
+uniform float flash;
+uniform vec4 flashColor;
+
+in vec2 fragTexCoord;
+in vec4 fragTint;
+
+out vec4 FragColor;
+
+// mixes colors of the texture with flashColor by flash, alpha is kept.
+void main() {
+    vec4 color = texture(tex, fragTexCoord) * fragTint;
+    FragColor = vec4(mix(color.rgb, flashColor.rgb, flash * flashColor.a), color.a);
+}
\ No newline at end of file
@@ -22,7 +22,7 @@ func init() {
 type TestBlock struct {
 	*base.GameObject2D
 	tf   *component.Transform2D
-	pc   *component.PolygonCollider
+	pc   *component.Collider
 	sr   *component.SpriteRenderer
 	csys collision.ICollisionSystem
 }
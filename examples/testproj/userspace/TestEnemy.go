@@ -48,6 +48,7 @@ func TestEnemy_OnCreate() base.IGameObject2D {
 		Pivot: &physics.Pivot{
 			Option: physics.PivotOption_BottomCenter,
 		},
+		Material: graphics.GetMaterial("mat_flash"),
 	})
 	this.pc = component.NewPolygonCollider(animator.Spr().GetHitbox(&this.tf.Pos, physics.Pivot{Option: physics.PivotOption_BottomCenter}), this)
 	this.logger = logger.New("miner")
@@ -80,12 +81,14 @@ func __TestEnemy_OnStep(iobj base.IGameObject2D) {
 	if this.hp <= 0 {
 		sdk.Destroy(this)
 	}
-	if time.Since(this.lastHitTime) < this.hitPreventionCD {
+	if since := time.Since(this.lastHitTime); since < this.hitPreventionCD {
 		// hit prevention
 		// TODO implement with a phaser effector
 		this.sr.Animator.Spr().DisableAnimation()
+		this.sr.Material.SetFloat("flash", 1-float64(since)/float64(this.hitPreventionCD))
 	} else {
 		this.sr.Animator.Spr().EnableAnimation()
+		this.sr.Material.SetFloat("flash", 0)
 	}
 }
 
@@ -103,7 +106,7 @@ func __TestEnemy_OnRender(obj base.IGameObject2D) {
 	graphics.DrawSegment(linalg.NewSegmentf64(srx-4, sry, srx+4, sry), linalg.NewRgbaF64(0, 1, 0, 1))
 	graphics.DrawSegment(linalg.NewSegmentf64(srx, sry-4, srx, sry+4), linalg.NewRgbaF64(0, 1, 0, 1))
 
-	graphics.DrawRectangle(this.pc.Collider.GetBoundingBox().ToRectangle(), linalg.NewRgbaF64(0, 1, 0, 1))
+	graphics.DrawRectangle(this.pc.Shape.GetBoundingBox().ToRectangle(), linalg.NewRgbaF64(0, 1, 0, 1))
 
 	// mark tf anchor center
 	tfx := this.tf.Pos.X
@@ -126,4 +129,5 @@ func (t *TestEnemy) TakeDamage(dmg int) {
 	t.logger.Debugf("take damage = %d", dmg)
 	t.hp -= dmg
 	t.lastHitTime = time.Now()
+	sdk.ShakeCamera(0.4)
 }
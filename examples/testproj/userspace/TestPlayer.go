@@ -5,7 +5,6 @@ All user defined 2D objects should be put here.
 package objs
 
 import (
-	"container/list"
 	"fmt"
 	"math"
 	"time"
@@ -40,30 +39,24 @@ type TestPlayer struct {
 	BasicComponentsBundle
 	logger *logger.Logger
 
+	cc *component.CharacterController2D
+
 	// -- user defined
-	canJump            bool          // whether the user can jump or not
-	isAir              bool          // whether the user is in air
-	lastJumpTime       time.Time     // last player jump time
-	jumpPreventionTime time.Duration // stop the user from operating a jump in this duration
-	speed              float64
-	jumpForceElem      *list.Element
+	speed float64 // horizontal speed in pixels per second
 
 	hp int
 }
 
-//TestPlayer_OnCreate is a public constructor.
+// TestPlayer_OnCreate is a public constructor.
 func TestPlayer_OnCreate() base.IGameObject2D {
 	fmt.Println("SDK Call onCreate")
 
 	this := &TestPlayer{}
 
-	animator := graphics.NewAnimator(graphics.StateClipPair{
-		State: "run",
-		Clip:  graphics.NewSpriteInstance("spr_megaman"),
-	})
+	animator := graphics.NewAnimatorFromStateMachine(graphics.GetAnimStateMachine("anim_megaman"))
 
 	this.tf = component.NewTransform2D()
-	this.rb = component.NewRigidBody2D()
+	this.cc = component.NewCharacterController2D()
 	this.sr = component.NewSpriteRendererWithOptions(animator, this.tf, false, graphics.RenderOptions{
 		Pivot: &physics.Pivot{
 			Option: physics.PivotOption_BottomCenter,
@@ -75,53 +68,47 @@ func TestPlayer_OnCreate() base.IGameObject2D {
 		RegisterRender(__TestPlayer_OnRender).
 		RegisterStep(__TestPlayer_OnStep).
 		RegisterDestroy(__TestPlayer_OnDestroy).
+		RegisterAnimationEvent(__TestPlayer_OnAnimationEvent).
 		RegisterComponentIfAbsent(this.tf).
-		RegisterComponentIfAbsent(this.rb).
+		RegisterComponentIfAbsent(this.cc).
 		RegisterComponentIfAbsent(this.pc).
 		RegisterComponentIfAbsent(this.sr)
 
-	// Enable gravity
-	this.rb.UseGravity = true
-	this.rb.SetGravity(270, 0.15)
-
 	this.logger = logger.New("player")
 	this.csys = core.GetSystem(system.NameCollision2Dsystem).(collision.ICollisionSystem)
 
-	core.SubscribeSystem(this, system.NamePhysics2DSystem)
+	core.SubscribeSystem(this, system.NameCharacterController2DSystem)
 	core.SubscribeSystem(this, system.NameCollision2Dsystem)
 	core.SubscribeSystem(this, system.NameRenderer2DSystem)
 
-	this.jumpPreventionTime = time.Millisecond * 50
-	this.lastJumpTime = time.Now()
-	this.speed = 2
+	this.speed = 120
 
 	return this
 }
 
-//__TestPlayer_OnStep is intentionally names with two underlines,
+// __TestPlayer_OnStep is intentionally names with two underlines,
 // telling user never call this function in other functions, that will not work,
 // even damaging the whole game logic.
 func __TestPlayer_OnStep(obj base.IGameObject2D) {
 	this := obj.(*TestPlayer)
 	isKeyHeld := false
 
-	var dx float64 = 0
-	var dy float64 = 0
-
 	// restart
 	if input.IsKeyPressed(keys.KeyR) {
 		sdk.ChangeScene("sc1")
 	}
 
-	// movement
-	if input.IsKeyHeld(keys.KeyA) && !collision.HasColliderAtPolygonWithTag(this.csys, this.pc.Collider.Shift(-this.speed*2, 0), "solid", collision.ActiveOnly) {
-		dx = -this.speed
-		this.sr.Scale.X = -1
+	// movement, walls and slopes are handled by the character controller
+	if input.IsKeyHeld(keys.KeyA) {
+		this.cc.Move(-this.speed)
+		this.tf.Scale.X = -1
 		isKeyHeld = true
-	} else if input.IsKeyHeld(keys.KeyD) && !collision.HasColliderAtPolygonWithTag(this.csys, this.pc.Collider.Shift(this.speed*2, 0), "solid", collision.ActiveOnly) {
-		dx = this.speed
-		this.sr.Scale.X = 1
+	} else if input.IsKeyHeld(keys.KeyD) {
+		this.cc.Move(this.speed)
+		this.tf.Scale.X = 1
 		isKeyHeld = true
+	} else {
+		this.cc.Move(0)
 	}
 
 	// movement of the camera
@@ -142,113 +129,87 @@ func __TestPlayer_OnStep(obj base.IGameObject2D) {
 		cam.Translate(0, 3)
 	}
 
-	// change camera
-	if input.IsKeyPressed(keys.KeyP) {
-		if graphics.GetCurrentCameraIndex() == 0 {
-			this.logger.Debugf("change camera to 1")
-			graphics.SetCurrentCamera(1)
+	// follow the player with the camera, which could then only be moved inside the room.
+	if input.IsKeyPressed(keys.KeyF) {
+		if sdk.GetCameraController().IsFollowing() {
+			sdk.GetCameraController().Follow(nil)
 		} else {
-			this.logger.Debugf("change camera to 0")
-			graphics.SetCurrentCamera(0)
+			sdk.TransitionCameraTo(this, 500*time.Millisecond)
 		}
 	}
 
+	// zoom of the camera
+	if input.IsKeyPressed(keys.KeyZ) {
+		cam := graphics.GetCurrentCamera()
+		cam.SetZoom(math.Min(cam.GetZoom()*1.25, 4))
+	}
+	if input.IsKeyPressed(keys.KeyX) {
+		cam := graphics.GetCurrentCamera()
+		cam.SetZoom(math.Max(cam.GetZoom()/1.25, 0.25))
+	}
+
+	// minimap, which is drawn by camera 1 over camera 0
+	if input.IsKeyPressed(keys.KeyP) {
+		minimap := graphics.GetCamera(1)
+		minimap.SetEnabled(!minimap.IsEnabled())
+		this.logger.Debugf("minimap enabled = %v", minimap.IsEnabled())
+	}
+
 	// shoot
 	if input.IsKeyPressed(keys.MouseButton1) {
 		projectile := sdk.Create(TestProjectile_OnCreate).(*TestProjectile)
 		projectile.selfDestructDuration = time.Second
 		projectile.owner = this
-		cx, cy := core.GetCursorPos()
+		cursor := sdk.CursorWorldPos()
 		ox := this.tf.X()
 		oy := this.tf.Y() - 16
-		projectile.directionRad = math.Atan2(cy-oy, cx-ox)
+		projectile.directionRad = math.Atan2(cursor.Y-oy, cursor.X-ox)
 		projectile.speed = 5
 		projectile.tf.Pos = linalg.NewVector2f64(ox, oy)
 
-		this.logger.Debugf("create bullet, mouse cursor is at %f, %f", cx, cy)
+		this.logger.Debugf("create bullet, mouse cursor is at %f, %f", cursor.X, cursor.Y)
 	}
 
 	// change speed
 	if input.IsKeyPressed(keys.KeyE) {
-		this.speed += 1
+		this.speed += 60
 	}
 	if input.IsKeyPressed(keys.KeyQ) {
-		this.speed -= 1
+		this.speed -= 60
 	}
 
-	// jump
-	if input.IsKeyPressed(keys.KeyW) && this.canJump {
-		this.canJump = false
-		this.lastJumpTime = time.Now()
-		this.jumpForceElem = this.rb.AddForce(component.SpeedVector{
-			Acceleration: 1,
-			Direction:    90,
-			Speed:        12,
-		})
+	// jump, a short press makes a low jump
+	if input.IsKeyPressed(keys.KeyW) {
+		this.cc.Jump()
+	} else if !input.IsKeyHeld(keys.KeyW) {
+		this.cc.ReleaseJump()
 	}
 
 	// animation
-	if isKeyHeld {
-		this.sr.Spr().EnableAnimation()
-	} else {
-		this.sr.Spr().DisableAnimation()
-	}
-
-	vspeed := this.rb.GetVspeed()
-	if val := collision.ColliderAtPolygonWithTag(this.csys, this.pc.Collider.Shift(0, vspeed), "solid", collision.ActiveOnly); val != nil {
-		// something is above the player / beneath the player
-		if this.isAir {
-			if vspeed < 0 {
-				// player ascending, detect ceil
-				this.logger.Debug("ceil")
-				this.rb.RemoveForce(this.jumpForceElem)
-				this.jumpForceElem = nil
-
-			} else if vspeed > 0 {
-				// player descending
-				if time.Since(this.lastJumpTime) > this.jumpPreventionTime {
-					// already jumped into the air, and in next frame he will go into ground
-					// in order to prevent this, snap him to the ground
-					this.logger.Debug("ground")
-
-					this.rb.RemoveForce(this.jumpForceElem)
-					this.jumpForceElem = nil
-
-					this.tf.Pos.Y += (val.Collider.GetAnchor().Y - this.pc.Collider.GetAnchor().Y) // snap to the ground
-					this.isAir = false
-					this.canJump = true
-				}
-				// player has just jumped, but we don't want to pull him back immediately.
-			}
-		}
-		// something above/beneath but the player is grounded, in this case do nothing
-	} else {
-		// if vspeed has variation, then:
-		// 1. player is jumping;
-		// 2. floor collapsed, triggers isAir = true, thus increased gravity speed, causing vspeed to change
-		if vspeed != 0 {
-			this.isAir = true
-			this.canJump = false
-		}
-	}
+	this.sr.SetBool("moving", isKeyHeld)
+}
 
-	this.tf.Translate(dx, dy)
+func __TestPlayer_OnAnimationEvent(obj base.IGameObject2D, event string) {
+	this := obj.(*TestPlayer)
+	this.logger.Debugf("animation event %s", event)
 }
 
 func __TestPlayer_OnRender(obj base.IGameObject2D) {
 	this := obj.(*TestPlayer)
-	this.csys.(*system.QuadTreeCollision2DSystem).Traverse(true, func(pc *component.PolygonCollider, qn *collision.QTreeNode, at collision.AreaType, idx int) bool {
-		graphics.DrawRectangle(qn.GetArea(), linalg.NewRgbaF64(0, 1, 0, 1))
-		if pc == nil {
-			this.logger.Warn("encounter empty PC while traversing, concurrent problem")
-			return false // no lock, which has a big posibility of encountering empty pc
-		}
-		if pc.I().Obj().Name == "player" {
-			graphics.DrawRectangle(pc.Collider.GetBoundingBox().ToRectangle(), linalg.NewRgbaF64(0, 0, 1, 1))
-			graphics.DrawRectangle(qn.GetArea().CropOutside(-1, -1), linalg.NewRgbaF64(1, 0, 0, 1))
-		}
-		return false
-	})
+	if qtsys, ok := this.csys.(*system.QuadTreeCollision2DSystem); ok {
+		qtsys.Traverse(true, func(pc *component.Collider, qn *collision.QTreeNode, at collision.AreaType, idx int) bool {
+			graphics.DrawRectangle(qn.GetArea(), linalg.NewRgbaF64(0, 1, 0, 1))
+			if pc == nil {
+				this.logger.Warn("encounter empty PC while traversing, concurrent problem")
+				return false // no lock, which has a big posibility of encountering empty pc
+			}
+			if pc.I().Obj().Name == "player" {
+				graphics.DrawRectangle(pc.Shape.GetBoundingBox().ToRectangle(), linalg.NewRgbaF64(0, 0, 1, 1))
+				graphics.DrawRectangle(qn.GetArea().CropOutside(-1, -1), linalg.NewRgbaF64(1, 0, 0, 1))
+			}
+			return false
+		})
+	}
 
 	// mark sprite anchor center
 	srx := this.sr.GetHitbox().GetAnchor().X
@@ -256,7 +217,7 @@ func __TestPlayer_OnRender(obj base.IGameObject2D) {
 	graphics.DrawSegment(linalg.NewSegmentf64(srx-4, sry, srx+4, sry), linalg.NewRgbaF64(0, 1, 0, 1))
 	graphics.DrawSegment(linalg.NewSegmentf64(srx, sry-4, srx, sry+4), linalg.NewRgbaF64(0, 1, 0, 1))
 
-	graphics.DrawRectangle(this.pc.Collider.GetBoundingBox().ToRectangle(), linalg.NewRgbaF64(0, 1, 0, 1))
+	graphics.DrawRectangle(this.pc.Shape.GetBoundingBox().ToRectangle(), linalg.NewRgbaF64(0, 1, 0, 1))
 
 	// mark tf anchor center
 	tfx := this.tf.Pos.X
@@ -264,13 +225,13 @@ func __TestPlayer_OnRender(obj base.IGameObject2D) {
 	graphics.DrawSegment(linalg.NewSegmentf64(tfx-4, tfy, tfx+4, tfy), linalg.NewRgbaF64(1, 0, 0, 1))
 	graphics.DrawSegment(linalg.NewSegmentf64(tfx, tfy-4, tfx, tfy+4), linalg.NewRgbaF64(1, 0, 0, 1))
 
-	// if this.canJump {
+	// if this.cc.IsGrounded() {
 	// 	graphics.DrawSegment(linalg.NewSegmentf64(this.tf.X(), this.tf.Y(), this.tf.X()+32, this.tf.Y()), linalg.NewRgbaF64(0, 1, 0, 0))
 	// } else {
 	// 	graphics.DrawSegment(linalg.NewSegmentf64(this.tf.X(), this.tf.Y(), this.tf.X()+32, this.tf.Y()), linalg.NewRgbaF64(1, 0, 0, 0))
 	// }
 
-	// if this.isAir {
+	// if !this.cc.IsGrounded() {
 	// 	graphics.DrawSegment(linalg.NewSegmentf64(this.tf.X()+32, this.tf.Y(), this.tf.X()+64, this.tf.Y()), linalg.NewRgbaF64(0, 1, 0, 0))
 	// } else {
 	// 	graphics.DrawSegment(linalg.NewSegmentf64(this.tf.X()+32, this.tf.Y(), this.tf.X()+64, this.tf.Y()), linalg.NewRgbaF64(1, 0, 0, 0))
@@ -91,7 +91,7 @@ func __TestProjectile_OnStep(obj base.IGameObject2D) {
 		sdk.Destroy(this)
 	}
 
-	val := collision.ColliderAtPolygonWithAny(this.csys, this.pc.Collider, collision.ActiveOnly)
+	val := collision.ColliderAtPolygonWithAny(this.csys, this.pc.Shape, collision.ActiveOnly)
 	if val != nil {
 
 		for tag := range val.I().Obj().Tags {
@@ -123,7 +123,7 @@ func __TestProjectile_OnStep(obj base.IGameObject2D) {
 
 func __TestProjectile_OnRender(obj base.IGameObject2D) {
 	this := obj.(*TestProjectile)
-	graphics.DrawRectangle(this.pc.Collider.GetBoundingBox().ToRectangle(), linalg.NewRgbaF64(1, 0, 0, 1))
+	graphics.DrawRectangle(this.pc.Shape.GetBoundingBox().ToRectangle(), linalg.NewRgbaF64(1, 0, 0, 1))
 }
 
 func __TestProjectile_OnDestroy(obj base.IGameObject2D) {
@@ -6,9 +6,9 @@ import (
 )
 
 type BasicComponentsBundle struct {
-	tf   *component.Transform2D     `gxen:"tf"`
-	rb   *component.RigidBody2D     `gxen:"rb"`
-	pc   *component.PolygonCollider `gxen:"pc"`
-	sr   *component.SpriteRenderer  `gxen:"sr"`
+	tf   *component.Transform2D    `gxen:"tf"`
+	rb   *component.RigidBody2D    `gxen:"rb"`
+	pc   *component.Collider       `gxen:"pc"`
+	sr   *component.SpriteRenderer `gxen:"sr"`
 	csys collision.ICollisionSystem
 }
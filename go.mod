@@ -1,8 +1,9 @@
 module galaxyzeta.io/engine
 
-go 1.14
+go 1.18
 
 require (
 	github.com/go-gl/gl v0.0.0-20210501111010-69f74958bac0
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20210410170116-ea3d685f79fb
+	golang.org/x/image v0.18.0
 )
@@ -0,0 +1,179 @@
+package graphics
+
+import (
+	"fmt"
+	"sort"
+)
+
+var stateMachineMap = map[string]*AnimStateMachine{}
+
+type AnimParamType uint8
+
+const (
+	AnimParam_Bool AnimParamType = iota
+	AnimParam_Float
+	AnimParam_Trigger // a bool which is reset once consumed by a transition.
+)
+
+type AnimConditionMode uint8
+
+const (
+	AnimCondition_If      AnimConditionMode = iota // bool is true, or trigger is set.
+	AnimCondition_IfNot                            // bool is false.
+	AnimCondition_Greater                          // float is greater than threshold.
+	AnimCondition_Less                             // float is less than threshold.
+)
+
+// AnimState plays a sprite meta at its own speed.
+type AnimState struct {
+	Name   string
+	Sprite string  // name of the sprite meta.
+	Speed  float64 // playback speed, 1 is normal.
+}
+
+// AnimCondition checks a parameter of an animator.
+type AnimCondition struct {
+	Param     string
+	Mode      AnimConditionMode
+	Threshold float64 // only used by Greater and Less.
+}
+
+// AnimTransition switches an animator from one state to another, once all conditions hold and the exit time is reached.
+// Transitions with higher priority are checked first, ties are broken by the order of declaration.
+type AnimTransition struct {
+	From        string // empty for transitions from any state.
+	To          string
+	Conditions  []AnimCondition
+	HasExitTime bool
+	ExitTime    float64 // normalized time of the source state, 1 means it has been played once.
+	Priority    int
+	ToSelf      bool // whether a transition from any state could restart the state it comes from.
+}
+
+type animParamDef struct {
+	kind     AnimParamType
+	defaults float64
+}
+
+// AnimStateMachine defines states, parameters and transitions shared by animators, it should not be modified once animators are created from it.
+type AnimStateMachine struct {
+	states      map[string]*AnimState
+	stateOrder  []string
+	entry       string
+	params      map[string]animParamDef
+	transitions []*AnimTransition
+}
+
+func NewAnimStateMachine() *AnimStateMachine {
+	return &AnimStateMachine{
+		states: map[string]*AnimState{},
+		params: map[string]animParamDef{},
+	}
+}
+
+// RegisterAnimStateMachine registers a state machine with a name, which could be used by level files.
+func RegisterAnimStateMachine(name string, machine *AnimStateMachine) {
+	stateMachineMap[name] = machine
+}
+
+// GetAnimStateMachine gets a state machine. Will panic if it is not found.
+func GetAnimStateMachine(name string) *AnimStateMachine {
+	machine, ok := stateMachineMap[name]
+	if !ok {
+		panic(fmt.Sprintf("animation state machine %s not found", name))
+	}
+	return machine
+}
+
+// AddState adds a state playing a sprite meta, the first state added is the entry.
+func (m *AnimStateMachine) AddState(name string, sprite string, speed float64) *AnimStateMachine {
+	if _, ok := m.states[name]; ok {
+		panic(fmt.Sprintf("animation state %s already exists", name))
+	}
+	if speed < 0 {
+		panic("playback speed should not be negative")
+	}
+	m.states[name] = &AnimState{Name: name, Sprite: sprite, Speed: speed}
+	m.stateOrder = append(m.stateOrder, name)
+	if m.entry == "" {
+		m.entry = name
+	}
+	return m
+}
+
+// SetEntry sets the state animators start from.
+func (m *AnimStateMachine) SetEntry(name string) *AnimStateMachine {
+	m.mustGetState(name)
+	m.entry = name
+	return m
+}
+
+func (m *AnimStateMachine) AddBool(name string, defaults bool) *AnimStateMachine {
+	return m.addParam(name, AnimParam_Bool, boolToParam(defaults))
+}
+
+func (m *AnimStateMachine) AddFloat(name string, defaults float64) *AnimStateMachine {
+	return m.addParam(name, AnimParam_Float, defaults)
+}
+
+func (m *AnimStateMachine) AddTrigger(name string) *AnimStateMachine {
+	return m.addParam(name, AnimParam_Trigger, 0)
+}
+
+func (m *AnimStateMachine) addParam(name string, kind AnimParamType, defaults float64) *AnimStateMachine {
+	if _, ok := m.params[name]; ok {
+		panic(fmt.Sprintf("animation parameter %s already exists", name))
+	}
+	m.params[name] = animParamDef{kind: kind, defaults: defaults}
+	return m
+}
+
+// AddTransition adds a transition. States and parameters it refers to must be added before.
+// A transition without conditions must have an exit time, otherwise it would fire forever.
+func (m *AnimStateMachine) AddTransition(t AnimTransition) *AnimStateMachine {
+	if t.From != "" {
+		m.mustGetState(t.From)
+	}
+	m.mustGetState(t.To)
+	if len(t.Conditions) == 0 && !t.HasExitTime {
+		panic(fmt.Sprintf("transition from %s to %s has neither conditions nor exit time", t.From, t.To))
+	}
+	for _, cond := range t.Conditions {
+		def, ok := m.params[cond.Param]
+		if !ok {
+			panic(fmt.Sprintf("animation parameter %s not found", cond.Param))
+		}
+		valid := false
+		switch cond.Mode {
+		case AnimCondition_If:
+			valid = def.kind == AnimParam_Bool || def.kind == AnimParam_Trigger
+		case AnimCondition_IfNot:
+			valid = def.kind == AnimParam_Bool
+		case AnimCondition_Greater, AnimCondition_Less:
+			valid = def.kind == AnimParam_Float
+		}
+		if !valid {
+			panic(fmt.Sprintf("condition mode %d is not applicable to parameter %s", cond.Mode, cond.Param))
+		}
+	}
+	m.transitions = append(m.transitions, &t)
+	sort.SliceStable(m.transitions, func(i, j int) bool {
+		return m.transitions[i].Priority > m.transitions[j].Priority
+	})
+	return m
+}
+
+func (m *AnimStateMachine) mustGetState(name string) *AnimState {
+	state, ok := m.states[name]
+	if !ok {
+		panic(fmt.Sprintf("animation state %s not found", name))
+	}
+	return state
+}
+
+func boolToParam(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,165 @@
+package graphics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// AsepriteDefaultTag names the sprite holding all frames of an Aseprite export without tags.
+const AsepriteDefaultTag = "default"
+
+// asepriteFile is the JSON exported by Aseprite, frames could be exported either as an array or as a hash.
+type asepriteFile struct {
+	Frames json.RawMessage `json:"frames"`
+	Meta   struct {
+		Image     string        `json:"image"`
+		FrameTags []asepriteTag `json:"frameTags"`
+	} `json:"meta"`
+}
+
+type asepriteFrame struct {
+	Filename         string       `json:"filename"`
+	Frame            asepriteRect `json:"frame"`
+	Rotated          bool         `json:"rotated"`
+	Trimmed          bool         `json:"trimmed"`
+	SpriteSourceSize asepriteRect `json:"spriteSourceSize"`
+	SourceSize       asepriteRect `json:"sourceSize"`
+	Duration         int          `json:"duration"` // milliseconds.
+}
+
+type asepriteRect struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+// asepriteTag is a range of frames played in a direction, which could be forward, reverse, pingpong or pingpong_reverse.
+type asepriteTag struct {
+	Name      string `json:"name"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	Direction string `json:"direction"`
+}
+
+// LoadAseprite imports a sprite sheet exported by Aseprite along with its JSON.
+// Frames are packed into atlas pages and registered as framePrefix followed by their indices.
+// A sprite meta is registered for each tag as spritePrefix followed by the tag name, frames keep their own durations,
+// and the direction of the tag decides the play mode.
+// An export without tags registers all frames as a tag named AsepriteDefaultTag.
+// If an error occurs, will panic.
+func LoadAseprite(jsonPath string, framePrefix string, spritePrefix string) {
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		panic(err)
+	}
+	desc := asepriteFile{}
+	if err := json.Unmarshal(data, &desc); err != nil {
+		panic(err)
+	}
+	frames, err := decodeAsepriteFrames(desc.Frames)
+	if err != nil {
+		panic(fmt.Sprintf("failed to decode frames of %s: %v", jsonPath, err))
+	}
+	sheet, err := ReadPng(filepath.Join(filepath.Dir(jsonPath), desc.Meta.Image))
+	if err != nil {
+		panic(err)
+	}
+
+	names := make([]string, len(frames))
+	images := make([]image.Image, len(frames))
+	durations := make([]time.Duration, len(frames))
+	for idx, frame := range frames {
+		names[idx] = strconv.Itoa(idx)
+		images[idx] = frame.image(sheet)
+		durations[idx] = time.Duration(frame.Duration) * time.Millisecond
+	}
+	registerPackedFrames(names, images, func(name string) string {
+		return framePrefix + name
+	})
+
+	tags := desc.Meta.FrameTags
+	if len(tags) == 0 {
+		tags = []asepriteTag{{Name: AsepriteDefaultTag, From: 0, To: len(frames) - 1}}
+	}
+	for _, tag := range tags {
+		if tag.From < 0 || tag.To >= len(frames) || tag.From > tag.To {
+			panic(fmt.Sprintf("tag %s of %s has invalid frame range [%d, %d]", tag.Name, jsonPath, tag.From, tag.To))
+		}
+		opts, err := tag.options()
+		if err != nil {
+			panic(err)
+		}
+		frameNames := make([]string, 0, tag.To-tag.From+1)
+		opts.Durations = make([]time.Duration, 0, tag.To-tag.From+1)
+		for frameIdx := tag.From; frameIdx <= tag.To; frameIdx++ {
+			frameNames = append(frameNames, framePrefix+names[frameIdx])
+			opts.Durations = append(opts.Durations, durations[frameIdx])
+		}
+		NewSpriteMetaWithOptions(spritePrefix+tag.Name, frameNames, opts)
+	}
+}
+
+// decodeAsepriteFrames decodes frames in order, a hash is walked token by token since maps lose the order.
+func decodeAsepriteFrames(raw json.RawMessage) (ret []asepriteFrame, err error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '[' {
+		err = json.Unmarshal(raw, &ret)
+		return ret, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("frames should be an array or an object")
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		frame := asepriteFrame{}
+		if err := decoder.Decode(&frame); err != nil {
+			return nil, err
+		}
+		frame.Filename = token.(string)
+		ret = append(ret, frame)
+	}
+	return ret, nil
+}
+
+// image returns pixels of the frame in its source size, trimmed transparent pixels are restored.
+func (frame asepriteFrame) image(sheet image.Image) image.Image {
+	if frame.Rotated {
+		panic(fmt.Sprintf("rotated frame %s is not supported", frame.Filename))
+	}
+	min := sheet.Bounds().Min
+	rect := image.Rect(frame.Frame.X, frame.Frame.Y, frame.Frame.X+frame.Frame.W, frame.Frame.Y+frame.Frame.H).Add(min)
+	if !frame.Trimmed {
+		return subImage(sheet, rect)
+	}
+	ret := image.NewRGBA(image.Rect(0, 0, frame.SourceSize.W, frame.SourceSize.H))
+	offset := image.Pt(frame.SpriteSourceSize.X, frame.SpriteSourceSize.Y)
+	draw.Draw(ret, image.Rect(0, 0, frame.Frame.W, frame.Frame.H).Add(offset), sheet, rect.Min, draw.Src)
+	return ret
+}
+
+// options converts the direction of the tag into a play mode.
+func (tag asepriteTag) options() (SpriteMetaOptions, error) {
+	switch tag.Direction {
+	case "", "forward":
+		return SpriteMetaOptions{Mode: PlayMode_Loop}, nil
+	case "reverse":
+		return SpriteMetaOptions{Mode: PlayMode_Loop, Reverse: true}, nil
+	case "pingpong":
+		return SpriteMetaOptions{Mode: PlayMode_PingPong}, nil
+	case "pingpong_reverse":
+		return SpriteMetaOptions{Mode: PlayMode_PingPong, Reverse: true}, nil
+	}
+	return SpriteMetaOptions{}, fmt.Errorf("unknown direction %s of tag %s", tag.Direction, tag.Name)
+}
@@ -0,0 +1,312 @@
+package graphics
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"galaxyzeta.io/engine/linalg"
+)
+
+const (
+	DefaultAtlasPageSize = 2048
+	DefaultAtlasPadding  = 2
+	DefaultAtlasExtrude  = 1
+)
+
+// AtlasPage is a texture shared by frames packed into it.
+type AtlasPage struct {
+	img       image.Image
+	glTexture uint32
+}
+
+// GetImg returns pixels of the whole page.
+func (page *AtlasPage) GetImg() image.Image {
+	return page.img
+}
+
+// newAtlasPage uploads the page into a GL texture.
+func newAtlasPage(img image.Image) *AtlasPage {
+	ret := &AtlasPage{img: img}
+	GLRegisterTexture(img, &ret.glTexture)
+	return ret
+}
+
+// AtlasPacker packs images into atlas pages.
+// Each image is surrounded by Extrude pixels copied from its edges, which prevents neighbours from bleeding in when sampling,
+// and then Padding transparent pixels away from others.
+type AtlasPacker struct {
+	PageSize int // max width and height of a page.
+	Padding  int
+	Extrude  int
+}
+
+// NewAtlasPacker returns a packer with default page size, padding and extrusion.
+func NewAtlasPacker() AtlasPacker {
+	return AtlasPacker{
+		PageSize: DefaultAtlasPageSize,
+		Padding:  DefaultAtlasPadding,
+		Extrude:  DefaultAtlasExtrude,
+	}
+}
+
+// AtlasRegion is where an image is placed on a page, in pixels and without extrusion.
+type AtlasRegion struct {
+	Name string `json:"name"`
+	X    int    `json:"x"`
+	Y    int    `json:"y"`
+	W    int    `json:"w"`
+	H    int    `json:"h"`
+
+	index int // index of the packed image.
+}
+
+// PackedPage is an atlas page packed on CPU, not uploaded yet.
+type PackedPage struct {
+	Image   *image.RGBA
+	Regions []AtlasRegion
+}
+
+// Pack places images onto as few pages as possible with shelf packing, taller images go first.
+// Names and images must have same length. Fails if an image could not fit in an empty page.
+func (p AtlasPacker) Pack(names []string, images []image.Image) ([]*PackedPage, error) {
+	if len(names) != len(images) {
+		panic("names and images should have same length")
+	}
+	order := make([]int, len(images))
+	for idx := range order {
+		order[idx] = idx
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return images[order[i]].Bounds().Dy() > images[order[j]].Bounds().Dy()
+	})
+
+	border := p.Extrude*2 + p.Padding
+	var pages []*PackedPage
+	var shelves [][]shelf // shelves of each page.
+	for _, idx := range order {
+		bounds := images[idx].Bounds()
+		w, h := bounds.Dx()+border, bounds.Dy()+border
+		if w > p.PageSize+p.Padding || h > p.PageSize+p.Padding {
+			return nil, fmt.Errorf("image %s of %dx%d could not fit in an atlas page of %d", names[idx], bounds.Dx(), bounds.Dy(), p.PageSize)
+		}
+		pageIdx := -1
+		var x, y int
+		for i := range pages {
+			var ok bool
+			if x, y, ok = p.place(&shelves[i], w, h); ok {
+				pageIdx = i
+				break
+			}
+		}
+		if pageIdx < 0 {
+			pages = append(pages, &PackedPage{})
+			shelves = append(shelves, nil)
+			pageIdx = len(pages) - 1
+			x, y, _ = p.place(&shelves[pageIdx], w, h)
+		}
+		pages[pageIdx].Regions = append(pages[pageIdx].Regions, AtlasRegion{
+			Name:  names[idx],
+			X:     x + p.Extrude,
+			Y:     y + p.Extrude,
+			W:     bounds.Dx(),
+			H:     bounds.Dy(),
+			index: idx,
+		})
+	}
+
+	// pages are cropped to the smallest power of 2 holding all regions, and then drawn.
+	// Regions never exceed PageSize, so is the page even if PageSize is not a power of 2.
+	for _, page := range pages {
+		width, height := 1, 1
+		for _, region := range page.Regions {
+			for width < region.X+region.W+p.Extrude {
+				width <<= 1
+			}
+			for height < region.Y+region.H+p.Extrude {
+				height <<= 1
+			}
+		}
+		if width > p.PageSize {
+			width = p.PageSize
+		}
+		if height > p.PageSize {
+			height = p.PageSize
+		}
+		page.Image = image.NewRGBA(image.Rect(0, 0, width, height))
+		for _, region := range page.Regions {
+			p.drawRegion(page.Image, images[region.index], region)
+		}
+	}
+	return pages, nil
+}
+
+// shelf is a row of a page, images are placed from left to right.
+type shelf struct {
+	y      int
+	height int
+	width  int // used width.
+}
+
+// place finds room for a w*h cell on the shelves of a page, a new shelf is opened below the last one if needed.
+// Cells are allowed to exceed the page by padding on right and bottom edges, which are never sampled.
+func (p AtlasPacker) place(shelves *[]shelf, w int, h int) (x int, y int, ok bool) {
+	limit := p.PageSize + p.Padding
+	for idx := range *shelves {
+		s := &(*shelves)[idx]
+		if h <= s.height && s.width+w <= limit {
+			x, y = s.width, s.y
+			s.width += w
+			return x, y, true
+		}
+	}
+	top := 0
+	if n := len(*shelves); n > 0 {
+		last := (*shelves)[n-1]
+		top = last.y + last.height
+	}
+	if top+h > limit {
+		return 0, 0, false
+	}
+	*shelves = append(*shelves, shelf{y: top, height: h, width: w})
+	return 0, top, true
+}
+
+// drawRegion copies the image onto the page, and extrudes its edges.
+func (p AtlasPacker) drawRegion(dst *image.RGBA, src image.Image, region AtlasRegion) {
+	bounds := src.Bounds()
+	draw.Draw(dst, image.Rect(region.X, region.Y, region.X+region.W, region.Y+region.H), src, bounds.Min, draw.Src)
+	if p.Extrude <= 0 {
+		return
+	}
+	clamp := func(v int, min int, max int) int {
+		if v < min {
+			return min
+		}
+		if v >= max {
+			return max - 1
+		}
+		return v
+	}
+	for y := region.Y - p.Extrude; y < region.Y+region.H+p.Extrude; y++ {
+		for x := region.X - p.Extrude; x < region.X+region.W+p.Extrude; x++ {
+			if x >= region.X && x < region.X+region.W && y >= region.Y && y < region.Y+region.H {
+				continue
+			}
+			sx := clamp(x-region.X, 0, region.W) + bounds.Min.X
+			sy := clamp(y-region.Y, 0, region.H) + bounds.Min.Y
+			dst.Set(x, y, src.At(sx, sy))
+		}
+	}
+}
+
+// +------------------------+
+// |	  	Atlas File	 	|
+// +------------------------+
+
+// AtlasFile is the JSON description of atlas pages written by gxatlas, page images are relative to the JSON file.
+type AtlasFile struct {
+	Pages []AtlasFilePage `json:"pages"`
+}
+
+type AtlasFilePage struct {
+	Image  string        `json:"image"`
+	Width  int           `json:"width"`
+	Height int           `json:"height"`
+	Frames []AtlasRegion `json:"frames"`
+}
+
+// WriteAtlas writes packed pages as <name>_<index>.png, and their description as <name>.json under dir.
+func WriteAtlas(dir string, name string, pages []*PackedPage) error {
+	desc := AtlasFile{}
+	for idx, page := range pages {
+		imgName := fmt.Sprintf("%s_%d.png", name, idx)
+		fp, err := os.Create(filepath.Join(dir, imgName))
+		if err != nil {
+			return err
+		}
+		err = png.Encode(fp, page.Image)
+		fp.Close()
+		if err != nil {
+			return err
+		}
+		desc.Pages = append(desc.Pages, AtlasFilePage{
+			Image:  imgName,
+			Width:  page.Image.Bounds().Dx(),
+			Height: page.Image.Bounds().Dy(),
+			Frames: page.Regions,
+		})
+	}
+	data, err := json.MarshalIndent(desc, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, name+".json"), data, 0644)
+}
+
+// LoadAtlas reads an atlas written by gxatlas, and registers its frames to frameMap with a naming strategy.
+// If an error occurs, will panic.
+func LoadAtlas(jsonPath string, namingFunc func(string) string) {
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		panic(err)
+	}
+	desc := AtlasFile{}
+	if err := json.Unmarshal(data, &desc); err != nil {
+		panic(err)
+	}
+	for _, pageDesc := range desc.Pages {
+		img, err := ReadPng(filepath.Join(filepath.Dir(jsonPath), pageDesc.Image))
+		if err != nil {
+			panic(err)
+		}
+		page := newAtlasPage(img)
+		for _, region := range pageDesc.Frames {
+			frameMap[namingFunc(region.Name)] = newAtlasFrame(page, region)
+		}
+	}
+}
+
+// registerPackedFrames packs images into atlas pages, and registers them to frameMap with a naming strategy.
+// If an error occurs, will panic.
+func registerPackedFrames(names []string, images []image.Image, namingFunc func(string) string) {
+	pages, err := NewAtlasPacker().Pack(names, images)
+	if err != nil {
+		panic(err)
+	}
+	for _, packed := range pages {
+		page := newAtlasPage(packed.Image)
+		for _, region := range packed.Regions {
+			frameMap[namingFunc(region.Name)] = newAtlasFrame(page, region)
+		}
+	}
+}
+
+// newAtlasFrame creates a frame referring to a region of the page.
+func newAtlasFrame(page *AtlasPage, region AtlasRegion) *GLFrame {
+	bounds := page.img.Bounds()
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+	rect := image.Rect(region.X, region.Y, region.X+region.W, region.Y+region.H).Add(bounds.Min)
+	return &GLFrame{
+		img:  subImage(page.img, rect),
+		page: page,
+		uv:   linalg.NewRect(float64(region.X)/w, float64(region.Y)/h, float64(region.W)/w, float64(region.H)/h),
+	}
+}
+
+// subImage returns pixels of img inside rect, it shares pixels with img if possible.
+func subImage(img image.Image, rect image.Rectangle) image.Image {
+	if sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(rect)
+	}
+	ret := image.NewRGBA(rect)
+	draw.Draw(ret, rect, img, rect.Min, draw.Src)
+	return ret
+}
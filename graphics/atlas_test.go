@@ -0,0 +1,91 @@
+package graphics
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"galaxyzeta.io/engine/infra/require"
+)
+
+// newTestImage creates a w*h image whose pixel at (x, y) has red x and green y, so that every pixel is distinct.
+func newTestImage(w int, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 255, A: 255})
+		}
+	}
+	return img
+}
+
+func packTestImages(p AtlasPacker, w int, h int, count int) ([]*PackedPage, error) {
+	names := make([]string, count)
+	images := make([]image.Image, count)
+	for i := range images {
+		names[i] = string(rune('a' + i))
+		images[i] = newTestImage(w, h)
+	}
+	return p.Pack(names, images)
+}
+
+func TestAtlasShelfOverflow(t *testing.T) {
+	// each 28*28 image takes a 32*32 cell, so a page of 64 holds 2 shelves of 2 cells.
+	p := AtlasPacker{PageSize: 64, Padding: 2, Extrude: 1}
+	pages, err := packTestImages(p, 28, 28, 5)
+	require.EqBool(true, err == nil)
+	require.EqInt(2, len(pages))
+	require.EqInt(4, len(pages[0].Regions))
+	require.EqInt(1, len(pages[1].Regions))
+	// regions are offset by extrusion, and cells are apart by padding.
+	offsets := [][2]int{{1, 1}, {33, 1}, {1, 33}, {33, 33}}
+	for idx, region := range pages[0].Regions {
+		t.Log(region)
+		require.EqInt(offsets[idx][0], region.X)
+		require.EqInt(offsets[idx][1], region.Y)
+		require.EqInt(28, region.W)
+		require.EqInt(28, region.H)
+	}
+}
+
+func TestAtlasDrawRegion(t *testing.T) {
+	p := AtlasPacker{PageSize: 64, Padding: 2, Extrude: 1}
+	pages, err := packTestImages(p, 28, 28, 2)
+	require.EqBool(true, err == nil)
+	img := pages[0].Image
+	at := func(x int, y int) color.RGBA {
+		return img.RGBAAt(x, y)
+	}
+	// pixels are copied into the region.
+	require.EqBool(true, at(1, 1) == color.RGBA{R: 0, G: 0, B: 255, A: 255})
+	require.EqBool(true, at(28, 28) == color.RGBA{R: 27, G: 27, B: 255, A: 255})
+	// edges are extruded, corners take the corner pixels.
+	require.EqBool(true, at(0, 0) == at(1, 1))
+	require.EqBool(true, at(0, 10) == at(1, 10))
+	require.EqBool(true, at(10, 29) == at(10, 28))
+	require.EqBool(true, at(29, 29) == at(28, 28))
+	// padding between cells is left transparent.
+	require.EqBool(true, at(30, 10).A == 0 && at(31, 10).A == 0)
+	require.EqBool(true, at(32, 10) == at(33, 10))
+}
+
+func TestAtlasCrop(t *testing.T) {
+	// pages are cropped to the smallest power of 2.
+	pages, err := packTestImages(NewAtlasPacker(), 10, 20, 1)
+	require.EqBool(true, err == nil)
+	bounds := pages[0].Image.Bounds()
+	require.EqInt(16, bounds.Dx())
+	require.EqInt(32, bounds.Dy())
+	// but never exceed a page size which is not a power of 2.
+	pages, err = packTestImages(AtlasPacker{PageSize: 48, Padding: 2, Extrude: 1}, 46, 46, 1)
+	require.EqBool(true, err == nil)
+	bounds = pages[0].Image.Bounds()
+	require.EqInt(48, bounds.Dx())
+	require.EqInt(48, bounds.Dy())
+}
+
+func TestAtlasOversize(t *testing.T) {
+	_, err := packTestImages(AtlasPacker{PageSize: 48, Padding: 2, Extrude: 1}, 47, 10, 1)
+	t.Log(err)
+	require.EqBool(true, err != nil)
+}
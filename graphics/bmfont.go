@@ -0,0 +1,127 @@
+package graphics
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"galaxyzeta.io/engine/linalg"
+)
+
+// LoadBMFont reads a font in text format of AngelCode BMFont, whose pages are pngs next to the .fnt file.
+// If an error occurs, will panic.
+func LoadBMFont(fntPath string) *Font {
+	fp, err := os.Open(fntPath)
+	if err != nil {
+		panic(err)
+	}
+	defer fp.Close()
+
+	var ret *Font
+	pages := map[int]*AtlasPage{}
+	var chars []map[string]string // chars refer to pages, which may be declared after them.
+	scanner := bufio.NewScanner(fp)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		tag, attrs, err := parseBMFontLine(scanner.Text())
+		if err != nil {
+			panic(fmt.Sprintf("%s:%d: %s", fntPath, lineNo, err))
+		}
+		switch tag {
+		case "common":
+			ret = newFont(bmFontFloat(attrs, "lineHeight"), bmFontFloat(attrs, "base"))
+		case "page":
+			img, err := ReadPng(filepath.Join(filepath.Dir(fntPath), attrs["file"]))
+			if err != nil {
+				panic(err)
+			}
+			pages[int(bmFontFloat(attrs, "id"))] = newAtlasPage(img)
+		case "char":
+			chars = append(chars, attrs)
+		case "kerning":
+			if ret == nil {
+				panic(fmt.Sprintf("%s:%d: kerning is declared before common", fntPath, lineNo))
+			}
+			pair := [2]rune{rune(bmFontFloat(attrs, "first")), rune(bmFontFloat(attrs, "second"))}
+			ret.kernings[pair] = bmFontFloat(attrs, "amount")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		panic(err)
+	}
+	if ret == nil {
+		panic(fmt.Sprintf("%s is not a BMFont file in text format", fntPath))
+	}
+	for _, attrs := range chars {
+		g := &Glyph{
+			W:        bmFontFloat(attrs, "width"),
+			H:        bmFontFloat(attrs, "height"),
+			XOffset:  bmFontFloat(attrs, "xoffset"),
+			YOffset:  bmFontFloat(attrs, "yoffset"),
+			XAdvance: bmFontFloat(attrs, "xadvance"),
+		}
+		if g.W > 0 && g.H > 0 {
+			page, ok := pages[int(bmFontFloat(attrs, "page"))]
+			if !ok {
+				panic(fmt.Sprintf("%s: page %s of char %s is not declared", fntPath, attrs["page"], attrs["id"]))
+			}
+			bounds := page.img.Bounds()
+			pw, ph := float64(bounds.Dx()), float64(bounds.Dy())
+			g.page = page
+			g.uv = linalg.NewRect(bmFontFloat(attrs, "x")/pw, bmFontFloat(attrs, "y")/ph, g.W/pw, g.H/ph)
+		}
+		ret.glyphs[rune(bmFontFloat(attrs, "id"))] = g
+	}
+	return ret
+}
+
+// parseBMFontLine splits a line into its tag and key=value attributes, values could be quoted.
+func parseBMFontLine(line string) (tag string, attrs map[string]string, err error) {
+	attrs = map[string]string{}
+	line = strings.TrimSpace(line)
+	if idx := strings.IndexAny(line, " \t"); idx >= 0 {
+		tag, line = line[:idx], line[idx:]
+	} else {
+		return line, attrs, nil
+	}
+	for {
+		line = strings.TrimLeft(line, " \t")
+		if line == "" {
+			return tag, attrs, nil
+		}
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 {
+			return "", nil, fmt.Errorf("attribute %q has no value", line)
+		}
+		key := line[:eq]
+		line = line[eq+1:]
+		var value string
+		if strings.HasPrefix(line, "\"") {
+			end := strings.IndexByte(line[1:], '"')
+			if end < 0 {
+				return "", nil, fmt.Errorf("value of %s is not closed", key)
+			}
+			value, line = line[1:end+1], line[end+2:]
+		} else if end := strings.IndexAny(line, " \t"); end >= 0 {
+			value, line = line[:end], line[end:]
+		} else {
+			value, line = line, ""
+		}
+		attrs[key] = value
+	}
+}
+
+// bmFontFloat returns a numeric attribute, 0 if it is absent. Will panic if it is not a number.
+func bmFontFloat(attrs map[string]string, key string) float64 {
+	value, ok := attrs[key]
+	if !ok {
+		return 0
+	}
+	ret, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		panic(fmt.Sprintf("attribute %s=%s is not a number", key, value))
+	}
+	return ret
+}
@@ -1,24 +1,53 @@
 package graphics
 
 import (
+	"math"
 	"sync"
 
 	"galaxyzeta.io/engine/linalg"
 	"galaxyzeta.io/engine/physics"
 )
 
+// Camera views a part of the world. pos is the top-left corner of the view before zoom and rotation,
+// which are applied around the center of the view. The view is fitted into the viewport rect of the window by the scale mode.
+// Every enabled camera draws the scene once per frame in order, so that cameras could split the screen or show a minimap.
 type Camera struct {
-	pos        linalg.Vector2f64
-	resolution linalg.Vector2f64
-	rwmutex    *sync.RWMutex
+	pos           linalg.Vector2f64
+	resolution    linalg.Vector2f64 // virtual resolution, the size of the view in world units at zoom 1.
+	zoom          float64
+	rotation      float64     // degrees, clockwise on screen.
+	viewportRect  linalg.Rect // normalized area of the window, (0, 0) is the top-left corner and (1, 1) is the bottom-right one.
+	order         int         // cameras of lower order draw first, so that cameras of higher order are drawn over them.
+	layerMask     uint32      // layers drawn by the camera.
+	enabled       bool
+	rwmutex       *sync.RWMutex
+	postProcessor *PostProcessor
+	controller    *CameraController
 }
 
 func NewCamera(pos linalg.Vector2f64, resolution linalg.Vector2f64) *Camera {
-	return &Camera{
-		pos:        pos,
-		resolution: resolution,
-		rwmutex:    &sync.RWMutex{},
+	c := &Camera{
+		pos:           pos,
+		resolution:    resolution,
+		zoom:          1,
+		viewportRect:  linalg.NewRect(0, 0, 1, 1),
+		layerMask:     LayerMaskAll,
+		enabled:       true,
+		rwmutex:       &sync.RWMutex{},
+		postProcessor: NewPostProcessor(),
 	}
+	c.controller = NewCameraController(c)
+	return c
+}
+
+// PostProcessor returns the chain of effects applied to what the camera views.
+func (c *Camera) PostProcessor() *PostProcessor {
+	return c.postProcessor
+}
+
+// Controller returns the controller moving the camera, such as following a target.
+func (c *Camera) Controller() *CameraController {
+	return c.controller
 }
 
 func (c *Camera) GetPos() (pos linalg.Vector2f64) {
@@ -28,13 +57,31 @@ func (c *Camera) GetPos() (pos linalg.Vector2f64) {
 	return
 }
 
-func (c *Camera) GetResolution() (res linalg.Vector2f64) {
+// GetResolution returns the size of the view at zoom 1, which is the virtual resolution, or larger in expand mode.
+func (c *Camera) GetResolution() linalg.Vector2f64 {
+	_, res := c.viewport()
+	return res
+}
+
+// GetVirtualResolution returns the design resolution of the camera.
+func (c *Camera) GetVirtualResolution() (res linalg.Vector2f64) {
 	c.rwmutex.RLock()
 	res = c.resolution
 	c.rwmutex.RUnlock()
 	return
 }
 
+// SetVirtualResolution sets the design resolution of the camera, which is fitted into the window by the scale mode.
+func (c *Camera) SetVirtualResolution(x float64, y float64) {
+	if x <= 0 || y <= 0 {
+		panic("virtual resolution must be positive")
+	}
+	c.rwmutex.Lock()
+	c.resolution.X = x
+	c.resolution.Y = y
+	c.rwmutex.Unlock()
+}
+
 func (c *Camera) SetPos(x float64, y float64) {
 	c.rwmutex.Lock()
 	c.pos.X = x
@@ -61,6 +108,196 @@ func (c *Camera) SetPosY(y float64) {
 	c.rwmutex.Unlock()
 }
 
+// GetCenter returns the center of the view in world space, around which the camera zooms and rotates.
+func (c *Camera) GetCenter() linalg.Vector2f64 {
+	return c.GetPos().Add(c.GetResolution().Scale(0.5))
+}
+
+func (c *Camera) GetZoom() (zoom float64) {
+	c.rwmutex.RLock()
+	zoom = c.zoom
+	c.rwmutex.RUnlock()
+	return
+}
+
+// SetZoom sets the zoom, things look larger with zoom greater than 1.
+func (c *Camera) SetZoom(zoom float64) {
+	if zoom <= 0 {
+		panic("zoom must be positive")
+	}
+	c.rwmutex.Lock()
+	c.zoom = zoom
+	c.rwmutex.Unlock()
+}
+
+func (c *Camera) GetRotation() (deg float64) {
+	c.rwmutex.RLock()
+	deg = c.rotation
+	c.rwmutex.RUnlock()
+	return
+}
+
+// SetRotation sets the rotation of the view in degrees, the world looks rotated counterclockwise as the camera rotates clockwise.
+func (c *Camera) SetRotation(deg float64) {
+	c.rwmutex.Lock()
+	c.rotation = deg
+	c.rwmutex.Unlock()
+}
+
+func (c *Camera) Rotate(deg float64) {
+	c.rwmutex.Lock()
+	c.rotation += deg
+	c.rwmutex.Unlock()
+}
+
+func (c *Camera) IsEnabled() (enabled bool) {
+	c.rwmutex.RLock()
+	enabled = c.enabled
+	c.rwmutex.RUnlock()
+	return
+}
+
+// SetEnabled turns drawing of the camera on or off, a disabled camera could still be moved by its controller.
+func (c *Camera) SetEnabled(enabled bool) {
+	c.rwmutex.Lock()
+	c.enabled = enabled
+	c.rwmutex.Unlock()
+}
+
+func (c *Camera) GetOrder() (order int) {
+	c.rwmutex.RLock()
+	order = c.order
+	c.rwmutex.RUnlock()
+	return
+}
+
+// SetOrder sets the render order, cameras of higher order are drawn over cameras of lower order.
+func (c *Camera) SetOrder(order int) {
+	c.rwmutex.Lock()
+	c.order = order
+	c.rwmutex.Unlock()
+}
+
+func (c *Camera) GetLayerMask() (mask uint32) {
+	c.rwmutex.RLock()
+	mask = c.layerMask
+	c.rwmutex.RUnlock()
+	return
+}
+
+// SetLayerMask sets layers drawn by the camera, see LayerMask.
+func (c *Camera) SetLayerMask(mask uint32) {
+	c.rwmutex.Lock()
+	c.layerMask = mask
+	c.rwmutex.Unlock()
+}
+
+// DrawsLayer tells whether the camera draws renderables on the layer.
+func (c *Camera) DrawsLayer(layer int) bool {
+	return c.GetLayerMask()&(1<<uint(layer)) != 0
+}
+
+// GetViewportRect returns the normalized area of the window the camera draws into.
+func (c *Camera) GetViewportRect() (rect linalg.Rect) {
+	c.rwmutex.RLock()
+	rect = c.viewportRect
+	c.rwmutex.RUnlock()
+	return
+}
+
+// SetViewportRect sets the normalized area of the window the camera draws into, such as (0, 0, 0.5, 1) for the left half.
+// The virtual resolution is fitted into the area by the scale mode. Will panic if the area is empty.
+func (c *Camera) SetViewportRect(rect linalg.Rect) {
+	if rect.W <= 0 || rect.H <= 0 {
+		panic("viewport rect must not be empty")
+	}
+	c.rwmutex.Lock()
+	c.viewportRect = rect
+	c.rwmutex.Unlock()
+}
+
+// ViewMat3 returns the matrix converting world space into OpenGL space of the viewport.
+func (c *Camera) ViewMat3() linalg.Mat3 {
+	res := c.GetResolution()
+	cam2OpenGL := linalg.Mat3{
+		2 / res.X, 0, -1,
+		0, -2 / res.Y, 1,
+		0, 0, 1,
+	}
+	return cam2OpenGL.Mul(c.world2Cam(res))
+}
+
+// world2Cam returns the matrix converting world space into camera space, whose origin is the top-left corner of the viewport
+// and whose unit is a pixel of virtual resolution.
+func (c *Camera) world2Cam(res linalg.Vector2f64) linalg.Mat3 {
+	c.rwmutex.RLock()
+	pos, zoom, rotation := c.pos, c.zoom, c.rotation
+	c.rwmutex.RUnlock()
+	half := res.Scale(0.5)
+	return linalg.NewTranslateMat3(half).
+		Mul(linalg.NewScaleMat3(linalg.NewVector2f64(zoom, zoom))).
+		Mul(linalg.NewRotateMat3(-rotation)).
+		Mul(linalg.NewTranslateMat3(pos.Add(half).Scale(-1)))
+}
+
+// Viewport returns the area of the window the camera draws into, in screen coordinates with the origin at the top-left corner.
+func (c *Camera) Viewport() linalg.Rect {
+	vp, _ := c.viewport()
+	fb, screen := GetFramebufferSize(), GetScreenResolution()
+	sx, sy := screen.X/fb.X, screen.Y/fb.Y
+	return linalg.NewRect(vp.X*sx, vp.Y*sy, vp.W*sx, vp.H*sy)
+}
+
+// viewport returns the viewport in pixels of the framebuffer, and the resolution viewed.
+func (c *Camera) viewport() (linalg.Rect, linalg.Vector2f64) {
+	fb := GetFramebufferSize()
+	rect := c.GetViewportRect()
+	area := linalg.NewRect(math.Round(rect.X*fb.X), math.Round(rect.Y*fb.Y), math.Round(rect.W*fb.X), math.Round(rect.H*fb.Y))
+	return fitViewport(area, c.GetVirtualResolution(), GetScaleMode())
+}
+
+// glViewport returns the viewport in OpenGL convention, whose origin is the bottom-left corner of the framebuffer.
+func (c *Camera) glViewport() (x int32, y int32, w int32, h int32) {
+	vp, _ := c.viewport()
+	fb := GetFramebufferSize()
+	return int32(vp.X), int32(fb.Y - vp.Y - vp.H), int32(vp.W), int32(vp.H)
+}
+
+// WorldToScreen converts a point in world space into screen coordinates of the window, such as the cursor position.
+func (c *Camera) WorldToScreen(p linalg.Vector2f64) linalg.Vector2f64 {
+	vp := c.Viewport()
+	ndc := c.ViewMat3().MulPoint(p)
+	return linalg.NewVector2f64(vp.X+(ndc.X+1)/2*vp.W, vp.Y+(1-ndc.Y)/2*vp.H)
+}
+
+// ScreenToWorld converts a point in screen coordinates of the window, such as the cursor position, into world space.
+func (c *Camera) ScreenToWorld(p linalg.Vector2f64) linalg.Vector2f64 {
+	vp := c.Viewport()
+	ndc := linalg.NewVector2f64((p.X-vp.X)/vp.W*2-1, 1-(p.Y-vp.Y)/vp.H*2)
+	inv, ok := c.ViewMat3().Inverse()
+	if !ok {
+		return c.GetCenter()
+	}
+	return inv.MulPoint(ndc)
+}
+
+// GetPolygon returns the area of the world in view, which is rotated if the camera is.
 func (c *Camera) GetPolygon() physics.Polygon {
-	return physics.NewRectangle(c.pos.X, c.pos.Y, c.resolution.X, c.resolution.Y).ToPolygon()
+	res := c.GetResolution()
+	cam2World, ok := c.world2Cam(res).Inverse()
+	if !ok {
+		cam2World = linalg.Identity3()
+	}
+	corners := []linalg.Vector2f64{
+		cam2World.MulPoint(linalg.NewVector2f64(0, 0)),
+		cam2World.MulPoint(linalg.NewVector2f64(res.X, 0)),
+		cam2World.MulPoint(linalg.NewVector2f64(res.X, res.Y)),
+		cam2World.MulPoint(linalg.NewVector2f64(0, res.Y)),
+	}
+	return *physics.NewStaticPolygon(linalg.Vector2f64{}, 0, corners)
+}
+
+// GetViewRect returns the smallest axis aligned rect containing the area of the world in view.
+func (c *Camera) GetViewRect() linalg.Rect {
+	return linalg.NewRectFromPoints(c.GetPolygon().GetWorldVertices()...)
 }
@@ -0,0 +1,294 @@
+package graphics
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"galaxyzeta.io/engine/linalg"
+)
+
+type CameraFollowMode uint8
+
+const (
+	CameraFollow_SmoothDamp CameraFollowMode = iota // springs towards the target in about SmoothTime, without overshooting.
+	CameraFollow_Lerp                               // closes a part of the distance every step, which is faster with a larger LerpSpeed.
+	CameraFollow_Instant                            // sticks to the target.
+)
+
+// CameraTick is the clock a camera controller is driven by.
+type CameraTick uint8
+
+const (
+	CameraTick_Physics CameraTick = iota // updated along with game logic, in fixed steps.
+	CameraTick_Render                    // updated once per frame before anything is drawn, which is smoother at high frame rates.
+)
+
+// CameraController moves a camera to follow a target, keeps the view inside bounds, and shakes the camera by trauma.
+// It does nothing while it has no target, no bounds and no trauma, so the camera could be moved by hand.
+// Positions are in world space, and the target is kept at the center of the view.
+type CameraController struct {
+	Tick       CameraTick
+	Mode       CameraFollowMode
+	SmoothTime float64           // seconds to reach the target in smooth damp mode.
+	LerpSpeed  float64           // in lerp mode, 1-exp(-LerpSpeed) of the distance is closed every second.
+	Offset     linalg.Vector2f64 // added to the target, such as looking a little above a character.
+	Deadzone   linalg.Vector2f64 // size of a rectangle at the center of the view, inside which the target moves without moving the camera.
+
+	LookAhead           float64           // the camera leads the target by its velocity over these seconds.
+	LookAheadMax        linalg.Vector2f64 // limits the lead on each axis, no limit if 0.
+	LookAheadSmoothTime float64           // seconds the lead takes to settle, so that it does not jitter when the target turns.
+
+	MaxShake       linalg.Vector2f64 // offset at full trauma in pixels of virtual resolution.
+	ShakeFrequency float64           // how fast the shake changes, in samples per second.
+	TraumaDecay    float64           // trauma lost per second.
+
+	mu                sync.Mutex
+	cam               *Camera
+	target            func() linalg.Vector2f64
+	bounds            linalg.Rect
+	hasBounds         bool
+	center            linalg.Vector2f64 // center of the view without shake.
+	velocity          linalg.Vector2f64 // velocity of smooth damp.
+	lastFocus         linalg.Vector2f64 // the target with offset at last update, to measure its velocity.
+	hasLastFocus      bool
+	lookAhead         linalg.Vector2f64
+	lookAheadVelocity linalg.Vector2f64
+	trauma            float64
+	shakeTime         float64
+	shake             linalg.Vector2f64 // shake applied at last update, which is excluded when reading the position of the camera.
+	transition        *cameraTransition
+}
+
+// cameraTransition moves the camera from a point to a new target in a duration, easing in and out.
+type cameraTransition struct {
+	from     linalg.Vector2f64
+	duration float64
+	elapsed  float64
+}
+
+// NewCameraController returns a controller of a camera updated on the physics tick, following targets by smooth damp.
+func NewCameraController(cam *Camera) *CameraController {
+	return &CameraController{
+		cam:                 cam,
+		Tick:                CameraTick_Physics,
+		Mode:                CameraFollow_SmoothDamp,
+		SmoothTime:          0.15,
+		LerpSpeed:           5,
+		LookAheadSmoothTime: 0.3,
+		MaxShake:            linalg.NewVector2f64(16, 16),
+		ShakeFrequency:      15,
+		TraumaDecay:         1,
+	}
+}
+
+// Follow makes the camera follow a target, which is usually the position of a transform.
+// The camera moves there by the follow mode, a nil target stops following.
+func (c *CameraController) Follow(target func() linalg.Vector2f64) {
+	c.mu.Lock()
+	c.setTarget(target)
+	c.transition = nil
+	c.mu.Unlock()
+}
+
+// TransitionTo moves the camera to a new target in duration, easing in and out, and then follows it.
+func (c *CameraController) TransitionTo(target func() linalg.Vector2f64, duration time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setTarget(target)
+	if target == nil || duration <= 0 {
+		c.transition = nil
+		return
+	}
+	c.transition = &cameraTransition{
+		from:     c.currentCenter(),
+		duration: duration.Seconds(),
+	}
+}
+
+// IsFollowing tells whether the camera has a target.
+func (c *CameraController) IsFollowing() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.target != nil
+}
+
+// IsTransitioning tells whether the camera is moving to a new target by TransitionTo.
+func (c *CameraController) IsTransitioning() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.transition != nil
+}
+
+// SnapToTarget centers the view on the target immediately, without smoothing and look-ahead.
+func (c *CameraController) SnapToTarget() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.target == nil {
+		return
+	}
+	c.transition = nil
+	c.velocity = linalg.Vector2f64{}
+	c.lookAhead, c.lookAheadVelocity = linalg.Vector2f64{}, linalg.Vector2f64{}
+	c.hasLastFocus = false
+	c.center = c.target().Add(c.Offset)
+	c.apply(0)
+}
+
+// SetBounds keeps the view inside bounds, such as the room of a scene. The view is centered on bounds smaller than it.
+func (c *CameraController) SetBounds(bounds linalg.Rect) {
+	c.mu.Lock()
+	c.bounds = bounds
+	c.hasBounds = true
+	c.mu.Unlock()
+}
+
+// ClearBounds lets the view go anywhere.
+func (c *CameraController) ClearBounds() {
+	c.mu.Lock()
+	c.hasBounds = false
+	c.mu.Unlock()
+}
+
+// AddTrauma adds to the trauma, which is kept in [0, 1]. The camera shakes by the square of trauma, and trauma decays over time.
+func (c *CameraController) AddTrauma(amount float64) {
+	c.mu.Lock()
+	c.trauma = linalg.Clamp(c.trauma+amount, 0, 1)
+	c.mu.Unlock()
+}
+
+func (c *CameraController) Trauma() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.trauma
+}
+
+// Update moves the camera by dt seconds, which is done by Camera2DSystem on the tick of the controller.
+func (c *CameraController) Update(dt float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if dt <= 0 || (c.target == nil && !c.hasBounds && c.trauma <= 0 && c.shake == linalg.Vector2f64{}) {
+		return
+	}
+	// the camera could have been moved by others since last update.
+	c.center = c.currentCenter()
+	if c.target != nil {
+		focus := c.focus(dt)
+		if c.transition != nil {
+			c.transition.elapsed += dt
+			t := linalg.Clamp(c.transition.elapsed/c.transition.duration, 0, 1)
+			c.center = linalg.LerpVector(c.transition.from, focus, t*t*(3-2*t))
+			if t >= 1 {
+				c.transition = nil
+				c.velocity = linalg.Vector2f64{}
+			}
+		} else {
+			goal := c.deadzoneGoal(focus)
+			switch c.Mode {
+			case CameraFollow_SmoothDamp:
+				c.center, c.velocity = linalg.SmoothDampVector(c.center, goal, c.velocity, c.SmoothTime, math.Inf(1), dt)
+			case CameraFollow_Lerp:
+				c.center = linalg.LerpVector(c.center, goal, 1-math.Exp(-c.LerpSpeed*dt))
+			default:
+				c.center = goal
+			}
+		}
+	}
+	c.apply(dt)
+}
+
+func (c *CameraController) setTarget(target func() linalg.Vector2f64) {
+	c.target = target
+	c.velocity = linalg.Vector2f64{}
+	c.hasLastFocus = false
+}
+
+// currentCenter returns the center of the view without shake.
+func (c *CameraController) currentCenter() linalg.Vector2f64 {
+	return c.cam.GetPos().Sub(c.shake).Add(c.cam.GetResolution().Scale(0.5))
+}
+
+// focus returns the point to center on, which is the target with offset and look-ahead.
+func (c *CameraController) focus(dt float64) linalg.Vector2f64 {
+	focus := c.target().Add(c.Offset)
+	if c.hasLastFocus {
+		lead := focus.Sub(c.lastFocus).Scale(c.LookAhead / dt)
+		if c.LookAheadMax.X > 0 {
+			lead.X = linalg.Clamp(lead.X, -c.LookAheadMax.X, c.LookAheadMax.X)
+		}
+		if c.LookAheadMax.Y > 0 {
+			lead.Y = linalg.Clamp(lead.Y, -c.LookAheadMax.Y, c.LookAheadMax.Y)
+		}
+		c.lookAhead, c.lookAheadVelocity = linalg.SmoothDampVector(c.lookAhead, lead, c.lookAheadVelocity, c.LookAheadSmoothTime, math.Inf(1), dt)
+	}
+	c.lastFocus = focus
+	c.hasLastFocus = true
+	return focus.Add(c.lookAhead)
+}
+
+// deadzoneGoal returns the center which moves the least to keep focus inside the deadzone.
+func (c *CameraController) deadzoneGoal(focus linalg.Vector2f64) linalg.Vector2f64 {
+	goal := c.center
+	half := c.Deadzone.Scale(0.5)
+	if focus.X < goal.X-half.X {
+		goal.X = focus.X + half.X
+	} else if focus.X > goal.X+half.X {
+		goal.X = focus.X - half.X
+	}
+	if focus.Y < goal.Y-half.Y {
+		goal.Y = focus.Y + half.Y
+	} else if focus.Y > goal.Y+half.Y {
+		goal.Y = focus.Y - half.Y
+	}
+	return goal
+}
+
+// apply clamps the center into bounds, shakes it, and moves the camera there.
+func (c *CameraController) apply(dt float64) {
+	half := c.cam.GetResolution().Scale(0.5)
+	zoom := c.cam.GetZoom()
+	if c.hasBounds {
+		// the view seen is smaller when zoomed in.
+		view := half.Scale(1 / zoom)
+		c.center.X = clampViewAxis(c.center.X, view.X, c.bounds.Left(), c.bounds.Right())
+		c.center.Y = clampViewAxis(c.center.Y, view.Y, c.bounds.Top(), c.bounds.Bottom())
+	}
+	c.shake = linalg.Vector2f64{}
+	if c.trauma > 0 {
+		c.shakeTime += dt
+		// shakes the same on screen whatever the zoom is.
+		amount := c.trauma * c.trauma / zoom
+		t := c.shakeTime * c.ShakeFrequency
+		c.shake = linalg.NewVector2f64(c.MaxShake.X*amount*shakeNoise(0, t), c.MaxShake.Y*amount*shakeNoise(1, t))
+		c.trauma = math.Max(0, c.trauma-c.TraumaDecay*dt)
+	}
+	pos := c.center.Sub(half).Add(c.shake)
+	c.cam.SetPos(pos.X, pos.Y)
+}
+
+// clampViewAxis keeps a view of half size around center inside [min, max], the view is centered if it is larger.
+func clampViewAxis(center float64, half float64, min float64, max float64) float64 {
+	if max-min <= half*2 {
+		return (min + max) / 2
+	}
+	return linalg.Clamp(center, min+half, max-half)
+}
+
+// shakeNoise is smooth value noise in [-1, 1], seeds give independent noises of axes.
+func shakeNoise(seed uint64, t float64) float64 {
+	i := math.Floor(t)
+	f := t - i
+	f = f * f * (3 - 2*f)
+	return linalg.Lerp(hashNoise(seed, int64(i)), hashNoise(seed, int64(i)+1), f)
+}
+
+// hashNoise returns a random value in [-1, 1] decided by seed and i.
+func hashNoise(seed uint64, i int64) float64 {
+	h := uint64(i)*0x9E3779B97F4A7C15 ^ (seed+1)*0xC2B2AE3D27D4EB4F
+	h ^= h >> 30
+	h *= 0xBF58476D1CE4E5B9
+	h ^= h >> 27
+	h *= 0x94D049BB133111EB
+	h ^= h >> 31
+	return float64(h>>11)/float64(1<<53)*2 - 1
+}
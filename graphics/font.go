@@ -0,0 +1,239 @@
+package graphics
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"galaxyzeta.io/engine/linalg"
+	"galaxyzeta.io/engine/physics"
+)
+
+var fontMap = map[string]*Font{}
+
+// fallbackRune is drawn in place of characters missing from a font.
+const fallbackRune = '?'
+
+type TextAlign uint8
+
+const (
+	TextAlign_Left TextAlign = iota
+	TextAlign_Center
+	TextAlign_Right
+)
+
+// Glyph is a character of a font, in pixels.
+type Glyph struct {
+	page     *AtlasPage // nil if the glyph has nothing to draw, such as a space.
+	uv       linalg.Rect
+	W        float64 // size of the glyph image.
+	H        float64
+	XOffset  float64 // from the pen to the left of the image.
+	YOffset  float64 // from the top of the line to the top of the image.
+	XAdvance float64 // how far the pen moves after the glyph.
+}
+
+// Font is a set of glyphs packed into atlas pages, which is loaded from BMFont or rasterized from TrueType.
+type Font struct {
+	LineHeight float64 // distance between two lines.
+	Base       float64 // distance from the top of a line to the baseline.
+	glyphs     map[rune]*Glyph
+	kernings   map[[2]rune]float64 // adjustments of advance between two characters.
+}
+
+// TextOptions describes how a text is laid out and colored.
+type TextOptions struct {
+	Color        linalg.RgbaF64
+	Align        TextAlign // aligns lines inside the text box.
+	Width        float64   // width of the text box, lines are wrapped at spaces to fit in. No wrapping if 0.
+	Outline      float64   // thickness of the outline in pixels, no outline if 0.
+	OutlineColor linalg.RgbaF64
+}
+
+// placedGlyph is a glyph placed by layout, with the top-left corner of the text box as origin.
+type placedGlyph struct {
+	glyph *Glyph
+	x     float64
+	y     float64
+}
+
+// NewTextOptions returns options drawing white, left aligned text without wrapping and outline.
+func NewTextOptions() TextOptions {
+	return TextOptions{
+		Color:        White,
+		OutlineColor: linalg.NewRgbaF64(0, 0, 0, 1),
+	}
+}
+
+func newFont(lineHeight float64, base float64) *Font {
+	return &Font{
+		LineHeight: lineHeight,
+		Base:       base,
+		glyphs:     map[rune]*Glyph{},
+		kernings:   map[[2]rune]float64{},
+	}
+}
+
+// RegisterFont registers a font with a name, which could be used by level files.
+func RegisterFont(name string, font *Font) {
+	fontMap[name] = font
+}
+
+// GetFont gets a font. Will panic if it is not found.
+func GetFont(name string) *Font {
+	font, ok := fontMap[name]
+	if !ok {
+		panic(fmt.Sprintf("font %s not found", name))
+	}
+	return font
+}
+
+// GetGlyph returns the glyph of a character, or nil if the font does not have it.
+func (f *Font) GetGlyph(r rune) *Glyph {
+	return f.glyphs[r]
+}
+
+// Kerning returns the adjustment of advance when b follows a.
+func (f *Font) Kerning(a rune, b rune) float64 {
+	return f.kernings[[2]rune{a, b}]
+}
+
+// glyph returns the glyph drawn for a character, missing characters are replaced by the fallback one.
+func (f *Font) glyph(r rune) *Glyph {
+	if g, ok := f.glyphs[r]; ok {
+		return g
+	}
+	return f.glyphs[fallbackRune]
+}
+
+// measureLine returns the advance of a line, kerning included.
+func (f *Font) measureLine(line []rune) (width float64) {
+	for idx, r := range line {
+		if idx > 0 {
+			width += f.Kerning(line[idx-1], r)
+		}
+		if g := f.glyph(r); g != nil {
+			width += g.XAdvance
+		}
+	}
+	return width
+}
+
+// wrap splits text into lines at line breaks, and at spaces to fit lines in width if it is positive.
+// A word wider than width is broken between characters.
+func (f *Font) wrap(text string, width float64) (lines [][]rune) {
+	for _, paragraph := range strings.Split(text, "\n") {
+		var line []rune
+		for idx, word := range strings.Split(paragraph, " ") {
+			next := []rune(word)
+			if idx > 0 {
+				next = append(append(append([]rune(nil), line...), ' '), next...)
+				if width > 0 && f.measureLine(next) > width {
+					lines = append(lines, line)
+					next = []rune(word)
+				}
+			}
+			for width > 0 && len(next) > 1 && f.measureLine(next) > width {
+				cut := 1
+				for cut < len(next) && f.measureLine(next[:cut+1]) <= width {
+					cut++
+				}
+				lines = append(lines, next[:cut])
+				next = next[cut:]
+			}
+			line = next
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// layout places glyphs of the text, and returns the size of the text box.
+func (f *Font) layout(text string, opts TextOptions) (placed []placedGlyph, size linalg.Vector2f64) {
+	lines := f.wrap(text, opts.Width)
+	widths := make([]float64, len(lines))
+	size.X = opts.Width
+	for idx, line := range lines {
+		widths[idx] = f.measureLine(line)
+		if opts.Width <= 0 {
+			size.X = math.Max(size.X, widths[idx])
+		}
+	}
+	size.Y = float64(len(lines)) * f.LineHeight
+	for idx, line := range lines {
+		pen := 0.0
+		switch opts.Align {
+		case TextAlign_Center:
+			pen = (size.X - widths[idx]) / 2
+		case TextAlign_Right:
+			pen = size.X - widths[idx]
+		}
+		top := float64(idx) * f.LineHeight
+		for i, r := range line {
+			if i > 0 {
+				pen += f.Kerning(line[i-1], r)
+			}
+			g := f.glyph(r)
+			if g == nil {
+				continue
+			}
+			if g.page != nil {
+				placed = append(placed, placedGlyph{glyph: g, x: pen + g.XOffset, y: top + g.YOffset})
+			}
+			pen += g.XAdvance
+		}
+	}
+	return placed, size
+}
+
+// Measure returns the size of the text box, which is as wide as the widest line if no width is given.
+func (f *Font) Measure(text string, opts TextOptions) linalg.Vector2f64 {
+	_, size := f.layout(text, opts)
+	return size
+}
+
+// Draw queues glyphs of the text into the batch as quads transformed by m, which is usually the matrix of a transform.
+// The pivot of the text box is placed on the origin of the local space, the top-left corner is used if pivot is nil.
+func (f *Font) Draw(batch *SpriteBatch, text string, m linalg.Mat3, pivot *physics.Pivot, opts TextOptions) {
+	placed, size := f.layout(text, opts)
+	var origin linalg.Vector2f64
+	if pivot != nil {
+		origin = pivot.GetPointOfBox(size.X, size.Y)
+	}
+	if opts.Outline > 0 {
+		// the outline is stamped around glyphs, and covered by them afterwards.
+		d := opts.Outline
+		diag := d * math.Sqrt2 / 2
+		offsets := []linalg.Vector2f64{
+			{X: -d}, {X: d}, {Y: -d}, {Y: d},
+			{X: -diag, Y: -diag}, {X: diag, Y: -diag}, {X: -diag, Y: diag}, {X: diag, Y: diag},
+		}
+		for _, offset := range offsets {
+			f.drawGlyphs(batch, placed, m, origin.Sub(offset), opts.OutlineColor)
+		}
+	}
+	f.drawGlyphs(batch, placed, m, origin, opts.Color)
+}
+
+func (f *Font) drawGlyphs(batch *SpriteBatch, placed []placedGlyph, m linalg.Mat3, origin linalg.Vector2f64, color linalg.RgbaF64) {
+	for _, p := range placed {
+		x, y := p.x-origin.X, p.y-origin.Y
+		w, h := p.glyph.W, p.glyph.H
+		corners := [4]linalg.Vector2f64{
+			m.MulPoint(linalg.NewVector2f64(x, y)),
+			m.MulPoint(linalg.NewVector2f64(x+w, y)),
+			m.MulPoint(linalg.NewVector2f64(x+w, y+h)),
+			m.MulPoint(linalg.NewVector2f64(x, y+h)),
+		}
+		batch.DrawQuadTinted(p.glyph.page.glTexture, DefaultBatchShader, corners, p.glyph.uv, color)
+	}
+}
+
+// RenderText renders a text with top-left corner of its box at pos immediately, which costs a few draw calls.
+// Prefer a TextRenderer drawn by Renderer2DSystem for texts in the world.
+func RenderText(camera *Camera, font *Font, text string, pos linalg.Vector2f64, opts TextOptions) {
+	batch := immediateBatch()
+	batch.Begin(camera)
+	font.Draw(batch, text, linalg.NewTransformMat3(pos, 0, linalg.NewVector2f64(1, 1)), nil, opts)
+	batch.End()
+}
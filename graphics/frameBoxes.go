@@ -0,0 +1,225 @@
+package graphics
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"math"
+	"os"
+	"sort"
+
+	"galaxyzeta.io/engine/linalg"
+)
+
+// boxAlphaThreshold is the alpha above which a pixel is opaque when boxes are generated, in 16 bits.
+const boxAlphaThreshold = 0x7fff
+
+// FrameBoxes are convex polygons of a frame, in pixels with the top-left corner of the frame as origin.
+// Hitbox is the body followed by dynamic colliders, Hurtboxes are areas where the frame could be hurt.
+type FrameBoxes struct {
+	Hitbox    []linalg.Vector2f64
+	Hurtboxes [][]linalg.Vector2f64
+}
+
+// FrameBoxesFile is the JSON description of boxes keyed by frame names, points are [x, y] pairs.
+type FrameBoxesFile struct {
+	Frames map[string]FrameBoxesJSON `json:"frames"`
+}
+
+type FrameBoxesJSON struct {
+	Hitbox    [][2]float64   `json:"hitbox"`
+	Hurtboxes [][][2]float64 `json:"hurtboxes"`
+}
+
+// SetFrameBoxes authors boxes of a registered frame, which replace the generated ones.
+// A hitbox is generated from alpha channel if it is absent. Will panic if a box is not a convex polygon.
+func SetFrameBoxes(frameName string, boxes FrameBoxes) {
+	GetFrame(frameName).SetBoxes(boxes)
+}
+
+// LoadFrameBoxes reads boxes from a JSON file, and authors frames named by namingFunc.
+func LoadFrameBoxes(jsonPath string, namingFunc func(string) string) {
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		panic(err)
+	}
+	var file FrameBoxesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		panic(err)
+	}
+	for name, boxes := range file.Frames {
+		ret := FrameBoxes{Hitbox: pairsToVertices(boxes.Hitbox)}
+		for _, hurtbox := range boxes.Hurtboxes {
+			ret.Hurtboxes = append(ret.Hurtboxes, pairsToVertices(hurtbox))
+		}
+		SetFrameBoxes(namingFunc(name), ret)
+	}
+}
+
+func pairsToVertices(pairs [][2]float64) (ret []linalg.Vector2f64) {
+	for _, pair := range pairs {
+		ret = append(ret, linalg.NewVector2f64(pair[0], pair[1]))
+	}
+	return ret
+}
+
+// SetBoxes authors boxes of the frame, see SetFrameBoxes.
+func (frame *GLFrame) SetBoxes(boxes FrameBoxes) {
+	ret := &FrameBoxes{}
+	if boxes.Hitbox == nil {
+		ret.Hitbox = GenerateFrameBoxes(frame.img).Hitbox
+	} else {
+		ret.Hitbox = mustNormalizeBox(boxes.Hitbox)
+	}
+	for _, hurtbox := range boxes.Hurtboxes {
+		ret.Hurtboxes = append(ret.Hurtboxes, mustNormalizeBox(hurtbox))
+	}
+	frame.boxesOnce.Do(func() {})
+	frame.boxes = ret
+}
+
+// GetBoxes returns boxes of the frame, which are generated from alpha channel once if they are not authored.
+func (frame *GLFrame) GetBoxes() *FrameBoxes {
+	frame.boxesOnce.Do(func() {
+		if frame.boxes == nil {
+			frame.boxes = GenerateFrameBoxes(frame.img)
+		}
+	})
+	return frame.boxes
+}
+
+// GenerateFrameBoxes generates boxes from opaque pixels of an image.
+// The hitbox is the bounding rectangle of opaque pixels, which keeps bodies steady between frames,
+// and the only hurtbox is the convex hull of their contour. A transparent image is covered entirely.
+func GenerateFrameBoxes(img image.Image) *FrameBoxes {
+	bounds := img.Bounds()
+	var contour []linalg.Vector2f64
+	minX, minY, maxX, maxY := bounds.Dx(), bounds.Dy(), 0, 0
+	for y := 0; y < bounds.Dy(); y++ {
+		left, right := -1, -1
+		for x := 0; x < bounds.Dx(); x++ {
+			if _, _, _, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA(); a > boxAlphaThreshold {
+				if left < 0 {
+					left = x
+				}
+				right = x
+			}
+		}
+		if left < 0 {
+			continue
+		}
+		// pixels are squares, so both corners of the leftmost and rightmost pixel are on the contour.
+		fy := float64(y)
+		contour = append(contour,
+			linalg.NewVector2f64(float64(left), fy), linalg.NewVector2f64(float64(left), fy+1),
+			linalg.NewVector2f64(float64(right+1), fy), linalg.NewVector2f64(float64(right+1), fy+1),
+		)
+		minX, maxX = minInt(minX, left), maxInt(maxX, right+1)
+		minY, maxY = minInt(minY, y), maxInt(maxY, y+1)
+	}
+	if contour == nil {
+		rect := rectangleVertices(0, 0, float64(bounds.Dx()), float64(bounds.Dy()))
+		return &FrameBoxes{Hitbox: rect, Hurtboxes: [][]linalg.Vector2f64{rect}}
+	}
+	return &FrameBoxes{
+		Hitbox:    rectangleVertices(float64(minX), float64(minY), float64(maxX), float64(maxY)),
+		Hurtboxes: [][]linalg.Vector2f64{convexHull(contour)},
+	}
+}
+
+// rectangleVertices returns vertices of a rectangle in the winding of hitboxes.
+func rectangleVertices(left float64, top float64, right float64, bottom float64) []linalg.Vector2f64 {
+	return []linalg.Vector2f64{
+		linalg.NewVector2f64(right, top),
+		linalg.NewVector2f64(left, top),
+		linalg.NewVector2f64(left, bottom),
+		linalg.NewVector2f64(right, bottom),
+	}
+}
+
+// convexHull returns the convex hull of points by monotone chain, in the winding of hitboxes.
+func convexHull(points []linalg.Vector2f64) []linalg.Vector2f64 {
+	sorted := append([]linalg.Vector2f64(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X != sorted[j].X {
+			return sorted[i].X < sorted[j].X
+		}
+		return sorted[i].Y < sorted[j].Y
+	})
+	hull := make([]linalg.Vector2f64, 0, len(sorted)+1)
+	// lower chain, and then upper chain. Collinear points are dropped.
+	for _, p := range sorted {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	for idx, lower := len(sorted)-2, len(hull)+1; idx >= 0; idx-- {
+		p := sorted[idx]
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	hull = hull[:len(hull)-1]
+	reverseVertices(hull)
+	return hull
+}
+
+// mustNormalizeBox checks an authored box is a convex polygon, and returns a copy in the winding of hitboxes.
+// Turning in one direction is not enough, a self-intersecting polygon like a pentagram turns more than once.
+func mustNormalizeBox(vertices []linalg.Vector2f64) []linalg.Vector2f64 {
+	n := len(vertices)
+	if n < 3 {
+		panic(fmt.Sprintf("a frame box needs at least 3 vertices, got %d", n))
+	}
+	ret := append([]linalg.Vector2f64(nil), vertices...)
+	sign, turning := 0.0, 0.0
+	for idx := range ret {
+		a, b, c := ret[idx], ret[(idx+1)%n], ret[(idx+2)%n]
+		z := cross(a, b, c)
+		if z == 0 {
+			continue
+		}
+		if sign*z < 0 {
+			panic("a frame box must be a convex polygon")
+		}
+		sign = z
+		turning += math.Abs(math.Atan2(z, (b.X-a.X)*(c.X-b.X)+(b.Y-a.Y)*(c.Y-b.Y)))
+	}
+	if sign == 0 {
+		panic("a frame box must not be degenerated")
+	}
+	if turning > 2*math.Pi+1e-6 {
+		panic("a frame box must not intersect itself")
+	}
+	if sign > 0 {
+		reverseVertices(ret)
+	}
+	return ret
+}
+
+// cross returns the cross product of ab and bc, which is positive if they turn clockwise on screen.
+func cross(a linalg.Vector2f64, b linalg.Vector2f64, c linalg.Vector2f64) float64 {
+	return (b.X-a.X)*(c.Y-b.Y) - (b.Y-a.Y)*(c.X-b.X)
+}
+
+func reverseVertices(vertices []linalg.Vector2f64) {
+	for i, j := 0, len(vertices)-1; i < j; i, j = i+1, j-1 {
+		vertices[i], vertices[j] = vertices[j], vertices[i]
+	}
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
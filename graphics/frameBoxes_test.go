@@ -0,0 +1,81 @@
+package graphics
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"galaxyzeta.io/engine/infra/require"
+	"galaxyzeta.io/engine/linalg"
+)
+
+func eqVertices(expected []linalg.Vector2f64, actual []linalg.Vector2f64) bool {
+	return fmt.Sprint(expected) == fmt.Sprint(actual)
+}
+
+func vertices(xy ...float64) (ret []linalg.Vector2f64) {
+	for idx := 0; idx < len(xy); idx += 2 {
+		ret = append(ret, linalg.NewVector2f64(xy[idx], xy[idx+1]))
+	}
+	return ret
+}
+
+func TestGenerateFrameBoxes(t *testing.T) {
+	// an opaque triangle whose rows span [2, 2+y] in a 8x8 image, from y = 1 to y = 4.
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for y := 1; y <= 4; y++ {
+		for x := 2; x <= 2+y; x++ {
+			img.Set(x, y, color.RGBA{A: 255})
+		}
+	}
+	// a translucent pixel is not opaque.
+	img.Set(7, 7, color.RGBA{A: 100})
+	boxes := GenerateFrameBoxes(img)
+	t.Log(boxes.Hitbox, boxes.Hurtboxes)
+	require.EqBool(true, eqVertices(rectangleVertices(2, 1, 7, 5), boxes.Hitbox))
+	require.EqInt(1, len(boxes.Hurtboxes))
+	require.EqBool(true, eqVertices(vertices(2, 5, 7, 5, 7, 4, 4, 1, 2, 1), boxes.Hurtboxes[0]))
+
+	// a transparent image is covered entirely.
+	boxes = GenerateFrameBoxes(image.NewRGBA(image.Rect(0, 0, 4, 3)))
+	require.EqBool(true, eqVertices(rectangleVertices(0, 0, 4, 3), boxes.Hitbox))
+	require.EqBool(true, eqVertices(rectangleVertices(0, 0, 4, 3), boxes.Hurtboxes[0]))
+}
+
+func TestConvexHull(t *testing.T) {
+	// interior points, collinear points on edges and duplicates are dropped.
+	hull := convexHull(vertices(0, 0, 2, 0, 4, 0, 4, 4, 0, 4, 1, 1, 2, 3, 0, 2, 4, 4))
+	t.Log(hull)
+	require.EqBool(true, eqVertices(vertices(0, 4, 4, 4, 4, 0, 0, 0), hull))
+	// the hull is in the winding of hitboxes.
+	require.EqBool(true, eqVertices(hull, mustNormalizeBox(hull)))
+}
+
+func TestMustNormalizeBox(t *testing.T) {
+	square := rectangleVertices(0, 0, 2, 2)
+	require.EqBool(true, eqVertices(square, mustNormalizeBox(square)))
+	// the opposite winding is reversed, and the input is not modified.
+	reversed := vertices(2, 2, 0, 2, 0, 0, 2, 0)
+	require.EqBool(true, eqVertices(vertices(2, 0, 0, 0, 0, 2, 2, 2), mustNormalizeBox(reversed)))
+	require.EqBool(true, eqVertices(vertices(2, 2, 0, 2, 0, 0, 2, 0), reversed))
+	// a collinear vertex is kept.
+	require.EqBool(false, panics(func() { mustNormalizeBox(vertices(2, 0, 1, 0, 0, 0, 0, 2, 2, 2)) }))
+
+	pentagram := make([]linalg.Vector2f64, 5)
+	for idx := range pentagram {
+		rad := float64(idx*2) * 2 * math.Pi / 5
+		pentagram[idx] = linalg.NewVector2f64(10*math.Cos(rad), 10*math.Sin(rad))
+	}
+	invalid := map[string][]linalg.Vector2f64{
+		"too few vertices": vertices(0, 0, 1, 0),
+		"degenerated":      vertices(0, 0, 1, 0, 2, 0),
+		"concave":          vertices(0, 0, 4, 0, 2, 1, 4, 4, 0, 4),
+		"pentagram":        pentagram,
+	}
+	for name, box := range invalid {
+		t.Log(name)
+		require.EqBool(true, panics(func() { mustNormalizeBox(box) }))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"image"
 	"image/draw"
 	"strings"
+	"time"
 
 	"galaxyzeta.io/engine/infra/file"
 	"galaxyzeta.io/engine/linalg"
@@ -17,6 +18,14 @@ type Shader struct {
 	shader        uint32       // shader in OpenGL descriptor
 	vao           uint32       // vertex array object descriptor
 	AttributeFunc func(uint32) // this is used for setting shader variable descriptions
+
+	// shaders loaded from files by LoadShader could be reloaded.
+	layout      ShaderLayout
+	vertFile    string
+	fragFile    string
+	vertModTime time.Time
+	fragModTime time.Time
+	err         error // errors of the last compilation, the builtin shader of the layout is drawn instead.
 }
 
 // GLNewShader creates a new Shader.
@@ -53,12 +62,19 @@ func GLDeactivateTexture() {
 }
 
 // GLActivateShader uses specific Shader program. This should be put at the end of everything ahead of drawing.
-// to parse shader program parameters.
-func GLActivateShader(name string) {
+// to parse shader program parameters. Returns the program in use.
+func GLActivateShader(name string) uint32 {
 	shader := shaderMap[name]
-	gl.UseProgram(shader.shader)
+	program := shader.program()
+	gl.UseProgram(program)
 	gl.BindVertexArray(shader.vao)
-	shader.AttributeFunc(shader.shader)
+	if shader.AttributeFunc != nil {
+		shader.AttributeFunc(program)
+	}
+	if program != 0 {
+		setBuiltinUniforms(program)
+	}
+	return program
 }
 
 // GLBindData binds data into VBO.
@@ -71,25 +87,36 @@ func GLBindData(vbo uint32, data interface{}, dataSize int, bindingMode uint32)
 }
 
 // GLMustPrepareShaderProgram reads content from vert and frag file, compiles Shader,
-// creates Shader program and then link them up. Returns Shader program descriptor and err.
+// creates Shader program and then link them up. Returns Shader program descriptor. Will panic if an error occurs.
 func GLMustPrepareShaderProgram(vert string, frag string) uint32 {
+	program, err := GLPrepareShaderProgram(vert, frag)
+	if err != nil {
+		panic(err)
+	}
+	return program
+}
+
+// GLPrepareShaderProgram is like GLMustPrepareShaderProgram, but returns errors instead of panicking.
+// Compile and link errors are ShaderErrors located in files.
+func GLPrepareShaderProgram(vert string, frag string) (uint32, error) {
 	fmt.Printf("[System] file = %s %s\n", vert, frag)
 	vertexShaderSource, err := file.OpenAndRead(vert)
 	if err != nil {
-		panic(err)
+		return 0, err
 	}
 	fragmentShaderSource, err := file.OpenAndRead(frag)
 	if err != nil {
-		panic(err)
+		return 0, err
 	}
 
-	vertexShader, err := compileShader(vertexShaderSource, gl.VERTEX_SHADER)
+	vertexShader, err := compileShader(vert, vertexShaderSource, gl.VERTEX_SHADER)
 	if err != nil {
-		panic(err)
+		return 0, err
 	}
-	fragmentShader, err := compileShader(fragmentShaderSource, gl.FRAGMENT_SHADER)
+	fragmentShader, err := compileShader(frag, fragmentShaderSource, gl.FRAGMENT_SHADER)
 	if err != nil {
-		panic(err)
+		gl.DeleteShader(vertexShader)
+		return 0, err
 	}
 
 	program := gl.CreateProgram()
@@ -107,13 +134,17 @@ func GLMustPrepareShaderProgram(vert string, frag string) uint32 {
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetProgramInfoLog(program, logLength, nil, gl.Str(log))
 
-		panic(fmt.Errorf("failed to link program: %v", log))
+		gl.DeleteShader(vertexShader)
+		gl.DeleteShader(fragmentShader)
+		gl.DeleteProgram(program)
+		// link errors are not located in a single file.
+		return 0, parseShaderLog(fmt.Sprintf("%s, %s", vert, frag), log)
 	}
 
 	gl.DeleteShader(vertexShader)
 	gl.DeleteShader(fragmentShader)
 
-	return program
+	return program, nil
 }
 
 // GLNewVBO allocates an VBO.
@@ -172,11 +203,11 @@ func GLRenderRectangle(vbo uint32, rect physics.Rectangle, rgba linalg.Rgba) {
 		rect.Left + rect.Width, rect.Top + rect.Height, 0,
 		rect.Left, rect.Top + rect.Height, 0,
 	}
-	gl.BufferData(gl.ARRAY_BUFFER, 4, gl.Ptr(vertices), gl.DYNAMIC_DRAW)
-	gl.DrawArrays(gl.QUADS, 0, 4*2)
+	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*8, gl.Ptr(vertices), gl.DYNAMIC_DRAW)
+	gl.DrawArrays(gl.TRIANGLE_FAN, 0, 4)
 }
 
-func compileShader(source string, shaderType uint32) (uint32, error) {
+func compileShader(path string, source string, shaderType uint32) (uint32, error) {
 	shader := gl.CreateShader(shaderType)
 
 	csources, free := gl.Strs(source)
@@ -193,7 +224,8 @@ func compileShader(source string, shaderType uint32) (uint32, error) {
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
 
-		return 0, fmt.Errorf("failed to compile %v: %v", source, log)
+		gl.DeleteShader(shader)
+		return 0, parseShaderLog(path, log)
 	}
 
 	return shader, nil
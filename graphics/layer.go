@@ -0,0 +1,53 @@
+package graphics
+
+import "fmt"
+
+// MaxLayers is the number of layers, renderables on a layer are drawn by cameras whose layer masks contain the layer.
+const MaxLayers = 32
+
+const LayerDefault = 0
+
+// LayerMaskAll contains every layer, which is the layer mask of new cameras.
+const LayerMaskAll = ^uint32(0)
+
+var layerMap = map[string]int{
+	"default": LayerDefault,
+}
+
+// ILayered is implemented by renderables which could be put on a layer, others are on the default layer.
+type ILayered interface {
+	GetLayer() int
+}
+
+// LayerOf returns the layer of a renderable.
+func LayerOf(ren IRenderable) int {
+	if layered, ok := ren.(ILayered); ok {
+		return layered.GetLayer()
+	}
+	return LayerDefault
+}
+
+// RegisterLayer names a layer, which could be used by level files. Will panic if the layer is out of range.
+func RegisterLayer(name string, layer int) {
+	if layer < 0 || layer >= MaxLayers {
+		panic(fmt.Sprintf("layer must be in [0, %d)", MaxLayers))
+	}
+	layerMap[name] = layer
+}
+
+// GetLayer gets a layer by name. Will panic if it is not found.
+func GetLayer(name string) int {
+	layer, ok := layerMap[name]
+	if !ok {
+		panic(fmt.Sprintf("layer %s not found", name))
+	}
+	return layer
+}
+
+// LayerMask returns the mask containing layers.
+func LayerMask(layers ...int) (mask uint32) {
+	for _, layer := range layers {
+		mask |= 1 << uint(layer)
+	}
+	return mask
+}
@@ -0,0 +1,118 @@
+package graphics
+
+import (
+	"fmt"
+	"sync"
+
+	"galaxyzeta.io/engine/linalg"
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+var materialMap = map[string]*Material{}
+
+// Material draws quads with a shader of sprite layout and values of its uniforms, such as the amount of a hit flash.
+// Quads of different materials are never batched together, so materials should be shared by renderers looking the same.
+// Uniforms not set by a material keep values set by the last material of the same shader.
+type Material struct {
+	Shader string
+	Tint   linalg.RgbaF64 // multiplies colors of textures, like tints of quads.
+
+	mu       sync.RWMutex // uniforms are set on the game thread and uploaded on the render thread.
+	uniforms map[string][]float32
+}
+
+// NewMaterial returns a material of a shader without any uniform.
+func NewMaterial(shader string) *Material {
+	return &Material{
+		Shader:   shader,
+		Tint:     White,
+		uniforms: map[string][]float32{},
+	}
+}
+
+// RegisterMaterial registers a material with a name, which could be used by level files.
+func RegisterMaterial(name string, material *Material) {
+	materialMap[name] = material
+}
+
+// GetMaterial gets a copy of a material, which could be modified without affecting others. Will panic if it is not found.
+func GetMaterial(name string) *Material {
+	material, ok := materialMap[name]
+	if !ok {
+		panic(fmt.Sprintf("material %s not found", name))
+	}
+	return material.Clone()
+}
+
+// Clone returns a copy of the material.
+func (m *Material) Clone() *Material {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ret := NewMaterial(m.Shader)
+	ret.Tint = m.Tint
+	for name, value := range m.uniforms {
+		ret.uniforms[name] = append([]float32(nil), value...)
+	}
+	return ret
+}
+
+// SetFloat sets a float uniform.
+func (m *Material) SetFloat(name string, v float64) {
+	m.SetUniform(name, v)
+}
+
+// SetVec2 sets a vec2 uniform.
+func (m *Material) SetVec2(name string, v linalg.Vector2f64) {
+	m.SetUniform(name, v.X, v.Y)
+}
+
+// SetColor sets a vec4 uniform to a color.
+func (m *Material) SetColor(name string, c linalg.RgbaF64) {
+	m.SetUniform(name, c.X, c.Y, c.Z, c.W)
+}
+
+// SetUniform sets a float, vec2, vec3 or vec4 uniform by the number of values.
+func (m *Material) SetUniform(name string, values ...float64) {
+	if len(values) == 0 || len(values) > 4 {
+		panic("uniform must have 1 to 4 values")
+	}
+	value := make([]float32, len(values))
+	for idx, v := range values {
+		value[idx] = float32(v)
+	}
+	m.mu.Lock()
+	m.uniforms[name] = value
+	m.mu.Unlock()
+}
+
+// GetFloat returns the first value of a uniform, 0 if it is not set.
+func (m *Material) GetFloat(name string) float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if value := m.uniforms[name]; len(value) > 0 {
+		return float64(value[0])
+	}
+	return 0
+}
+
+// apply uploads uniforms to the program in use, uniforms the program does not declare are skipped.
+func (m *Material) apply(program uint32) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for name, value := range m.uniforms {
+		loc := gl.GetUniformLocation(program, gl.Str(name+"\x00"))
+		if loc < 0 {
+			continue
+		}
+		switch len(value) {
+		case 1:
+			gl.Uniform1f(loc, value[0])
+		case 2:
+			gl.Uniform2f(loc, value[0], value[1])
+		case 3:
+			gl.Uniform3f(loc, value[0], value[1], value[2])
+		case 4:
+			gl.Uniform4f(loc, value[0], value[1], value[2], value[3])
+		}
+	}
+}
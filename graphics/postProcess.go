@@ -0,0 +1,408 @@
+package graphics
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"galaxyzeta.io/engine/linalg"
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// builtin post-processing shaders are of sprite layout, drawn with the builtin vertex shader.
+const (
+	PostShader_ColorGrading = "post_colorGrading"
+	PostShader_Vignette     = "post_vignette"
+	PostShader_Scanlines    = "post_scanlines"
+	PostShader_Pixelate     = "post_pixelate"
+	PostShader_Flash        = "post_flash"
+	PostShader_BloomExtract = "post_bloomExtract"
+	PostShader_Blur         = "post_blur"
+	PostShader_BloomCombine = "post_bloomCombine"
+)
+
+// builtinPostShaderFiles are fragment files relative to the root of the engine.
+var builtinPostShaderFiles = map[string]string{
+	PostShader_ColorGrading: "graphics/shaders/post/colorGrading.glsl",
+	PostShader_Vignette:     "graphics/shaders/post/vignette.glsl",
+	PostShader_Scanlines:    "graphics/shaders/post/scanlines.glsl",
+	PostShader_Pixelate:     "graphics/shaders/post/pixelate.glsl",
+	PostShader_Flash:        "graphics/shaders/post/flash.glsl",
+	PostShader_BloomExtract: "graphics/shaders/post/bloomExtract.glsl",
+	PostShader_Blur:         "graphics/shaders/post/blur.glsl",
+	PostShader_BloomCombine: "graphics/shaders/post/bloomCombine.glsl",
+}
+
+// IPostEffect is a full-screen effect of a post-processing chain.
+type IPostEffect interface {
+	GetName() string
+	GetMaterial() *Material // uniforms of the effect.
+	IsEnabled() bool
+	SetEnabled(enabled bool)
+	// Apply draws src processed into dst, which is the screen if nil. It is called on the render thread.
+	Apply(p *PostProcessor, src *RenderTarget, dst *RenderTarget)
+}
+
+// PostEffect draws a single full-screen pass with the shader and uniforms of its material.
+// Shaders of effects are of sprite layout, the builtin uniform resolution is the size of the source in pixels.
+type PostEffect struct {
+	Name     string
+	Material *Material
+
+	mu      sync.RWMutex
+	enabled bool
+}
+
+// BloomEffect makes bright pixels glow. Bright pixels above the threshold are extracted, blurred at half resolution,
+// and added back by intensity. radius spreads the blur in pixels.
+type BloomEffect struct {
+	*PostEffect
+	extract *Material
+	blur    *Material
+}
+
+// FlashEffect covers the screen with a color fading out, which is started by Flash.
+type FlashEffect struct {
+	*PostEffect
+	flashMu  sync.RWMutex
+	start    time.Time
+	duration time.Duration
+}
+
+// PostProcessor renders the scene viewed by a camera into an off-screen target, and runs an ordered chain of effects before presenting it to the screen.
+// Effects could be modified from any thread, targets are allocated on the render thread at the first frame with any effect enabled.
+type PostProcessor struct {
+	mu       sync.RWMutex
+	effects  []IPostEffect
+	targets  map[string]*RenderTarget
+	active   bool     // the scene is being drawn into the off-screen target.
+	viewport [4]int32 // viewport of the screen, which is restored when presenting.
+	vbo      uint32
+}
+
+// BuiltinPostShaders returns names and fragment files of builtin post-processing shaders, files are relative to the root of the engine.
+func BuiltinPostShaders() map[string]string {
+	ret := make(map[string]string, len(builtinPostShaderFiles))
+	for name, file := range builtinPostShaderFiles {
+		ret[name] = file
+	}
+	return ret
+}
+
+// NewPostEffect returns an enabled effect drawn with a shader.
+func NewPostEffect(name string, shader string) *PostEffect {
+	return &PostEffect{
+		Name:     name,
+		Material: NewMaterial(shader),
+		enabled:  true,
+	}
+}
+
+// NewColorGradingEffect adjusts brightness, contrast and saturation, which are 0, 1 and 1 by default.
+func NewColorGradingEffect(name string) *PostEffect {
+	e := NewPostEffect(name, PostShader_ColorGrading)
+	e.Material.SetFloat("brightness", 0)
+	e.Material.SetFloat("contrast", 1)
+	e.Material.SetFloat("saturation", 1)
+	return e
+}
+
+// NewVignetteEffect darkens corners of the screen with color. Pixels farther than radius from the center are covered by intensity,
+// the edge is blurred by softness. Distances are relative to the height of the screen.
+func NewVignetteEffect(name string) *PostEffect {
+	e := NewPostEffect(name, PostShader_Vignette)
+	e.Material.SetFloat("intensity", 0.5)
+	e.Material.SetFloat("radius", 0.75)
+	e.Material.SetFloat("softness", 0.45)
+	e.Material.SetColor("color", linalg.NewRgbaF64(0, 0, 0, 1))
+	return e
+}
+
+// NewScanlinesEffect imitates a CRT screen, with dark scanlines lineHeight pixels high and the screen bent by curvature.
+func NewScanlinesEffect(name string) *PostEffect {
+	e := NewPostEffect(name, PostShader_Scanlines)
+	e.Material.SetFloat("intensity", 0.25)
+	e.Material.SetFloat("lineHeight", 2)
+	e.Material.SetFloat("curvature", 0)
+	return e
+}
+
+// NewPixelateEffect draws the screen in blocks of pixelSize pixels.
+func NewPixelateEffect(name string) *PostEffect {
+	e := NewPostEffect(name, PostShader_Pixelate)
+	e.Material.SetFloat("pixelSize", 4)
+	return e
+}
+
+// NewBloomEffect returns a bloom with threshold 0.7, intensity 1 and radius 2.
+func NewBloomEffect(name string) *BloomEffect {
+	e := &BloomEffect{
+		PostEffect: NewPostEffect(name, PostShader_BloomCombine),
+		extract:    NewMaterial(PostShader_BloomExtract),
+		blur:       NewMaterial(PostShader_Blur),
+	}
+	e.Material.SetFloat("threshold", 0.7)
+	e.Material.SetFloat("intensity", 1)
+	e.Material.SetFloat("radius", 2)
+	return e
+}
+
+// NewFlashEffect returns a disabled flash, which is enabled by Flash and disabled again once the flash fades out.
+func NewFlashEffect(name string) *FlashEffect {
+	e := &FlashEffect{PostEffect: NewPostEffect(name, PostShader_Flash)}
+	e.Material.SetFloat("amount", 0)
+	e.Material.SetColor("color", White)
+	e.enabled = false
+	return e
+}
+
+// NewPostProcessor returns an empty chain, which presents the scene as it is.
+func NewPostProcessor() *PostProcessor {
+	return &PostProcessor{
+		targets: map[string]*RenderTarget{},
+	}
+}
+
+// GetName is an implementation of IPostEffect.
+func (e *PostEffect) GetName() string {
+	return e.Name
+}
+
+// GetMaterial is an implementation of IPostEffect.
+func (e *PostEffect) GetMaterial() *Material {
+	return e.Material
+}
+
+func (e *PostEffect) IsEnabled() (ret bool) {
+	e.mu.RLock()
+	ret = e.enabled
+	e.mu.RUnlock()
+	return
+}
+
+func (e *PostEffect) SetEnabled(enabled bool) {
+	e.mu.Lock()
+	e.enabled = enabled
+	e.mu.Unlock()
+}
+
+// Apply is an implementation of IPostEffect.
+func (e *PostEffect) Apply(p *PostProcessor, src *RenderTarget, dst *RenderTarget) {
+	p.DrawPass(e.Material.Shader, e.Material, src, dst, nil)
+}
+
+// Apply is an implementation of IPostEffect.
+func (e *BloomEffect) Apply(p *PostProcessor, src *RenderTarget, dst *RenderTarget) {
+	w, h := src.Size()
+	w, h = maxInt(w/2, 1), maxInt(h/2, 1)
+	a, b := p.Target("bloomA", w, h), p.Target("bloomB", w, h)
+	e.extract.SetFloat("threshold", e.Material.GetFloat("threshold"))
+	p.DrawPass(PostShader_BloomExtract, e.extract, src, a, nil)
+	radius := e.Material.GetFloat("radius")
+	e.blur.SetVec2("direction", linalg.NewVector2f64(radius, 0))
+	p.DrawPass(PostShader_Blur, e.blur, a, b, nil)
+	e.blur.SetVec2("direction", linalg.NewVector2f64(0, radius))
+	p.DrawPass(PostShader_Blur, e.blur, b, a, nil)
+	p.DrawPass(e.Material.Shader, e.Material, src, dst, func(program uint32) {
+		// the blurred bloom is bound to the second texture unit.
+		gl.ActiveTexture(gl.TEXTURE1)
+		gl.BindTexture(gl.TEXTURE_2D, a.Texture())
+		gl.Uniform1i(gl.GetUniformLocation(program, gl.Str("bloomTex\x00")), 1)
+		gl.ActiveTexture(gl.TEXTURE0)
+	})
+}
+
+// Flash covers the screen with color, which fades out in duration. The effect is enabled as well.
+func (e *FlashEffect) Flash(color linalg.RgbaF64, duration time.Duration) {
+	e.Material.SetColor("color", color)
+	e.flashMu.Lock()
+	e.start = time.Now()
+	e.duration = duration
+	e.flashMu.Unlock()
+	e.SetEnabled(true)
+}
+
+// Apply is an implementation of IPostEffect.
+func (e *FlashEffect) Apply(p *PostProcessor, src *RenderTarget, dst *RenderTarget) {
+	e.flashMu.RLock()
+	amount := 0.0
+	if since := time.Since(e.start); e.duration > 0 && since < e.duration {
+		amount = 1 - float64(since)/float64(e.duration)
+	}
+	e.flashMu.RUnlock()
+	if amount <= 0 {
+		// the screen is drawn as it is for the last time.
+		e.SetEnabled(false)
+	}
+	e.Material.SetFloat("amount", amount)
+	p.DrawPass(e.Material.Shader, e.Material, src, dst, nil)
+}
+
+// Add appends an effect to the end of the chain.
+func (p *PostProcessor) Add(effect IPostEffect) {
+	p.mu.Lock()
+	p.effects = append(p.effects, effect)
+	p.mu.Unlock()
+}
+
+// Remove removes effects of a name.
+func (p *PostProcessor) Remove(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	effects := p.effects[:0]
+	for _, effect := range p.effects {
+		if effect.GetName() != name {
+			effects = append(effects, effect)
+		}
+	}
+	for idx := len(effects); idx < len(p.effects); idx++ {
+		p.effects[idx] = nil
+	}
+	p.effects = effects
+}
+
+// Clear removes all effects.
+func (p *PostProcessor) Clear() {
+	p.mu.Lock()
+	p.effects = nil
+	p.mu.Unlock()
+}
+
+// Get returns the first effect of a name, nil if it is not found.
+func (p *PostProcessor) Get(name string) IPostEffect {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, effect := range p.effects {
+		if effect.GetName() == name {
+			return effect
+		}
+	}
+	return nil
+}
+
+// Effects returns a copy of the chain in order.
+func (p *PostProcessor) Effects() []IPostEffect {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]IPostEffect(nil), p.effects...)
+}
+
+// SetEnabled turns an effect on or off. Will panic if it is not found.
+func (p *PostProcessor) SetEnabled(name string, enabled bool) {
+	effect := p.Get(name)
+	if effect == nil {
+		panic(fmt.Sprintf("post effect %s not found", name))
+	}
+	effect.SetEnabled(enabled)
+}
+
+// Begin redirects drawings of the frame into the off-screen target if any effect is enabled.
+// It must be called after the viewport of the camera is set by BeginCamera, and before the camera draws anything.
+func (p *PostProcessor) Begin() {
+	if len(p.enabledEffects()) == 0 {
+		return
+	}
+	// the scene is drawn at the size of the viewport, and presented back into it.
+	gl.GetIntegerv(gl.VIEWPORT, &p.viewport[0])
+	p.Target("scene", int(p.viewport[2]), int(p.viewport[3])).Bind()
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	p.active = true
+}
+
+// End runs enabled effects in order on the scene drawn since Begin, and presents the result to the screen.
+func (p *PostProcessor) End() {
+	if !p.active {
+		return
+	}
+	p.active = false
+	scene := p.targets["scene"]
+	w, h := scene.Size()
+	gl.Disable(gl.BLEND)
+	effects := p.enabledEffects()
+	if len(effects) == 0 {
+		// effects are disabled by other threads during the frame.
+		p.DrawPass(DefaultBatchShader, nil, scene, nil, nil)
+		return
+	}
+	ping, pong := p.Target("ping", w, h), p.Target("pong", w, h)
+	src := scene
+	for idx, effect := range effects {
+		var dst *RenderTarget
+		if idx < len(effects)-1 {
+			dst = ping
+			if src == ping {
+				dst = pong
+			}
+		}
+		effect.Apply(p, src, dst)
+		src = dst
+	}
+}
+
+// Target returns an off-screen target of a name, which is reallocated if its size changes. It must be called on the render thread.
+func (p *PostProcessor) Target(name string, width int, height int) *RenderTarget {
+	t, ok := p.targets[name]
+	if ok {
+		if w, h := t.Size(); w == width && h == height {
+			return t
+		}
+		t.Release()
+	}
+	t = NewRenderTarget(width, height)
+	p.targets[name] = t
+	return t
+}
+
+// DrawPass draws src as a full-screen quad into dst with a shader of sprite layout, dst is the screen if nil.
+// Uniforms of the material are set, as well as resolution, which is the size of src. setup could bind more textures or uniforms.
+func (p *PostProcessor) DrawPass(shader string, material *Material, src *RenderTarget, dst *RenderTarget, setup func(program uint32)) {
+	if dst != nil {
+		dst.Bind()
+	} else {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+		gl.Viewport(p.viewport[0], p.viewport[1], p.viewport[2], p.viewport[3])
+	}
+	tint := White
+	if material != nil {
+		tint = material.Tint
+	}
+	r, g, b, a := tint.X, tint.Y, tint.Z, tint.W
+	// OpenGL space, textures of targets have their origin at the bottom-left corner.
+	vertices := []float64{
+		-1, -1, 0, 0, 0, r, g, b, a,
+		1, -1, 0, 1, 0, r, g, b, a,
+		1, 1, 0, 1, 1, r, g, b, a,
+		-1, -1, 0, 0, 0, r, g, b, a,
+		1, 1, 0, 1, 1, r, g, b, a,
+		-1, 1, 0, 0, 1, r, g, b, a,
+	}
+	if p.vbo == 0 {
+		p.vbo = GLNewVBO(1)
+	}
+	GLBindData(p.vbo, vertices, len(vertices)*8, gl.STREAM_DRAW)
+	GLActivateTexture(src.Texture())
+	if program := GLActivateShader(shader); program != 0 {
+		if material != nil {
+			material.apply(program)
+		}
+		if loc := gl.GetUniformLocation(program, gl.Str("resolution\x00")); loc >= 0 {
+			w, h := src.Size()
+			gl.Uniform2f(loc, float32(w), float32(h))
+		}
+		if setup != nil {
+			setup(program)
+		}
+	}
+	gl.DrawArrays(gl.TRIANGLES, 0, 6)
+}
+
+func (p *PostProcessor) enabledEffects() (ret []IPostEffect) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, effect := range p.effects {
+		if effect.IsEnabled() {
+			ret = append(ret, effect)
+		}
+	}
+	return ret
+}
@@ -1,7 +1,10 @@
 package graphics
 
+// IRenderable is drawn by Renderer2DSystem in order of sorting layers and Z, see DrawsBefore, once by each camera whose layer mask contains its layer.
+// Render should queue its quads into the batch instead of drawing them immediately, the camera is available from the batch.
+// PostRender is called each time after it is rendered.
 type IRenderable interface {
-	Render(cam *Camera)
+	Render(batch *SpriteBatch)
 	PostRender()
 	IsStatic() bool
 	Z() int64
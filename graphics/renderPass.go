@@ -0,0 +1,75 @@
+package graphics
+
+import (
+	"sort"
+
+	"galaxyzeta.io/engine/linalg"
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+var renderingCamera *Camera // camera drawing between BeginCamera and EndCamera, only accessed on the render thread.
+
+// GetRenderingCameras returns enabled cameras in render order, cameras of the same order are drawn by their indices.
+func GetRenderingCameras() []*Camera {
+	ret := make([]*Camera, 0, len(cameraPool))
+	for _, cam := range cameraPool {
+		if cam.IsEnabled() {
+			ret = append(ret, cam)
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].GetOrder() < ret[j].GetOrder()
+	})
+	return ret
+}
+
+// GetRenderingCamera returns the camera drawing the scene, or the current camera out of camera passes.
+// Drawings on the render thread, such as shapes, go through it.
+func GetRenderingCamera() *Camera {
+	if renderingCamera != nil {
+		return renderingCamera
+	}
+	return GetCurrentCamera()
+}
+
+// CameraAt returns the enabled camera drawn on top at a point in screen coordinates, such as the cursor position.
+// It returns nil if no viewport contains the point.
+func CameraAt(p linalg.Vector2f64) *Camera {
+	cams := GetRenderingCameras()
+	for idx := len(cams) - 1; idx >= 0; idx-- {
+		if cams[idx].Viewport().Contains(p) {
+			return cams[idx]
+		}
+	}
+	return nil
+}
+
+// ClearScreen clears the whole window black, areas not covered by any viewport are left black.
+// It must be called on the render thread before cameras draw.
+func ClearScreen() {
+	fb := GetFramebufferSize()
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	gl.Viewport(0, 0, int32(fb.X), int32(fb.Y))
+	gl.ClearColor(0, 0, 0, 1)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+}
+
+// BeginCamera clears the viewport of the camera and sets it, drawings go through the camera until EndCamera.
+// It must be called on the render thread.
+func BeginCamera(cam *Camera) {
+	bg := GetBackgroundColor()
+	x, y, w, h := cam.glViewport()
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.Scissor(x, y, w, h)
+	gl.ClearColor(float32(bg.X), float32(bg.Y), float32(bg.Z), float32(bg.W))
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	gl.Disable(gl.SCISSOR_TEST)
+	gl.Viewport(x, y, w, h)
+	renderingCamera = cam
+}
+
+// EndCamera finishes the pass of the camera begun by BeginCamera.
+func EndCamera() {
+	renderingCamera = nil
+}
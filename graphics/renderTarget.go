@@ -0,0 +1,61 @@
+package graphics
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// RenderTarget is an off-screen framebuffer drawn into a texture, which could be sampled by later passes.
+// All methods must be called on the render thread.
+type RenderTarget struct {
+	fbo     uint32
+	texture uint32
+	width   int32
+	height  int32
+}
+
+// NewRenderTarget allocates a framebuffer with a color texture of the size. Will panic if the framebuffer is not complete.
+func NewRenderTarget(width int, height int) *RenderTarget {
+	t := &RenderTarget{width: int32(width), height: int32(height)}
+	gl.GenTextures(1, &t.texture)
+	gl.BindTexture(gl.TEXTURE_2D, t.texture)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, t.width, t.height, 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+
+	gl.GenFramebuffers(1, &t.fbo)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, t.fbo)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, t.texture, 0)
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		panic(fmt.Sprintf("framebuffer is not complete: 0x%x", status))
+	}
+	return t
+}
+
+// Bind makes following drawings go into the target, the viewport covers the whole target.
+func (t *RenderTarget) Bind() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, t.fbo)
+	gl.Viewport(0, 0, t.width, t.height)
+}
+
+// Texture returns the texture drawn into.
+func (t *RenderTarget) Texture() uint32 {
+	return t.texture
+}
+
+// Size returns the size of the target in pixels.
+func (t *RenderTarget) Size() (width int, height int) {
+	return int(t.width), int(t.height)
+}
+
+// Release deletes the framebuffer and its texture.
+func (t *RenderTarget) Release() {
+	gl.DeleteFramebuffers(1, &t.fbo)
+	gl.DeleteTextures(1, &t.texture)
+}
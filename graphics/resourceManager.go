@@ -83,6 +83,13 @@ func GetCamera(index int) *Camera {
 	return cameraPool[index]
 }
 
+// GetCameraCount returns the number of cameras in the pool.
+func GetCameraCount() int {
+	return len(cameraPool)
+}
+
+// SetCurrentCamera sets the camera game logic refers to, such as the camera followed by helpers of sdk.
+// It does not change which cameras draw, all enabled cameras do.
 func SetCurrentCamera(index int) {
 	if index > len(cameraPool) {
 		panic("invalid index, should be less than the length of cameraPool")
@@ -93,12 +100,15 @@ func SetCurrentCamera(index int) {
 	mu.Unlock()
 }
 
-// InitCameraPool inits camera pool with given camera counts. It will be called by core. Do not use this in ypur game logic.
-func InitCameraPool(camCnt int) {
+// InitCameraPool inits camera pool with given camera counts and their virtual resolution. It will be called by core. Do not use this in ypur game logic.
+// Only the first camera is enabled, others draw once they are enabled.
+func InitCameraPool(camCnt int, resolution linalg.Vector2f64) {
 	// init camera list
 	cameraPool = make([]*Camera, 0, camCnt)
 	for i := 0; i < camCnt; i++ {
-		cameraPool = append(cameraPool, NewCamera(linalg.NewVector2f64(0, 0), linalg.NewVector2f64(640, 480)))
+		cam := NewCamera(linalg.NewVector2f64(0, 0), resolution)
+		cam.SetEnabled(i == 0)
+		cameraPool = append(cameraPool, cam)
 	}
 }
 
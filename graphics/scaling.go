@@ -0,0 +1,93 @@
+package graphics
+
+import (
+	"math"
+
+	"galaxyzeta.io/engine/linalg"
+)
+
+// ScaleMode decides how the virtual resolution of cameras is fitted into the window.
+type ScaleMode uint8
+
+const (
+	ScaleMode_Stretch   ScaleMode = iota // fills the window, the view is distorted if aspect ratios differ.
+	ScaleMode_Letterbox                  // scales the view as large as it fits keeping its aspect ratio, bars are left black.
+	ScaleMode_Integer                    // scales the view by the largest integer that fits, so that pixel art stays sharp. It letterboxes if the window is too small.
+	ScaleMode_Expand                     // fills the window keeping the aspect ratio of pixels, more of the world is seen along the longer axis.
+)
+
+var scaleMode = ScaleMode_Stretch
+var framebufferSize *linalg.Vector2f64 = &linalg.Vector2f64{}
+var backgroundColor = linalg.NewRgbaF64(0.5, 0.5, 1, 1)
+
+func SetScaleMode(mode ScaleMode) {
+	mutexList[mutexScreenResolution].Lock()
+	scaleMode = mode
+	mutexList[mutexScreenResolution].Unlock()
+}
+
+func GetScaleMode() ScaleMode {
+	mutexList[mutexScreenResolution].RLock()
+	defer mutexList[mutexScreenResolution].RUnlock()
+	return scaleMode
+}
+
+// SetFramebufferSize sets the size of the window in pixels, which differs from the screen resolution on high DPI displays.
+// It is set by core when the window is created or resized.
+func SetFramebufferSize(x float64, y float64) {
+	mutexList[mutexScreenResolution].Lock()
+	framebufferSize.X = x
+	framebufferSize.Y = y
+	mutexList[mutexScreenResolution].Unlock()
+}
+
+// GetFramebufferSize returns the size of the window in pixels, the screen resolution if it has never been set.
+func GetFramebufferSize() linalg.Vector2f64 {
+	mutexList[mutexScreenResolution].RLock()
+	defer mutexList[mutexScreenResolution].RUnlock()
+	if framebufferSize.X <= 0 || framebufferSize.Y <= 0 {
+		return *screenResolution
+	}
+	return *framebufferSize
+}
+
+// SetBackgroundColor sets the color the viewport is cleared with before each frame.
+func SetBackgroundColor(color linalg.RgbaF64) {
+	mutexList[mutexScreenResolution].Lock()
+	backgroundColor = color
+	mutexList[mutexScreenResolution].Unlock()
+}
+
+func GetBackgroundColor() linalg.RgbaF64 {
+	mutexList[mutexScreenResolution].RLock()
+	defer mutexList[mutexScreenResolution].RUnlock()
+	return backgroundColor
+}
+
+// fitViewport fits a view of the virtual resolution into area by mode.
+// It returns the viewport, and the resolution actually viewed, which is larger than the virtual resolution only in expand mode.
+func fitViewport(area linalg.Rect, virtual linalg.Vector2f64, mode ScaleMode) (viewport linalg.Rect, resolution linalg.Vector2f64) {
+	if area.W <= 0 || area.H <= 0 || virtual.X <= 0 || virtual.Y <= 0 {
+		return area, virtual
+	}
+	scale := math.Min(area.W/virtual.X, area.H/virtual.Y)
+	switch mode {
+	case ScaleMode_Letterbox:
+		return centerIn(area, virtual.Scale(scale)), virtual
+	case ScaleMode_Integer:
+		// a window smaller than the virtual resolution is letterboxed, rather than cropping the view.
+		if scale >= 1 {
+			scale = math.Floor(scale)
+		}
+		return centerIn(area, virtual.Scale(scale)), virtual
+	case ScaleMode_Expand:
+		return area, linalg.NewVector2f64(area.W/scale, area.H/scale)
+	}
+	return area, virtual
+}
+
+// centerIn returns a rect of size at the center of area, whose corner is snapped to pixels.
+func centerIn(area linalg.Rect, size linalg.Vector2f64) linalg.Rect {
+	w, h := math.Round(size.X), math.Round(size.Y)
+	return linalg.NewRect(area.X+math.Floor((area.W-w)/2), area.Y+math.Floor((area.H-h)/2), w, h)
+}
@@ -0,0 +1,38 @@
+package graphics
+
+import (
+	"testing"
+
+	"galaxyzeta.io/engine/infra/require"
+	"galaxyzeta.io/engine/linalg"
+)
+
+func TestFitViewport(t *testing.T) {
+	virtual := linalg.NewVector2f64(320, 180)
+	cases := []struct {
+		name       string
+		mode       ScaleMode
+		area       linalg.Rect
+		viewport   linalg.Rect
+		resolution linalg.Vector2f64
+	}{
+		{"stretch", ScaleMode_Stretch, linalg.NewRect(0, 0, 800, 800), linalg.NewRect(0, 0, 800, 800), virtual},
+		{"letterbox wide", ScaleMode_Letterbox, linalg.NewRect(0, 0, 1000, 360), linalg.NewRect(180, 0, 640, 360), virtual},
+		{"letterbox tall", ScaleMode_Letterbox, linalg.NewRect(0, 0, 640, 500), linalg.NewRect(0, 70, 640, 360), virtual},
+		{"letterbox odd", ScaleMode_Letterbox, linalg.NewRect(10, 20, 641, 361), linalg.NewRect(10, 20, 641, 361), virtual},
+		{"integer", ScaleMode_Integer, linalg.NewRect(0, 0, 1000, 600), linalg.NewRect(20, 30, 960, 540), virtual},
+		{"integer exact", ScaleMode_Integer, linalg.NewRect(0, 0, 320, 180), linalg.NewRect(0, 0, 320, 180), virtual},
+		{"integer small", ScaleMode_Integer, linalg.NewRect(0, 0, 160, 120), linalg.NewRect(0, 15, 160, 90), virtual},
+		{"expand wide", ScaleMode_Expand, linalg.NewRect(0, 0, 800, 360), linalg.NewRect(0, 0, 800, 360), linalg.NewVector2f64(400, 180)},
+		{"expand tall", ScaleMode_Expand, linalg.NewRect(0, 0, 640, 480), linalg.NewRect(0, 0, 640, 480), linalg.NewVector2f64(320, 240)},
+		{"empty", ScaleMode_Letterbox, linalg.NewRect(0, 0, 0, 0), linalg.NewRect(0, 0, 0, 0), virtual},
+	}
+	for _, c := range cases {
+		viewport, resolution := fitViewport(c.area, virtual, c.mode)
+		t.Log(c.name, viewport, resolution)
+		require.EqBool(true, viewport == c.viewport)
+		require.EqBool(true, resolution == c.resolution)
+		// the viewport never exceeds the window.
+		require.EqBool(true, viewport.W <= c.area.W && viewport.H <= c.area.H)
+	}
+}
@@ -0,0 +1,227 @@
+package graphics
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"galaxyzeta.io/engine/infra/logger"
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+var shaderLogger = logger.New("Shader")
+
+// shaderStartTime is the origin of the builtin time uniform.
+var shaderStartTime = time.Now()
+
+// shaderHotReload tells whether ReloadModifiedShaders recompiles shaders.
+var shaderHotReload bool
+
+// shaderLogLine matches a line of compile logs, which locates errors in various formats of drivers:
+// "0:12(5): error: ..." on Mesa, "0(12) : error C0000: ..." on NVIDIA and "ERROR: 0:12: ..." on Apple and AMD.
+var shaderLogLine = regexp.MustCompile(`^\s*(?:ERROR:|WARNING:)?\s*\d+[:(](\d+)\)?(?:\(\d+\))?\s*:\s*(.*)$`)
+
+// ShaderLayout describes vertices a shader program takes.
+type ShaderLayout uint8
+
+const (
+	ShaderLayout_Sprite ShaderLayout = iota // vertices of SpriteBatch: aPos, vertTexCoord and vertTint, with the texture bound to tex.
+	ShaderLayout_Color                      // colored vertices of shapes: aPos and inputColor.
+)
+
+// builtin shaders are drawn in place of shaders of the same layout failing to compile.
+var builtinShaders = map[ShaderLayout]string{
+	ShaderLayout_Sprite: DefaultBatchShader,
+	ShaderLayout_Color:  "color",
+}
+
+// builtinShaderFiles are relative to the root of the engine.
+var builtinShaderFiles = map[ShaderLayout][2]string{
+	ShaderLayout_Sprite: {"graphics/shaders/simpleVertex.glsl", "graphics/shaders/simpleFragment.glsl"},
+	ShaderLayout_Color:  {"graphics/shaders/colorVertex.glsl", "graphics/shaders/colorFragment.glsl"},
+}
+
+// ShaderError is a compile or link error of a shader, located in GLSL files.
+type ShaderError struct {
+	File    string
+	Line    int // 0 if it is unknown, such as errors of linking.
+	Message string
+}
+
+// ShaderErrors are errors of a shader program reported by the driver.
+type ShaderErrors []ShaderError
+
+// BuiltinShader returns the name, vertex and fragment files of the builtin shader of a layout, files are relative to the root of the engine.
+func BuiltinShader(layout ShaderLayout) (name string, vert string, frag string) {
+	files := builtinShaderFiles[layout]
+	return builtinShaders[layout], files[0], files[1]
+}
+
+// LoadShader compiles GLSL files into a program of the layout, and registers it with a name. It must be called on the render thread.
+// Errors are reported with files and lines instead of panicking, and the shader is drawn as the builtin one of its layout until it is fixed.
+func LoadShader(name string, vert string, frag string, layout ShaderLayout) *Shader {
+	s := GLNewShader(name, 0, GLNewVAO(1), layout.attributeFunc())
+	s.vertFile = vert
+	s.fragFile = frag
+	s.layout = layout
+	s.vertModTime, s.fragModTime = shaderModTime(vert), shaderModTime(frag)
+	s.shader, s.err = GLPrepareShaderProgram(vert, frag)
+	if s.err != nil {
+		shaderLogger.Errorf("shader %s failed to compile:\n%v", name, s.err)
+	}
+	return s
+}
+
+// GetShader gets a shader. Will panic if it is not found.
+func GetShader(name string) *Shader {
+	s, ok := shaderMap[name]
+	if !ok {
+		panic(fmt.Sprintf("shader %s not found", name))
+	}
+	return s
+}
+
+// SetShaderHotReload enables or disables hot reloading of shaders.
+func SetShaderHotReload(enabled bool) {
+	shaderHotReload = enabled
+}
+
+// ReloadModifiedShaders recompiles shaders whose files have been modified since they were compiled, if hot reloading is enabled.
+// A shader keeps its last working program if the new one fails to compile. It must be called on the render thread.
+func ReloadModifiedShaders() {
+	if !shaderHotReload {
+		return
+	}
+	for name, s := range shaderMap {
+		if s.vertFile == "" {
+			continue
+		}
+		vertModTime, fragModTime := shaderModTime(s.vertFile), shaderModTime(s.fragFile)
+		if vertModTime.Equal(s.vertModTime) && fragModTime.Equal(s.fragModTime) {
+			continue
+		}
+		s.vertModTime, s.fragModTime = vertModTime, fragModTime
+		program, err := GLPrepareShaderProgram(s.vertFile, s.fragFile)
+		if err != nil {
+			if s.shader == 0 {
+				s.err = err
+			}
+			shaderLogger.Errorf("shader %s failed to reload:\n%v", name, err)
+			continue
+		}
+		if s.shader != 0 {
+			gl.DeleteProgram(s.shader)
+		}
+		s.shader, s.err = program, nil
+		shaderLogger.Infof("shader %s reloaded", name)
+	}
+}
+
+// Err returns errors of the last compilation, nil if the shader works.
+func (s *Shader) Err() error {
+	return s.err
+}
+
+// program returns the program to draw with, which is the builtin one of the layout if the shader failed to compile.
+func (s *Shader) program() uint32 {
+	if s.err == nil {
+		return s.shader
+	}
+	if builtin, ok := shaderMap[builtinShaders[s.layout]]; ok && builtin.err == nil {
+		return builtin.shader
+	}
+	return 0
+}
+
+func (l ShaderLayout) attributeFunc() func(program uint32) {
+	if l == ShaderLayout_Color {
+		return colorShaderAttributes
+	}
+	return spriteShaderAttributes
+}
+
+func spriteShaderAttributes(program uint32) {
+	// process uniform
+	textureUniform := gl.GetUniformLocation(program, gl.Str("tex\x00"))
+	gl.Uniform1i(textureUniform, 0)
+	// process input
+	// -- position
+	aPos := uint32(gl.GetAttribLocation(program, gl.Str("aPos\x00")))
+	gl.VertexAttribPointerWithOffset(aPos, 3, gl.DOUBLE, false, batchVertexStride*8, 0)
+	gl.EnableVertexAttribArray(aPos)
+	// -- uv
+	texcoord := uint32(gl.GetAttribLocation(program, gl.Str("vertTexCoord\x00")))
+	gl.EnableVertexAttribArray(texcoord)
+	gl.VertexAttribPointerWithOffset(texcoord, 2, gl.DOUBLE, false, batchVertexStride*8, 3*8)
+	// -- tint
+	tint := uint32(gl.GetAttribLocation(program, gl.Str("vertTint\x00")))
+	gl.EnableVertexAttribArray(tint)
+	gl.VertexAttribPointerWithOffset(tint, 4, gl.DOUBLE, false, batchVertexStride*8, 5*8)
+}
+
+func colorShaderAttributes(program uint32) {
+	// process input
+	// -- position
+	aPos := uint32(gl.GetAttribLocation(program, gl.Str("aPos\x00")))
+	gl.VertexAttribPointerWithOffset(aPos, 3, gl.DOUBLE, false, 7*8, 0)
+	gl.EnableVertexAttribArray(aPos)
+	// -- color
+	color := uint32(gl.GetAttribLocation(program, gl.Str("inputColor\x00")))
+	gl.EnableVertexAttribArray(color)
+	gl.VertexAttribPointerWithOffset(color, 4, gl.DOUBLE, false, 7*8, 3*8)
+}
+
+// setBuiltinUniforms sets uniforms provided to every shader, which are skipped if the program does not declare them.
+// time is seconds since the engine started.
+func setBuiltinUniforms(program uint32) {
+	if loc := gl.GetUniformLocation(program, gl.Str("time\x00")); loc >= 0 {
+		gl.Uniform1f(loc, float32(time.Since(shaderStartTime).Seconds()))
+	}
+}
+
+// shaderModTime returns when a file was modified, zero if it could not be read.
+func shaderModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// parseShaderLog locates errors in the info log of the driver, lines in unknown formats are kept without line numbers.
+func parseShaderLog(file string, log string) (ret ShaderErrors) {
+	for _, line := range strings.Split(strings.TrimRight(log, "\x00"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if match := shaderLogLine.FindStringSubmatch(line); match != nil {
+			lineNumber, _ := strconv.Atoi(match[1])
+			ret = append(ret, ShaderError{File: file, Line: lineNumber, Message: match[2]})
+			continue
+		}
+		ret = append(ret, ShaderError{File: file, Message: line})
+	}
+	if len(ret) == 0 {
+		ret = append(ret, ShaderError{File: file, Message: "unknown error"})
+	}
+	return ret
+}
+
+func (e ShaderError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+}
+
+func (e ShaderErrors) Error() string {
+	lines := make([]string, 0, len(e))
+	for _, err := range e {
+		lines = append(lines, err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
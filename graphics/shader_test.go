@@ -0,0 +1,36 @@
+package graphics
+
+import (
+	"testing"
+
+	"galaxyzeta.io/engine/infra/require"
+)
+
+func TestParseShaderLog(t *testing.T) {
+	cases := []struct {
+		driver  string
+		log     string
+		line    int
+		message string
+	}{
+		{"mesa", "0:12(5): error: `color' undeclared", 12, "error: `color' undeclared"},
+		{"nvidia", "0(12) : error C1008: undefined variable \"color\"", 12, "error C1008: undefined variable \"color\""},
+		{"apple/amd", "ERROR: 0:12: Use of undeclared identifier 'color'", 12, "Use of undeclared identifier 'color'"},
+		{"amd warning", "WARNING: 0:3: extension not supported", 3, "extension not supported"},
+		{"unknown", "Vertex info\n", 0, "Vertex info"},
+		{"empty", "\x00", 0, "unknown error"},
+	}
+	for _, c := range cases {
+		errs := parseShaderLog("test.glsl", c.log)
+		t.Log(c.driver, errs)
+		require.EqInt(1, len(errs))
+		require.EqBool(true, errs[0].File == "test.glsl")
+		require.EqInt(c.line, errs[0].Line)
+		require.EqBool(true, errs[0].Message == c.message)
+	}
+
+	// every line of a log is kept in order.
+	errs := parseShaderLog("test.glsl", "0:1(1): error: a\n\n0:2(1): error: b\x00")
+	require.EqInt(2, len(errs))
+	require.EqBool(true, errs.Error() == "test.glsl:1: error: a\ntest.glsl:2: error: b")
+}
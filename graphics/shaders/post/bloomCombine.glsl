@@ -0,0 +1,15 @@
+#version 410 core
+uniform sampler2D tex;
+uniform sampler2D bloomTex;
+uniform float intensity;
+
+in vec2 fragTexCoord;
+in vec4 fragTint;
+
+out vec4 FragColor;
+
+void main() {
+    vec4 scene = texture(tex, fragTexCoord);
+    vec3 bloom = texture(bloomTex, fragTexCoord).rgb;
+    FragColor = vec4(scene.rgb + bloom * intensity, scene.a) * fragTint;
+}
\ No newline at end of file
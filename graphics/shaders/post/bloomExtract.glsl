@@ -0,0 +1,15 @@
+#version 410 core
+uniform sampler2D tex;
+uniform float threshold;
+
+in vec2 fragTexCoord;
+in vec4 fragTint;
+
+out vec4 FragColor;
+
+// keeps the part of colors brighter than threshold.
+void main() {
+    vec4 scene = texture(tex, fragTexCoord);
+    vec3 bright = max(scene.rgb - threshold, 0.0) / max(1.0 - threshold, 0.0001);
+    FragColor = vec4(bright, 1.0);
+}
\ No newline at end of file
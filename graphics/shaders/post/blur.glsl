@@ -0,0 +1,20 @@
+#version 410 core
+uniform sampler2D tex;
+uniform vec2 resolution;
+uniform vec2 direction;
+
+in vec2 fragTexCoord;
+in vec4 fragTint;
+
+out vec4 FragColor;
+
+// gaussian blur of 9 taps along direction in pixels, which are folded into 5 samples by linear filtering.
+void main() {
+    vec2 step = direction / resolution;
+    vec4 sum = texture(tex, fragTexCoord) * 0.2270270270;
+    sum += texture(tex, fragTexCoord + step * 1.3846153846) * 0.3162162162;
+    sum += texture(tex, fragTexCoord - step * 1.3846153846) * 0.3162162162;
+    sum += texture(tex, fragTexCoord + step * 3.2307692308) * 0.0702702703;
+    sum += texture(tex, fragTexCoord - step * 3.2307692308) * 0.0702702703;
+    FragColor = sum;
+}
\ No newline at end of file
@@ -0,0 +1,19 @@
+#version 410 core
+uniform sampler2D tex;
+uniform float brightness;
+uniform float contrast;
+uniform float saturation;
+
+in vec2 fragTexCoord;
+in vec4 fragTint;
+
+out vec4 FragColor;
+
+void main() {
+    vec4 color = texture(tex, fragTexCoord);
+    vec3 rgb = color.rgb + brightness;
+    rgb = (rgb - 0.5) * contrast + 0.5;
+    float luma = dot(rgb, vec3(0.2126, 0.7152, 0.0722));
+    rgb = mix(vec3(luma), rgb, saturation);
+    FragColor = vec4(clamp(rgb, 0.0, 1.0), color.a) * fragTint;
+}
\ No newline at end of file
@@ -0,0 +1,14 @@
+#version 410 core
+uniform sampler2D tex;
+uniform float amount;
+uniform vec4 color;
+
+in vec2 fragTexCoord;
+in vec4 fragTint;
+
+out vec4 FragColor;
+
+void main() {
+    vec4 scene = texture(tex, fragTexCoord);
+    FragColor = vec4(mix(scene.rgb, color.rgb, amount * color.a), scene.a) * fragTint;
+}
\ No newline at end of file
@@ -0,0 +1,16 @@
+#version 410 core
+uniform sampler2D tex;
+uniform vec2 resolution;
+uniform float pixelSize;
+
+in vec2 fragTexCoord;
+in vec4 fragTint;
+
+out vec4 FragColor;
+
+// samples the center of the block a pixel belongs to.
+void main() {
+    vec2 block = max(pixelSize, 1.0) / resolution;
+    vec2 uv = (floor(fragTexCoord / block) + 0.5) * block;
+    FragColor = texture(tex, uv) * fragTint;
+}
\ No newline at end of file
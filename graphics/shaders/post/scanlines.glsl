@@ -0,0 +1,26 @@
+#version 410 core
+uniform sampler2D tex;
+uniform vec2 resolution;
+uniform float intensity;
+uniform float lineHeight;
+uniform float curvature;
+
+in vec2 fragTexCoord;
+in vec4 fragTint;
+
+out vec4 FragColor;
+
+void main() {
+    vec2 uv = fragTexCoord;
+    // bends the screen like a tube, areas out of the screen are black.
+    vec2 centered = uv * 2.0 - 1.0;
+    centered *= 1.0 + curvature * dot(centered.yx, centered.yx);
+    uv = centered * 0.5 + 0.5;
+    if (uv.x < 0.0 || uv.x > 1.0 || uv.y < 0.0 || uv.y > 1.0) {
+        FragColor = vec4(0.0, 0.0, 0.0, 1.0);
+        return;
+    }
+    vec4 scene = texture(tex, uv);
+    float line = 0.5 + 0.5 * sin(uv.y * resolution.y * 3.14159265 / max(lineHeight, 1.0));
+    FragColor = vec4(scene.rgb * (1.0 - intensity * line), scene.a) * fragTint;
+}
\ No newline at end of file
@@ -0,0 +1,21 @@
+#version 410 core
+uniform sampler2D tex;
+uniform vec2 resolution;
+uniform float intensity;
+uniform float radius;
+uniform float softness;
+uniform vec4 color;
+
+in vec2 fragTexCoord;
+in vec4 fragTint;
+
+out vec4 FragColor;
+
+// distances are relative to the height of the screen, so that the vignette stays round.
+void main() {
+    vec4 scene = texture(tex, fragTexCoord);
+    vec2 d = fragTexCoord - 0.5;
+    d.x *= resolution.x / resolution.y;
+    float covered = smoothstep(radius - softness, radius, length(d)) * intensity * color.a;
+    FragColor = vec4(mix(scene.rgb, color.rgb, covered), scene.a) * fragTint;
+}
\ No newline at end of file
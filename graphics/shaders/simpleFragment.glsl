@@ -2,9 +2,10 @@
 uniform sampler2D tex;
 
 in vec2 fragTexCoord;
+in vec4 fragTint;
 
 out vec4 FragColor;
 
 void main() {
-    FragColor = texture(tex, fragTexCoord);
+    FragColor = texture(tex, fragTexCoord) * fragTint;
 }
\ No newline at end of file
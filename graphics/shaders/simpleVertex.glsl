@@ -2,11 +2,14 @@
 
 in vec3 aPos;
 in vec2 vertTexCoord;
+in vec4 vertTint;
 
 out vec2 fragTexCoord;
+out vec4 fragTint;
 
 void main()
 {
     fragTexCoord = vertTexCoord;
+    fragTint = vertTint;
     gl_Position = vec4(aPos.x, aPos.y, aPos.z, 1.0);
 }
\ No newline at end of file
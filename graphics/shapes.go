@@ -6,45 +6,59 @@ import (
 	"github.com/go-gl/gl/v4.1-core/gl"
 )
 
+// DrawRectangle draws the outline of a rectangle in world space.
 func DrawRectangle(rect physics.Rectangle, color linalg.RgbaF64) {
-	cam := GetCurrentCamera()
-	vertices := []float64{
-		rect.Left, rect.Top, 0, color.X, color.Y, color.Z, color.W,
-		rect.Left, rect.Top + rect.Height, 0, color.X, color.Y, color.Z, color.W,
-		rect.Left + rect.Width, rect.Top + rect.Height, 0, color.X, color.Y, color.Z, color.W,
-		rect.Left + rect.Width, rect.Top, 0, color.X, color.Y, color.Z, color.W,
-	}
-	linalg.WorldVertice2OpenGL(&vertices, 0, 7, cam.pos, cam.resolution, GetScreenResolution())
-
 	gl.Enable(gl.BLEND)
-	GLEnableWireframe()
 	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
 	GLDeactivateTexture()
+	DrawPolygon([]linalg.Vector2f64{
+		linalg.NewVector2f64(rect.Left, rect.Top),
+		linalg.NewVector2f64(rect.Left, rect.Top+rect.Height),
+		linalg.NewVector2f64(rect.Left+rect.Width, rect.Top+rect.Height),
+		linalg.NewVector2f64(rect.Left+rect.Width, rect.Top),
+	}, color)
+	gl.Disable(gl.BLEND)
+}
+
+func DrawSegment(segment linalg.Segmentf64, color linalg.RgbaF64) {
+	cam := GetRenderingCamera()
+	vertices := []float64{
+		segment.Point1.X, segment.Point1.Y, 0, color.X, color.Y, color.Z, color.W,
+		segment.Point2.X, segment.Point2.Y, 0, color.X, color.Y, color.Z, color.W,
+	}
 
 	vbo := vboManager.Borrow()
 
+	linalg.MulVertices(&vertices, 0, 7, cam.ViewMat3())
 	GLBindData(vbo, vertices, len(vertices)*8, gl.DYNAMIC_DRAW)
 	GLActivateShader("color")
-	gl.DrawArrays(gl.QUADS, 0, 4)
-	gl.Disable(gl.BLEND)
-	GLDisableWireFrame()
+	gl.DrawArrays(gl.LINES, 0, 2)
 
 	vboManager.Release(vbo)
 }
 
-func DrawSegment(segment linalg.Segmentf64, color linalg.RgbaF64) {
-	cam := GetCurrentCamera()
-	vertices := []float64{
-		segment.Point1.X, segment.Point1.Y, 0, color.X, color.Y, color.Z, color.W,
-		segment.Point2.X, segment.Point2.Y, 0, color.X, color.Y, color.Z, color.W,
+// DrawPolygon draws the outline of a polygon with world vertices.
+func DrawPolygon(polygon []linalg.Vector2f64, color linalg.RgbaF64) {
+	if len(polygon) < 2 {
+		return
+	}
+	cam := GetRenderingCamera()
+	vertices := make([]float64, 0, len(polygon)*7)
+	for _, v := range polygon {
+		vertices = append(vertices, v.X, v.Y, 0, color.X, color.Y, color.Z, color.W)
 	}
 
 	vbo := vboManager.Borrow()
 
-	linalg.WorldVertice2OpenGL(&vertices, 0, 7, cam.pos, cam.resolution, GetScreenResolution())
+	linalg.MulVertices(&vertices, 0, 7, cam.ViewMat3())
 	GLBindData(vbo, vertices, len(vertices)*8, gl.DYNAMIC_DRAW)
 	GLActivateShader("color")
-	gl.DrawArrays(gl.LINES, 0, 2)
+	gl.DrawArrays(gl.LINE_LOOP, 0, int32(len(polygon)))
 
 	vboManager.Release(vbo)
 }
+
+// DrawCircle draws the outline of a circle in world space.
+func DrawCircle(center linalg.Vector2f64, radius float64, color linalg.RgbaF64) {
+	DrawPolygon(physics.NewCircle(nil, center.X-radius, center.Y-radius, radius).ToPolygon().GetWorldVertices(), color)
+}
@@ -0,0 +1,70 @@
+package graphics
+
+import (
+	"fmt"
+	"image"
+)
+
+// SheetGrid slices a sprite sheet into cells of same size, from left to right and then top to bottom.
+// Cells are named by Name followed by their index, which starts from Start.
+type SheetGrid struct {
+	Name     string
+	CellW    int
+	CellH    int
+	MarginX  int // pixels between the sheet border and the first cell.
+	MarginY  int
+	SpacingX int // pixels between cells.
+	SpacingY int
+	Count    int // number of cells to take, all cells if 0.
+	Start    int
+}
+
+// Slice cuts cells out of the sheet, cells not entirely inside the sheet are dropped.
+func (grid SheetGrid) Slice(sheet image.Image) (names []string, images []image.Image) {
+	if grid.CellW <= 0 || grid.CellH <= 0 {
+		panic("cell size of a sheet grid must be positive")
+	}
+	bounds := sheet.Bounds()
+	idx := 0
+	for y := bounds.Min.Y + grid.MarginY; y+grid.CellH <= bounds.Max.Y; y += grid.CellH + grid.SpacingY {
+		for x := bounds.Min.X + grid.MarginX; x+grid.CellW <= bounds.Max.X; x += grid.CellW + grid.SpacingX {
+			if grid.Count > 0 && idx >= grid.Count {
+				return names, images
+			}
+			names = append(names, fmt.Sprintf("%s%d", grid.Name, grid.Start+idx))
+			images = append(images, subImage(sheet, image.Rect(x, y, x+grid.CellW, y+grid.CellH)))
+			idx++
+		}
+	}
+	if grid.Count > idx {
+		panic(fmt.Sprintf("sheet grid %s wants %d cells, but only %d fit in the sheet", grid.Name, grid.Count, idx))
+	}
+	return names, images
+}
+
+// NewSheetFrames slices a sprite sheet by grids and named regions, packs slices into atlas pages,
+// and registers them to frameMap with a naming strategy. Regions are in pixels of the sheet.
+// If an error occurs, will panic.
+func NewSheetFrames(sheetFile string, grids []SheetGrid, regions []AtlasRegion, namingFunc func(string) string) {
+	sheet, err := ReadPng(sheetFile)
+	if err != nil {
+		panic(err)
+	}
+	var names []string
+	var images []image.Image
+	for _, grid := range grids {
+		gridNames, gridImages := grid.Slice(sheet)
+		names = append(names, gridNames...)
+		images = append(images, gridImages...)
+	}
+	bounds := sheet.Bounds()
+	for _, region := range regions {
+		rect := image.Rect(region.X, region.Y, region.X+region.W, region.Y+region.H).Add(bounds.Min)
+		if rect.Empty() || !rect.In(bounds) {
+			panic(fmt.Sprintf("region %s is outside of sheet %s", region.Name, sheetFile))
+		}
+		names = append(names, region.Name)
+		images = append(images, subImage(sheet, rect))
+	}
+	registerPackedFrames(names, images, namingFunc)
+}
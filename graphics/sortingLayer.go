@@ -0,0 +1,63 @@
+package graphics
+
+import (
+	"fmt"
+
+	"galaxyzeta.io/engine/infra/constdef/layer"
+)
+
+const (
+	SortingLayerBackground = "background"
+	SortingLayerDefault    = "default"
+	SortingLayerForeground = "foreground"
+)
+
+// SortingLayer groups renderables by drawing order. Renderables on a sorting layer of higher order are drawn over those of lower order,
+// and renderables on the same sorting layer are drawn by Z, their order in layer.
+type SortingLayer struct {
+	Name  string
+	Order int
+}
+
+var sortingLayerMap = map[string]SortingLayer{
+	SortingLayerBackground: {Name: SortingLayerBackground, Order: layer.Layer_BG},
+	SortingLayerDefault:    {Name: SortingLayerDefault, Order: layer.Layer_Default},
+	SortingLayerForeground: {Name: SortingLayerForeground, Order: layer.Layer_FG},
+}
+
+// ISortingLayered is implemented by renderables which could be put on a sorting layer, others are on the default sorting layer.
+type ISortingLayered interface {
+	GetSortingLayer() SortingLayer
+}
+
+// SortingLayerOf returns the sorting layer of a renderable.
+func SortingLayerOf(ren IRenderable) SortingLayer {
+	if layered, ok := ren.(ISortingLayered); ok {
+		return layered.GetSortingLayer()
+	}
+	return sortingLayerMap[SortingLayerDefault]
+}
+
+// RegisterSortingLayer names a sorting layer of order, which could be used by level files.
+// Renderables keep the order their sorting layers had when they were put on them.
+func RegisterSortingLayer(name string, order int) {
+	sortingLayerMap[name] = SortingLayer{Name: name, Order: order}
+}
+
+// GetSortingLayer gets a sorting layer by name. Will panic if it is not found.
+func GetSortingLayer(name string) SortingLayer {
+	sortingLayer, ok := sortingLayerMap[name]
+	if !ok {
+		panic(fmt.Sprintf("sorting layer %s not found", name))
+	}
+	return sortingLayer
+}
+
+// DrawsBefore tells whether a is drawn before b, which is by orders of sorting layers and then by Z.
+func DrawsBefore(a IRenderable, b IRenderable) bool {
+	orderA, orderB := SortingLayerOf(a).Order, SortingLayerOf(b).Order
+	if orderA != orderB {
+		return orderA < orderB
+	}
+	return a.Z() < b.Z()
+}
@@ -1,7 +1,9 @@
 package graphics
 
 import (
+	"fmt"
 	"image"
+	"sync"
 	"time"
 
 	"galaxyzeta.io/engine/linalg"
@@ -11,30 +13,65 @@ import (
 
 type SpriteInstance struct {
 	AnimationController
-	frames SpriteMeta
-	vbo    uint32
+	name   string // name of the sprite meta.
+	frames []*GLFrame
+	SpriteMetaOptions
 }
 
 type RenderOptions struct {
-	Scale *linalg.Vector2f64
-	Pivot *physics.Pivot
+	Scale        *linalg.Vector2f64
+	Pivot        *physics.Pivot
+	Material     *Material
+	Layer        int
+	SortingLayer *SortingLayer // the default sorting layer if nil.
+	Z            int64         // order in the sorting layer.
 }
 
 // SpriteMeta is a sequence of frames that consists of an playable animation.
-type SpriteMeta []*GLFrame
+type SpriteMeta struct {
+	Frames []*GLFrame
+	SpriteMetaOptions
+}
+
+// PlayMode decides what happens when an animation reaches its end.
+type PlayMode uint8
+
+const (
+	PlayMode_Loop         PlayMode = iota // starts over.
+	PlayMode_Once                         // finishes, stops and rewinds to the first frame.
+	PlayMode_PingPong                     // plays back and forth.
+	PlayMode_ClampForever                 // finishes and holds the last frame, normalized time keeps growing.
+)
+
+// SpriteMetaOptions describes how frames of a sprite meta are played.
+type SpriteMetaOptions struct {
+	Durations []time.Duration  // duration of each frame, nil or zero to use the update interval of instances.
+	Mode      PlayMode         // loops by default.
+	Reverse   bool             // plays from the last frame to the first one.
+	Events    map[int][]string // names of events fired when a frame is shown, keyed by frame index.
+}
 
-// GLFrame is a single img included in Sprite object.
+// GLFrame is a single img included in Sprite object, which is a region of an atlas page.
 type GLFrame struct {
-	img       image.Image
-	glTexture uint32
+	img  image.Image // pixels of the frame, shared with the page.
+	page *AtlasPage
+	uv   linalg.Rect // region of the page in texture coordinates.
+
+	boxes     *FrameBoxes // authored, or generated from alpha channel once needed.
+	boxesOnce sync.Once
 }
 
-// AnimationController controls the single animation of a sprite instance.
+// AnimationController controls the single animation of a sprite instance. It is advanced by the game clock.
 type AnimationController struct {
 	currentFrame   int
 	updateInterval time.Duration
-	lastUpdateTime time.Time
 	isPlaying      bool
+	speed          float64       // playback speed, 1 is normal.
+	frameElapsed   time.Duration // time spent on current frame.
+	played         time.Duration // time played since the animation restarted, which is scaled by speed.
+	direction      int           // 1 or -1, which is flipped by ping-pong.
+	shown          bool          // whether events of current frame have been fired.
+	finished       bool          // whether a once or clamp-forever animation has reached its end.
 }
 
 func (spr *SpriteInstance) GetImg() image.Image {
@@ -56,16 +93,31 @@ func NewFrame(name string, frameFile string) *GLFrame {
 	return ret
 }
 
-// newGLFrame creates a new frame and register texture into it.
+// newGLFrame creates a new frame owning a whole page.
 func newGLFrame(img image.Image) (ret *GLFrame) {
-	ret = &GLFrame{
-		img: img,
+	return &GLFrame{
+		img:  img,
+		page: newAtlasPage(img),
+		uv:   FullUV,
 	}
-	GLRegisterTexture(img, &ret.glTexture)
-	return ret
 }
 
-// BatchNewFrames read all pngs under a certain directory, and register them
+// GetImg returns pixels of the frame.
+func (frame *GLFrame) GetImg() image.Image {
+	return frame.img
+}
+
+// GetPage returns the atlas page holding the frame.
+func (frame *GLFrame) GetPage() *AtlasPage {
+	return frame.page
+}
+
+// GetUV returns the region of the page in texture coordinates.
+func (frame *GLFrame) GetUV() linalg.Rect {
+	return frame.uv
+}
+
+// BatchNewFrames read all pngs under a certain directory, packs them into atlas pages, and register them
 // to frameMap with a naming strategy.
 // If an error occurs, will panic.
 func BatchNewFrames(dirPath string, nameingFunc func(string) string) {
@@ -73,93 +125,124 @@ func BatchNewFrames(dirPath string, nameingFunc func(string) string) {
 	if err != nil {
 		panic(err)
 	}
-	// fileNames and images has same length
-	for i := 0; i < len(fileNames); i++ {
-		frameMap[nameingFunc(fileNames[i])] = newGLFrame(images[i])
-	}
+	registerPackedFrames(fileNames, images, nameingFunc)
 }
 
 // NewSpriteMeta creates a new sprite meta from given sprite names.
 func NewSpriteMeta(name string, frameNames ...string) {
-	ret := make(SpriteMeta, len(frameNames))
-	for idx := range ret {
-		ret[idx] = GetFrame(frameNames[idx])
+	NewSpriteMetaWithDurations(name, frameNames, nil)
+}
+
+// NewSpriteMetaWithDurations creates a new sprite meta from given sprite names, each frame lasts for its own duration.
+// Durations should have same length with frame names, or be nil to use the update interval of instances.
+func NewSpriteMetaWithDurations(name string, frameNames []string, durations []time.Duration) {
+	NewSpriteMetaWithOptions(name, frameNames, SpriteMetaOptions{Durations: durations})
+}
+
+// NewSpriteMetaWithOptions creates a new sprite meta from given sprite names, which is played as options describe.
+func NewSpriteMetaWithOptions(name string, frameNames []string, opts SpriteMetaOptions) {
+	if opts.Durations != nil && len(opts.Durations) != len(frameNames) {
+		panic("frame names and durations should have same length")
+	}
+	for frame := range opts.Events {
+		if frame < 0 || frame >= len(frameNames) {
+			panic(fmt.Sprintf("sprite %s has events on frame %d, which does not exist", name, frame))
+		}
+	}
+	ret := SpriteMeta{
+		Frames:            make([]*GLFrame, len(frameNames)),
+		SpriteMetaOptions: opts,
+	}
+	for idx := range ret.Frames {
+		ret.Frames[idx] = GetFrame(frameNames[idx])
 	}
 	spriteMetaMap[name] = ret
 }
 
 // NewSpriteInstance creates a new sprite.
 func NewSpriteInstance(sprMetaName string) (spr *SpriteInstance) {
+	meta := GetSpriteMeta(sprMetaName)
 	ret := &SpriteInstance{
-		vbo:    vboManager.Borrow(),
-		frames: GetSpriteMeta(sprMetaName),
+		name:              sprMetaName,
+		frames:            meta.Frames,
+		SpriteMetaOptions: meta.SpriteMetaOptions,
 		AnimationController: AnimationController{
-			currentFrame:   0,
 			updateInterval: time.Millisecond * 200,
-			lastUpdateTime: time.Now(),
 			isPlaying:      true,
+			speed:          1,
 		},
 	}
+	ret.Restart()
 	return ret
 }
 
-func (spr *SpriteInstance) getRenderVertices(pos linalg.Vector2f64, renderOptions ...RenderOptions) []float64 {
+// GetName returns the name of the sprite meta.
+func (spr *SpriteInstance) GetName() string {
+	return spr.name
+}
+
+// getRenderCorners returns the 4 corners of current frame transformed by m, clockwise from the top-left one.
+// The pivot of the frame is placed on the origin of the local space.
+func (spr *SpriteInstance) getRenderCorners(m linalg.Mat3, pivot *physics.Pivot) [4]linalg.Vector2f64 {
 	currentGLImg := spr.frames[spr.currentFrame]
 	dx := float64(currentGLImg.img.Bounds().Dx())
 	dy := float64(currentGLImg.img.Bounds().Dy())
-	var sx float64 = 1
-	var sy float64 = 1
 	var offset linalg.Vector2f64
-	if len(renderOptions) != 0 {
-		if scale := renderOptions[0].Scale; scale != nil {
-			sx = renderOptions[0].Scale.X
-			sy = renderOptions[0].Scale.Y
-		}
-		if anchorOption := renderOptions[0].Pivot; anchorOption != nil {
-			if fixedPoint := renderOptions[0].Pivot.Point; fixedPoint != nil {
-				offset = linalg.NewVector2f64(fixedPoint.X, fixedPoint.Y)
-			} else {
-				offset = anchorOption.Option.GetPivotPoint(physics.NewBoundingBox(
-					linalg.NewVector2f64(dx, 0),
-					linalg.NewVector2f64(0, 0),
-					linalg.NewVector2f64(0, dy),
-					linalg.NewVector2f64(dx, dy),
-				))
-				offset.X = -offset.X
-				offset.Y = -offset.Y
-			}
+	if pivot != nil {
+		if fixedPoint := pivot.Point; fixedPoint != nil {
+			offset = linalg.NewVector2f64(fixedPoint.X, fixedPoint.Y)
+		} else {
+			offset = pivot.Option.GetPivotPoint(physics.NewBoundingBox(
+				linalg.NewVector2f64(dx, 0),
+				linalg.NewVector2f64(0, 0),
+				linalg.NewVector2f64(0, dy),
+				linalg.NewVector2f64(dx, dy),
+			))
+			offset.X = -offset.X
+			offset.Y = -offset.Y
 		}
 	}
-	offset2Top := offset.Y * sy
-	offset2Left := offset.X * sx
-	offset2Right := (offset.X + dx) * sx
-	offset2Bot := (offset.Y + dy) * sy
-	return []float64{
-		pos.X + offset2Left, pos.Y + offset2Top, 0, 0, 0,
-		pos.X + offset2Left, pos.Y + offset2Bot, 0, 0, 1,
-		pos.X + offset2Right, pos.Y + offset2Bot, 0, 1, 1,
-		pos.X + offset2Right, pos.Y + offset2Top, 0, 1, 0,
+	return [4]linalg.Vector2f64{
+		m.MulPoint(linalg.NewVector2f64(offset.X, offset.Y)),
+		m.MulPoint(linalg.NewVector2f64(offset.X+dx, offset.Y)),
+		m.MulPoint(linalg.NewVector2f64(offset.X+dx, offset.Y+dy)),
+		m.MulPoint(linalg.NewVector2f64(offset.X, offset.Y+dy)),
 	}
 }
 
-// Render sprite. Sprite must exist.
+// Draw queues current frame into the batch as a quad transformed by m, which is usually the matrix of a transform.
+// The pivot of the frame is placed on the origin of the local space. Sprite must exist.
+func (spr *SpriteInstance) Draw(batch *SpriteBatch, m linalg.Mat3, pivot *physics.Pivot) {
+	spr.DrawWithMaterial(batch, m, pivot, nil)
+}
+
+// DrawWithMaterial queues current frame like Draw, which is drawn with the material. The default shader is used if material is nil.
+func (spr *SpriteInstance) DrawWithMaterial(batch *SpriteBatch, m linalg.Mat3, pivot *physics.Pivot, material *Material) {
+	frame := spr.frames[spr.currentFrame]
+	batch.DrawQuadMaterial(frame.page.glTexture, material, spr.getRenderCorners(m, pivot), frame.uv)
+}
+
+// Render sprite at pos immediately, scale in options is applied. Sprite must exist.
+// Prefer Draw with the batch of Renderer2DSystem when drawing many sprites.
 func (spr *SpriteInstance) Render(camera *Camera, pos linalg.Vector2f64, renderOptions ...RenderOptions) {
-	currentGLImg := spr.frames[spr.currentFrame]
-	vertices := spr.getRenderVertices(pos, renderOptions...)
-	linalg.WorldVertice2OpenGL(&vertices, 0, 5, camera.pos, camera.resolution, GetScreenResolution())
-	// vertices := []float64{
-	// 	-0.5, 0.5, 0, 0, 0,
-	// 	-0.5, -0.5, 0, 0, 1,
-	// 	0.5, -0.5, 0, 1, 1,
-	// 	0.5, 0.5, 0, 1, 0,
-	// }
-	gl.Enable(gl.BLEND)
-	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
-	GLActivateTexture(currentGLImg.glTexture)
-	GLBindData(spr.vbo, vertices, len(vertices)*8, gl.DYNAMIC_DRAW)
-	GLActivateShader("default")
-	gl.DrawArrays(gl.QUADS, 0, 4)
-	gl.Disable(gl.BLEND)
+	scale := linalg.NewVector2f64(1, 1)
+	var pivot *physics.Pivot
+	if len(renderOptions) != 0 {
+		if renderOptions[0].Scale != nil {
+			scale = *renderOptions[0].Scale
+		}
+		pivot = renderOptions[0].Pivot
+	}
+	spr.RenderTransformed(camera, linalg.NewTransformMat3(pos, 0, scale), pivot)
+}
+
+// RenderTransformed renders sprite immediately as a quad transformed by m, which costs a draw call.
+// The pivot of the frame is placed on the origin of the local space. Sprite must exist.
+func (spr *SpriteInstance) RenderTransformed(camera *Camera, m linalg.Mat3, pivot *physics.Pivot) {
+	batch := immediateBatch()
+	batch.Begin(camera)
+	spr.Draw(batch, m, pivot)
+	batch.End()
 }
 
 // Render sprite in wire mode. Sprite must exist.
@@ -173,63 +256,182 @@ func (spr *SpriteInstance) RenderWire(camera *Camera, pos linalg.Vector2f64, col
 		pos.X + dx, pos.Y + dy, 0, color.X, color.Y, color.Z, color.W,
 		pos.X + dx, pos.Y, 0, color.X, color.Y, color.Z, color.W,
 	}
-	linalg.WorldVertice2OpenGL(&vertices, 0, 7, camera.pos, camera.resolution, GetScreenResolution())
+	linalg.MulVertices(&vertices, 0, 7, camera.ViewMat3())
 
+	vbo := vboManager.Borrow()
 	gl.Enable(gl.BLEND)
-	GLEnableWireframe()
 	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
 	GLDeactivateTexture()
-	GLBindData(spr.vbo, vertices, len(vertices)*8, gl.DYNAMIC_DRAW)
+	GLBindData(vbo, vertices, len(vertices)*8, gl.DYNAMIC_DRAW)
 	GLActivateShader("color")
-	gl.DrawArrays(gl.QUADS, 0, 4)
+	gl.DrawArrays(gl.LINE_LOOP, 0, 4)
 	gl.Disable(gl.BLEND)
-	GLDisableWireFrame()
+	vboManager.Release(vbo)
 }
 
+// GetHitbox returns the hitbox of current frame, placed on the anchor by the pivot of the frame.
 func (spr *SpriteInstance) GetHitbox(anchor *linalg.Vector2f64, pivot physics.Pivot) physics.Polygon {
-	maxWidth := -1
-	maxHeight := -1
-	for _, frame := range spr.frames {
-		cx := frame.img.Bounds().Dx()
-		cy := frame.img.Bounds().Dy()
-		if cx > maxWidth {
-			maxWidth = cx
+	frame := spr.frames[spr.currentFrame]
+	return *physics.NewPolygon(anchor, spr.framePivotPoint(frame, pivot), 0, frame.GetBoxes().Hitbox)
+}
+
+// GetHurtboxes returns hurtboxes of current frame, placed on the anchor by the pivot of the frame.
+func (spr *SpriteInstance) GetHurtboxes(anchor *linalg.Vector2f64, pivot physics.Pivot) []physics.Polygon {
+	frame := spr.frames[spr.currentFrame]
+	pivotPoint := spr.framePivotPoint(frame, pivot)
+	hurtboxes := frame.GetBoxes().Hurtboxes
+	ret := make([]physics.Polygon, len(hurtboxes))
+	for idx, vertices := range hurtboxes {
+		ret[idx] = *physics.NewPolygon(anchor, pivotPoint, 0, vertices)
+	}
+	return ret
+}
+
+// CurrentFrame returns the frame being shown.
+func (spr *SpriteInstance) CurrentFrame() *GLFrame {
+	return spr.frames[spr.currentFrame]
+}
+
+// framePivotPoint returns the pivot point of the whole frame, which matches the one used by rendering.
+func (spr *SpriteInstance) framePivotPoint(frame *GLFrame, pivot physics.Pivot) linalg.Vector2f64 {
+	return pivot.GetPointOfBox(float64(frame.img.Bounds().Dx()), float64(frame.img.Bounds().Dy()))
+}
+
+// Advance plays the animation for dt of the game clock, scaled by speed.
+// Returns names of events fired by frames shown, and whether the animation finished during this advance.
+func (spr *SpriteInstance) Advance(dt time.Duration) (events []string, finished bool) {
+	if !spr.isPlaying {
+		return nil, false
+	}
+	if !spr.shown {
+		spr.shown = true
+		events = append(events, spr.Events[spr.currentFrame]...)
+	}
+	scaled := time.Duration(float64(dt) * spr.speed)
+	spr.played += scaled
+	if spr.finished {
+		return events, false
+	}
+	spr.frameElapsed += scaled
+	for d := spr.frameDuration(); d > 0 && spr.frameElapsed >= d; d = spr.frameDuration() {
+		spr.frameElapsed -= d
+		if !spr.nextFrame() {
+			spr.finished = true
+			spr.frameElapsed = 0
+			if spr.Mode == PlayMode_Once {
+				spr.isPlaying = false
+				spr.currentFrame = spr.firstFrame()
+			}
+			return events, true
 		}
-		if cy > maxHeight {
-			maxHeight = cy
+		events = append(events, spr.Events[spr.currentFrame]...)
+	}
+	return events, false
+}
+
+// nextFrame moves to the next frame according to the play mode, returns false if the animation reaches its end.
+func (spr *SpriteInstance) nextFrame() bool {
+	next := spr.currentFrame + spr.direction
+	if next >= 0 && next < len(spr.frames) {
+		spr.currentFrame = next
+		return true
+	}
+	switch spr.Mode {
+	case PlayMode_Loop:
+		spr.currentFrame = spr.firstFrame()
+	case PlayMode_PingPong:
+		// ends are not repeated when bouncing back.
+		spr.direction = -spr.direction
+		if len(spr.frames) > 1 {
+			spr.currentFrame += spr.direction
 		}
+	default:
+		return false
 	}
-	f64w := float64(maxWidth)
-	f64h := float64(maxHeight)
-	retVert := []linalg.Vector2f64{
-		linalg.NewVector2f64(f64w, 0),
-		linalg.NewVector2f64(0, 0),
-		linalg.NewVector2f64(0, f64h),
-		linalg.NewVector2f64(f64w, f64h),
+	return true
+}
+
+func (spr *SpriteInstance) firstFrame() int {
+	if spr.Reverse {
+		return len(spr.frames) - 1
 	}
-	// get pivot point
-	var pivotPoint linalg.Vector2f64
-	if pivot.Point != nil {
-		pivotPoint = *pivot.Point
-	} else {
-		pivotPoint = pivot.Option.GetPivotPoint(physics.SliceToBoundingBox(retVert))
+	return 0
+}
+
+// Restart plays the animation from its first frame, events of the first frame are fired at next advance.
+func (spr *SpriteInstance) Restart() {
+	spr.currentFrame = spr.firstFrame()
+	spr.direction = 1
+	if spr.Reverse {
+		spr.direction = -1
 	}
+	spr.frameElapsed = 0
+	spr.played = 0
+	spr.shown = false
+	spr.finished = false
+	spr.isPlaying = true
+}
 
-	return *physics.NewPolygon(anchor, pivotPoint, 0, retVert)
+// IsFinished tells whether a once or clamp-forever animation has reached its end since it restarted.
+func (spr *SpriteInstance) IsFinished() bool {
+	return spr.finished
 }
 
-func (spr *SpriteInstance) DoFrameStep() {
-	if spr.isPlaying {
-		if time.Since(spr.lastUpdateTime) >= spr.updateInterval {
-			spr.currentFrame += 1
-			if spr.currentFrame >= len(spr.frames) {
-				spr.currentFrame = 0
-			}
-			spr.lastUpdateTime = time.Now()
-		}
+// SetReverse sets whether the animation is played from the last frame to the first one, it takes effect at next restart.
+func (spr *SpriteInstance) SetReverse(reverse bool) *SpriteInstance {
+	spr.Reverse = reverse
+	return spr
+}
+
+// SetPlayMode sets what happens when the animation reaches its end.
+func (spr *SpriteInstance) SetPlayMode(mode PlayMode) *SpriteInstance {
+	spr.Mode = mode
+	return spr
+}
+
+// Length returns how long the animation lasts for one pass at normal speed.
+func (spr *SpriteInstance) Length() (ret time.Duration) {
+	for idx := range spr.frames {
+		ret += spr.durationOf(idx)
+	}
+	return ret
+}
+
+// NormalizedTime returns passes played since the animation restarted, 1.5 means it has been played once and a half.
+func (spr *SpriteInstance) NormalizedTime() float64 {
+	length := spr.Length()
+	if length <= 0 {
+		return 0
+	}
+	return float64(spr.played) / float64(length)
+}
+
+// SetSpeed sets playback speed, 1 is normal and 0 pauses on current frame.
+func (spr *SpriteInstance) SetSpeed(speed float64) *SpriteInstance {
+	if speed < 0 {
+		panic("playback speed should not be negative")
+	}
+	spr.speed = speed
+	return spr
+}
+
+func (spr *SpriteInstance) Speed() float64 {
+	return spr.speed
+}
+
+// frameDuration returns how long current frame lasts.
+func (spr *SpriteInstance) frameDuration() time.Duration {
+	return spr.durationOf(spr.currentFrame)
+}
+
+func (spr *SpriteInstance) durationOf(frame int) time.Duration {
+	if spr.Durations != nil && spr.Durations[frame] > 0 {
+		return spr.Durations[frame]
 	}
+	return spr.updateInterval
 }
 
+// SetUpdateInterval sets how long each frame lasts, frames having their own durations are not affected.
 func (spr *SpriteInstance) SetUpdateInterval(dur time.Duration) *SpriteInstance {
 	spr.updateInterval = dur
 	return spr
@@ -1,11 +1,31 @@
 package graphics
 
-import "sync"
+import (
+	"fmt"
+	"sync"
+	"time"
 
+	"galaxyzeta.io/engine/linalg"
+	"galaxyzeta.io/engine/physics"
+)
+
+// Animator switches animation clips of a sprite renderer by states.
+// In simple mode, states are switched by AlterState manually.
+// An animator created from a state machine also switches states by transitions, which are driven by parameters.
 type Animator struct {
 	mu           sync.RWMutex
 	state2clip   map[string]*SpriteInstance // maps from state name to an animation clip.
 	currentState string
+	machine      *AnimStateMachine  // nil in simple mode.
+	params       map[string]float64 // values of parameters, bools and triggers are 0 or 1.
+	speed        float64            // multiplies playback speed of all clips.
+}
+
+// AnimationEvent is fired by a frame of current clip, or by current clip when it finishes.
+type AnimationEvent struct {
+	Name     string // name of the frame event, empty if Finished.
+	State    string // state playing the clip.
+	Finished bool
 }
 
 type StateClipPair struct {
@@ -18,6 +38,7 @@ func NewAnimator(cfgs ...StateClipPair) (anmt *Animator) {
 		mu:           sync.RWMutex{},
 		state2clip:   map[string]*SpriteInstance{},
 		currentState: cfgs[0].State,
+		speed:        1,
 	}
 	for _, state := range cfgs {
 		anmt.state2clip[state.State] = state.Clip
@@ -25,6 +46,29 @@ func NewAnimator(cfgs ...StateClipPair) (anmt *Animator) {
 	return anmt
 }
 
+// NewAnimatorFromStateMachine creates an animator with its own clips and parameters, starting from the entry state.
+func NewAnimatorFromStateMachine(machine *AnimStateMachine) (anmt *Animator) {
+	if machine.entry == "" {
+		panic("animation state machine has no states")
+	}
+	anmt = &Animator{
+		mu:           sync.RWMutex{},
+		state2clip:   map[string]*SpriteInstance{},
+		currentState: machine.entry,
+		machine:      machine,
+		params:       map[string]float64{},
+		speed:        1,
+	}
+	for _, name := range machine.stateOrder {
+		anmt.state2clip[name] = NewSpriteInstance(machine.states[name].Sprite)
+	}
+	for name, def := range machine.params {
+		anmt.params[name] = def.defaults
+	}
+	anmt.enter(machine.entry)
+	return anmt
+}
+
 func (a *Animator) Spr() (ret *SpriteInstance) {
 	a.mu.RLock()
 	ret = a.state2clip[a.currentState]
@@ -32,12 +76,29 @@ func (a *Animator) Spr() (ret *SpriteInstance) {
 	return
 }
 
+// AlterState switches to a state immediately. A state machine restarts the state.
 func (a *Animator) AlterState(toState string) {
+	// the state machine is never modified, so it is checked before locking, which might panic.
+	if a.machine != nil {
+		a.machine.mustGetState(toState)
+	}
 	a.mu.Lock()
-	a.currentState = toState
+	if a.machine != nil {
+		a.enter(toState)
+	} else {
+		a.currentState = toState
+	}
 	a.mu.Unlock()
 }
 
+// CurrentState returns the name of current state.
+func (a *Animator) CurrentState() (ret string) {
+	a.mu.RLock()
+	ret = a.currentState
+	a.mu.RUnlock()
+	return
+}
+
 func (a *Animator) RegisterState(spr *SpriteInstance, s string) {
 	a.mu.Lock()
 	a.state2clip[s] = spr
@@ -51,3 +112,153 @@ func (a *Animator) RegisterStates(sps ...StateClipPair) {
 	}
 	a.mu.Unlock()
 }
+
+// SetSpeed sets a multiplier of playback speed applied to all clips.
+func (a *Animator) SetSpeed(speed float64) {
+	if speed < 0 {
+		panic("playback speed should not be negative")
+	}
+	a.mu.Lock()
+	a.speed = speed
+	a.mu.Unlock()
+}
+
+// Draw queues current frame of current clip into the batch, see SpriteInstance.Draw.
+func (a *Animator) Draw(batch *SpriteBatch, m linalg.Mat3, pivot *physics.Pivot) {
+	a.DrawWithMaterial(batch, m, pivot, nil)
+}
+
+// DrawWithMaterial queues current frame of current clip like Draw, which is drawn with the material.
+func (a *Animator) DrawWithMaterial(batch *SpriteBatch, m linalg.Mat3, pivot *physics.Pivot, material *Material) {
+	a.mu.RLock()
+	a.state2clip[a.currentState].DrawWithMaterial(batch, m, pivot, material)
+	a.mu.RUnlock()
+}
+
+// Step advances current clip by dt of the game clock, and then takes at most one transition if it is driven by a state machine.
+// Returns events fired by the clip during this step.
+func (a *Animator) Step(dt time.Duration) (ret []AnimationEvent) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	clip := a.state2clip[a.currentState]
+	names, finished := clip.Advance(time.Duration(float64(dt) * a.speed))
+	for _, name := range names {
+		ret = append(ret, AnimationEvent{Name: name, State: a.currentState})
+	}
+	if finished {
+		ret = append(ret, AnimationEvent{State: a.currentState, Finished: true})
+	}
+	if a.machine == nil {
+		return ret
+	}
+	for _, t := range a.machine.transitions {
+		if t.From != "" && t.From != a.currentState {
+			continue
+		}
+		if t.From == "" && !t.ToSelf && t.To == a.currentState {
+			continue
+		}
+		if t.HasExitTime && clip.NormalizedTime() < t.ExitTime {
+			continue
+		}
+		if !a.satisfies(t.Conditions) {
+			continue
+		}
+		// triggers are consumed by the transition taken.
+		for _, cond := range t.Conditions {
+			if a.machine.params[cond.Param].kind == AnimParam_Trigger {
+				a.params[cond.Param] = 0
+			}
+		}
+		a.enter(t.To)
+		return ret
+	}
+	return ret
+}
+
+func (a *Animator) satisfies(conds []AnimCondition) bool {
+	for _, cond := range conds {
+		v := a.params[cond.Param]
+		switch cond.Mode {
+		case AnimCondition_If:
+			if v == 0 {
+				return false
+			}
+		case AnimCondition_IfNot:
+			if v != 0 {
+				return false
+			}
+		case AnimCondition_Greater:
+			if v <= cond.Threshold {
+				return false
+			}
+		case AnimCondition_Less:
+			if v >= cond.Threshold {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// enter restarts a state with its playback speed.
+func (a *Animator) enter(state string) {
+	a.currentState = state
+	clip := a.state2clip[state]
+	clip.Restart()
+	clip.SetSpeed(a.machine.states[state].Speed)
+}
+
+// +------------------------+
+// |	  	Parameters	 	|
+// +------------------------+
+
+func (a *Animator) SetBool(name string, value bool) {
+	a.setParam(name, AnimParam_Bool, boolToParam(value))
+}
+
+func (a *Animator) GetBool(name string) bool {
+	return a.getParam(name, AnimParam_Bool) != 0
+}
+
+func (a *Animator) SetFloat(name string, value float64) {
+	a.setParam(name, AnimParam_Float, value)
+}
+
+func (a *Animator) GetFloat(name string) float64 {
+	return a.getParam(name, AnimParam_Float)
+}
+
+// SetTrigger sets a trigger, it stays set until a transition consumes it or it is reset.
+func (a *Animator) SetTrigger(name string) {
+	a.setParam(name, AnimParam_Trigger, 1)
+}
+
+func (a *Animator) ResetTrigger(name string) {
+	a.setParam(name, AnimParam_Trigger, 0)
+}
+
+func (a *Animator) setParam(name string, kind AnimParamType, value float64) {
+	a.mustCheckParam(name, kind)
+	a.mu.Lock()
+	a.params[name] = value
+	a.mu.Unlock()
+}
+
+func (a *Animator) getParam(name string, kind AnimParamType) (ret float64) {
+	a.mustCheckParam(name, kind)
+	a.mu.RLock()
+	ret = a.params[name]
+	a.mu.RUnlock()
+	return
+}
+
+// mustCheckParam panics if the parameter is not defined by the state machine, it needs no lock.
+func (a *Animator) mustCheckParam(name string, kind AnimParamType) {
+	if a.machine == nil {
+		panic("animator in simple mode has no parameters")
+	}
+	if def, ok := a.machine.params[name]; !ok || def.kind != kind {
+		panic(fmt.Sprintf("animation parameter %s of type %d not found", name, kind))
+	}
+}
@@ -0,0 +1,148 @@
+package graphics
+
+import (
+	"fmt"
+	"image"
+	"testing"
+	"time"
+
+	"galaxyzeta.io/engine/infra/require"
+)
+
+// newTestSprite registers a sprite meta of count frames, which are 1*1 images without GL textures.
+func newTestSprite(name string, count int, opts SpriteMetaOptions) {
+	names := make([]string, count)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s_%d", name, i)
+		frameMap[names[i]] = &GLFrame{img: image.NewRGBA(image.Rect(0, 0, 1, 1))}
+	}
+	NewSpriteMetaWithOptions(name, names, opts)
+}
+
+// panics tells whether fn panics.
+func panics(fn func()) (ret bool) {
+	defer func() {
+		ret = recover() != nil
+	}()
+	fn()
+	return false
+}
+
+const testFrame = 200 * time.Millisecond // default update interval of sprite instances.
+
+func newTestMachine() *AnimStateMachine {
+	newTestSprite("spr_animIdle", 2, SpriteMetaOptions{})
+	newTestSprite("spr_animRun", 2, SpriteMetaOptions{})
+	newTestSprite("spr_animAttack", 2, SpriteMetaOptions{Mode: PlayMode_Once})
+	newTestSprite("spr_animHurt", 2, SpriteMetaOptions{})
+	return NewAnimStateMachine().
+		AddState("idle", "spr_animIdle", 1).
+		AddState("run", "spr_animRun", 1).
+		AddState("attack", "spr_animAttack", 1).
+		AddState("hurt", "spr_animHurt", 1).
+		AddBool("moving", false).
+		AddFloat("speed", 0).
+		AddTrigger("attack").
+		AddTrigger("hit")
+}
+
+func TestAnimatorInvalidParams(t *testing.T) {
+	anmt := NewAnimatorFromStateMachine(newTestMachine())
+	// panics do not leave the animator locked.
+	require.EqBool(true, panics(func() { anmt.SetBool("missing", true) }))
+	require.EqBool(true, panics(func() { anmt.SetBool("speed", true) }))
+	require.EqBool(true, panics(func() { anmt.GetFloat("moving") }))
+	require.EqBool(true, panics(func() { anmt.AlterState("missing") }))
+	anmt.SetBool("moving", true)
+	require.EqBool(true, anmt.GetBool("moving"))
+	anmt.AlterState("run")
+	require.EqBool(true, anmt.CurrentState() == "run")
+}
+
+func TestAnimatorTransitionPriority(t *testing.T) {
+	machine := newTestMachine().
+		AddTransition(AnimTransition{From: "idle", To: "run", Conditions: []AnimCondition{{Param: "moving", Mode: AnimCondition_If}}}).
+		AddTransition(AnimTransition{From: "idle", To: "attack", Conditions: []AnimCondition{{Param: "speed", Mode: AnimCondition_Greater, Threshold: 1}}}).
+		AddTransition(AnimTransition{To: "hurt", Conditions: []AnimCondition{{Param: "hit", Mode: AnimCondition_If}}, Priority: 10})
+	anmt := NewAnimatorFromStateMachine(machine)
+	// ties are broken by the order of declaration.
+	anmt.SetBool("moving", true)
+	anmt.SetFloat("speed", 2)
+	anmt.Step(0)
+	require.EqBool(true, anmt.CurrentState() == "run")
+	// higher priority goes first, even though it is declared later.
+	anmt.AlterState("idle")
+	anmt.SetTrigger("hit")
+	anmt.Step(0)
+	require.EqBool(true, anmt.CurrentState() == "hurt")
+	// lower ones are taken if higher ones do not hold.
+	anmt = NewAnimatorFromStateMachine(machine)
+	anmt.SetBool("moving", false)
+	anmt.SetFloat("speed", 2)
+	anmt.Step(0)
+	require.EqBool(true, anmt.CurrentState() == "attack")
+}
+
+func TestAnimatorExitTime(t *testing.T) {
+	machine := newTestMachine().
+		AddTransition(AnimTransition{From: "idle", To: "attack", Conditions: []AnimCondition{{Param: "attack", Mode: AnimCondition_If}}}).
+		AddTransition(AnimTransition{From: "attack", To: "idle", HasExitTime: true, ExitTime: 1})
+	anmt := NewAnimatorFromStateMachine(machine)
+	anmt.SetTrigger("attack")
+	anmt.Step(0)
+	require.EqBool(true, anmt.CurrentState() == "attack")
+	anmt.Step(testFrame)
+	require.EqBool(true, anmt.CurrentState() == "attack")
+	// the attack clip of 2 frames has been played once.
+	events := anmt.Step(testFrame)
+	t.Log(events)
+	require.EqBool(true, anmt.CurrentState() == "idle")
+	require.EqBool(true, len(events) == 1 && events[0].Finished && events[0].State == "attack")
+}
+
+func TestAnimatorTriggerConsumption(t *testing.T) {
+	machine := newTestMachine().
+		AddTransition(AnimTransition{From: "idle", To: "attack", Conditions: []AnimCondition{{Param: "attack", Mode: AnimCondition_If}}}).
+		AddTransition(AnimTransition{From: "attack", To: "idle", HasExitTime: true, ExitTime: 1}).
+		AddTransition(AnimTransition{From: "run", To: "hurt", Conditions: []AnimCondition{{Param: "hit", Mode: AnimCondition_If}}})
+	anmt := NewAnimatorFromStateMachine(machine)
+	anmt.SetTrigger("attack")
+	// a trigger stays set until a transition takes it, triggers of transitions not taken are kept.
+	anmt.SetTrigger("hit")
+	anmt.Step(0)
+	require.EqBool(true, anmt.CurrentState() == "attack")
+	anmt.Step(2 * testFrame)
+	require.EqBool(true, anmt.CurrentState() == "idle")
+	// the attack trigger has been consumed, so idle stays.
+	anmt.Step(0)
+	require.EqBool(true, anmt.CurrentState() == "idle")
+	anmt.AlterState("run")
+	anmt.Step(0)
+	require.EqBool(true, anmt.CurrentState() == "hurt")
+	// a reset trigger does not fire.
+	anmt.AlterState("idle")
+	anmt.SetTrigger("attack")
+	anmt.ResetTrigger("attack")
+	anmt.Step(0)
+	require.EqBool(true, anmt.CurrentState() == "idle")
+}
+
+func TestAnimatorAnyState(t *testing.T) {
+	hit := []AnimCondition{{Param: "hit", Mode: AnimCondition_If}}
+	for _, toSelf := range []bool{false, true} {
+		machine := newTestMachine().AddTransition(AnimTransition{To: "hurt", Conditions: hit, ToSelf: toSelf})
+		anmt := NewAnimatorFromStateMachine(machine)
+		// any-state transitions leave every state.
+		anmt.AlterState("run")
+		anmt.SetTrigger("hit")
+		anmt.Step(0)
+		require.EqBool(true, anmt.CurrentState() == "hurt")
+		anmt.Step(testFrame)
+		require.EqBool(true, anmt.Spr().NormalizedTime() == 0.5)
+		// the hurt state restarts itself only with ToSelf.
+		anmt.SetTrigger("hit")
+		anmt.Step(0)
+		t.Log(toSelf, anmt.Spr().NormalizedTime())
+		require.EqBool(toSelf, anmt.Spr().NormalizedTime() == 0)
+	}
+}
@@ -0,0 +1,198 @@
+package graphics
+
+import (
+	"sync"
+
+	"galaxyzeta.io/engine/linalg"
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+const (
+	DefaultBatchSize   = 2048 // quads a batch holds before it has to be flushed.
+	DefaultBatchShader = "default"
+
+	batchVertexStride = 9 // x, y, z, u, v, r, g, b, a
+	verticesPerQuad   = 6 // quads are drawn as 2 triangles.
+
+	immediateBatchSize = 256 // large enough for a line of text.
+)
+
+// FullUV covers a whole texture.
+var FullUV = linalg.NewRect(0, 0, 1, 1)
+
+// White keeps colors of a texture when used as a tint.
+var White = linalg.NewRgbaF64(1, 1, 1, 1)
+
+// immediate is a small batch used by sprites rendered outside of Renderer2DSystem.
+var immediate *SpriteBatch
+
+// RenderStats describes the cost of rendering a frame.
+type RenderStats struct {
+	DrawCalls int // gl.DrawArrays issued.
+	Vertices  int // vertices submitted to OpenGL.
+	Quads     int // quads drawn, usually sprites.
+	Flushes   int // flushes caused by texture, shader or material changes.
+}
+
+// Add returns the sum of two stats.
+func (s RenderStats) Add(another RenderStats) RenderStats {
+	return RenderStats{
+		DrawCalls: s.DrawCalls + another.DrawCalls,
+		Vertices:  s.Vertices + another.Vertices,
+		Quads:     s.Quads + another.Quads,
+		Flushes:   s.Flushes + another.Flushes,
+	}
+}
+
+// SpriteBatch collects textured quads into a large triangle vertex buffer, and draws them with as few draw calls as possible.
+// Consecutive quads sharing the same texture and shader are drawn together, so the buffer is flushed only on state changes or when it is full.
+// Except Stats, all methods must be called on the render thread, and drawing happens between Begin and End.
+type SpriteBatch struct {
+	vbo        uint32
+	vertices   []float64 // vertices in OpenGL space waiting to be drawn.
+	maxQuads   int
+	texture    uint32
+	shader     string
+	material   *Material   // uniforms of the shader, nil if quads are not drawn with a material.
+	view       linalg.Mat3 // converts world space into OpenGL space.
+	cam        *Camera
+	drawing    bool
+	stats      RenderStats // stats of the frame being drawn.
+	frameStats RenderStats // stats of the last finished frame, which could be read by other threads.
+	statsMu    sync.RWMutex
+}
+
+// NewSpriteBatch creates a batch holding at most maxQuads quads before flushing.
+func NewSpriteBatch(maxQuads int) *SpriteBatch {
+	if maxQuads <= 0 {
+		panic("batch size must be positive")
+	}
+	return &SpriteBatch{
+		maxQuads: maxQuads,
+		vertices: make([]float64, 0, maxQuads*verticesPerQuad*batchVertexStride),
+	}
+}
+
+// Begin starts a frame viewed by the camera, blending is enabled until End.
+func (b *SpriteBatch) Begin(cam *Camera) {
+	if b.drawing {
+		panic("sprite batch has already begun")
+	}
+	if b.vbo == 0 {
+		// buffers could only be allocated on the render thread.
+		b.vbo = GLNewVBO(1)
+	}
+	b.drawing = true
+	b.cam = cam
+	b.view = cam.ViewMat3()
+	b.stats = RenderStats{}
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+}
+
+// End draws everything left and finishes the frame.
+func (b *SpriteBatch) End() {
+	if !b.drawing {
+		panic("sprite batch has not begun")
+	}
+	b.Flush()
+	gl.Disable(gl.BLEND)
+	b.drawing = false
+	b.statsMu.Lock()
+	b.frameStats = b.stats
+	b.statsMu.Unlock()
+}
+
+// Camera returns the camera of the frame being drawn.
+func (b *SpriteBatch) Camera() *Camera {
+	return b.cam
+}
+
+// Stats returns stats of the last finished frame, it is safe to be called from any thread.
+func (b *SpriteBatch) Stats() (stats RenderStats) {
+	b.statsMu.RLock()
+	stats = b.frameStats
+	b.statsMu.RUnlock()
+	return
+}
+
+// DrawQuad queues a textured quad. Corners are in world space, clockwise from the top-left one on the texture.
+// uv is the area of the texture mapped to the quad, FullUV covers the whole texture.
+func (b *SpriteBatch) DrawQuad(texture uint32, shader string, corners [4]linalg.Vector2f64, uv linalg.Rect) {
+	b.DrawQuadTinted(texture, shader, corners, uv, White)
+}
+
+// DrawQuadTinted queues a textured quad like DrawQuad, colors of the texture are multiplied by the tint.
+func (b *SpriteBatch) DrawQuadTinted(texture uint32, shader string, corners [4]linalg.Vector2f64, uv linalg.Rect, tint linalg.RgbaF64) {
+	b.drawQuad(texture, shader, nil, corners, uv, tint)
+}
+
+// DrawQuadMaterial queues a textured quad like DrawQuad, which is drawn with the shader, uniforms and tint of the material.
+// The default shader is used if material is nil.
+func (b *SpriteBatch) DrawQuadMaterial(texture uint32, material *Material, corners [4]linalg.Vector2f64, uv linalg.Rect) {
+	if material == nil {
+		b.drawQuad(texture, DefaultBatchShader, nil, corners, uv, White)
+		return
+	}
+	b.drawQuad(texture, material.Shader, material, corners, uv, material.Tint)
+}
+
+func (b *SpriteBatch) drawQuad(texture uint32, shader string, material *Material, corners [4]linalg.Vector2f64, uv linalg.Rect, tint linalg.RgbaF64) {
+	if !b.drawing {
+		panic("sprite batch has not begun")
+	}
+	if len(b.vertices) > 0 && (texture != b.texture || shader != b.shader || material != b.material) {
+		b.stats.Flushes++
+		b.Flush()
+	} else if len(b.vertices) >= b.maxQuads*verticesPerQuad*batchVertexStride {
+		b.Flush()
+	}
+	b.texture = texture
+	b.shader = shader
+	b.material = material
+	var p [4]linalg.Vector2f64
+	for i, corner := range corners {
+		p[i] = b.view.MulPoint(corner)
+	}
+	u0, v0, u1, v1 := uv.Left(), uv.Top(), uv.Right(), uv.Bottom()
+	r, g, bl, a := tint.X, tint.Y, tint.Z, tint.W
+	b.vertices = append(b.vertices,
+		p[0].X, p[0].Y, 0, u0, v0, r, g, bl, a,
+		p[3].X, p[3].Y, 0, u0, v1, r, g, bl, a,
+		p[2].X, p[2].Y, 0, u1, v1, r, g, bl, a,
+		p[0].X, p[0].Y, 0, u0, v0, r, g, bl, a,
+		p[2].X, p[2].Y, 0, u1, v1, r, g, bl, a,
+		p[1].X, p[1].Y, 0, u1, v0, r, g, bl, a,
+	)
+	b.stats.Quads++
+}
+
+// DrawFrame queues a frame as a quad with the default shader, see DrawQuadTinted.
+func (b *SpriteBatch) DrawFrame(frame *GLFrame, corners [4]linalg.Vector2f64, tint linalg.RgbaF64) {
+	b.DrawQuadTinted(frame.page.glTexture, DefaultBatchShader, corners, frame.uv, tint)
+}
+
+// Flush draws queued quads at once.
+func (b *SpriteBatch) Flush() {
+	if len(b.vertices) == 0 {
+		return
+	}
+	count := len(b.vertices) / batchVertexStride
+	GLBindData(b.vbo, b.vertices, len(b.vertices)*8, gl.STREAM_DRAW)
+	GLActivateTexture(b.texture)
+	if program := GLActivateShader(b.shader); b.material != nil && program != 0 {
+		b.material.apply(program)
+	}
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(count))
+	b.stats.DrawCalls++
+	b.stats.Vertices += count
+	b.vertices = b.vertices[:0]
+}
+
+// immediateBatch returns the batch for immediate rendering, it is created at the first use on the render thread.
+func immediateBatch() *SpriteBatch {
+	if immediate == nil {
+		immediate = NewSpriteBatch(immediateBatchSize)
+	}
+	return immediate
+}
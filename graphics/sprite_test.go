@@ -0,0 +1,112 @@
+package graphics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"galaxyzeta.io/engine/infra/require"
+)
+
+func TestSpriteAdvance(t *testing.T) {
+	events := map[int][]string{0: {"f0"}, 1: {"f1"}, 2: {"f2"}}
+	ms := time.Millisecond
+	cases := []struct {
+		name     string
+		opts     SpriteMetaOptions
+		dt       time.Duration
+		frames   []int      // current frame after each advance.
+		events   [][]string // events fired by each advance.
+		finished []bool     // finished returned by each advance.
+	}{
+		{
+			name:     "loop",
+			opts:     SpriteMetaOptions{Events: events},
+			dt:       testFrame,
+			frames:   []int{1, 2, 0, 1},
+			events:   [][]string{{"f0", "f1"}, {"f2"}, {"f0"}, {"f1"}},
+			finished: []bool{false, false, false, false},
+		},
+		{
+			name:     "once",
+			opts:     SpriteMetaOptions{Events: events, Mode: PlayMode_Once},
+			dt:       testFrame,
+			frames:   []int{1, 2, 0, 0},
+			events:   [][]string{{"f0", "f1"}, {"f2"}, nil, nil},
+			finished: []bool{false, false, true, false},
+		},
+		{
+			name:     "ping-pong",
+			opts:     SpriteMetaOptions{Events: events, Mode: PlayMode_PingPong},
+			dt:       testFrame,
+			frames:   []int{1, 2, 1, 0, 1},
+			events:   [][]string{{"f0", "f1"}, {"f2"}, {"f1"}, {"f0"}, {"f1"}},
+			finished: []bool{false, false, false, false, false},
+		},
+		{
+			name:     "clamp-forever",
+			opts:     SpriteMetaOptions{Events: events, Mode: PlayMode_ClampForever},
+			dt:       testFrame,
+			frames:   []int{1, 2, 2, 2},
+			events:   [][]string{{"f0", "f1"}, {"f2"}, nil, nil},
+			finished: []bool{false, false, true, false},
+		},
+		{
+			name:     "reverse",
+			opts:     SpriteMetaOptions{Events: events, Reverse: true},
+			dt:       testFrame,
+			frames:   []int{1, 0, 2, 1},
+			events:   [][]string{{"f2", "f1"}, {"f0"}, {"f2"}, {"f1"}},
+			finished: []bool{false, false, false, false},
+		},
+		{
+			name:     "reverse once",
+			opts:     SpriteMetaOptions{Mode: PlayMode_Once, Reverse: true},
+			dt:       testFrame,
+			frames:   []int{1, 0, 2},
+			events:   [][]string{nil, nil, nil},
+			finished: []bool{false, false, true},
+		},
+		{
+			name:     "durations",
+			opts:     SpriteMetaOptions{Events: events, Durations: []time.Duration{100 * ms, 300 * ms, 0}},
+			dt:       100 * ms,
+			frames:   []int{1, 1, 1, 2, 2, 0},
+			events:   [][]string{{"f0", "f1"}, nil, nil, {"f2"}, nil, {"f0"}},
+			finished: []bool{false, false, false, false, false, false},
+		},
+		{
+			name:     "several frames in one advance",
+			opts:     SpriteMetaOptions{Events: events},
+			dt:       3*testFrame + 100*ms,
+			frames:   []int{0, 1},
+			events:   [][]string{{"f0", "f1", "f2", "f0"}, {"f1", "f2", "f0", "f1"}},
+			finished: []bool{false, false},
+		},
+	}
+	for idx, c := range cases {
+		name := fmt.Sprintf("spr_advance%d", idx)
+		newTestSprite(name, 3, c.opts)
+		spr := NewSpriteInstance(name)
+		for step := range c.frames {
+			fired, finished := spr.Advance(c.dt)
+			t.Log(c.name, step, spr.currentFrame, fired, finished)
+			require.EqInt(c.frames[step], spr.currentFrame)
+			require.EqBool(c.finished[step], finished)
+			require.EqBool(true, fmt.Sprint(fired) == fmt.Sprint(c.events[step]))
+		}
+	}
+}
+
+func TestSpriteNormalizedTime(t *testing.T) {
+	newTestSprite("spr_normalized", 2, SpriteMetaOptions{Mode: PlayMode_ClampForever})
+	spr := NewSpriteInstance("spr_normalized")
+	spr.SetSpeed(2)
+	spr.Advance(testFrame)
+	require.EqBool(true, spr.NormalizedTime() == 1)
+	// normalized time keeps growing after a clamp-forever animation finishes.
+	spr.Advance(testFrame)
+	require.EqBool(true, spr.IsFinished() && spr.NormalizedTime() == 2)
+	spr.Restart()
+	require.EqBool(true, !spr.IsFinished() && spr.NormalizedTime() == 0)
+}
@@ -0,0 +1,110 @@
+package graphics
+
+import (
+	"image"
+	"image/draw"
+	"os"
+	"strconv"
+
+	"galaxyzeta.io/engine/linalg"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// DefaultFontRunes are printable ASCII characters, which are rasterized if no runes are given.
+const DefaultFontRunes = " !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~"
+
+// LoadTrueTypeFont reads a TrueType or OpenType file, see NewTrueTypeFont.
+// If an error occurs, will panic.
+func LoadTrueTypeFont(ttfPath string, size float64, runes string) *Font {
+	data, err := os.ReadFile(ttfPath)
+	if err != nil {
+		panic(err)
+	}
+	return NewTrueTypeFont(data, size, runes)
+}
+
+// NewTrueTypeFont rasterizes runes of a TrueType or OpenType font at size in pixels, and packs them into atlas pages.
+// Glyphs are white, so that they could be colored by tints. Kerning is read from the kern table of the font.
+// DefaultFontRunes are used if runes is empty.
+// If an error occurs, will panic.
+func NewTrueTypeFont(data []byte, size float64, runes string) *Font {
+	if size <= 0 {
+		panic("font size must be positive")
+	}
+	if runes == "" {
+		runes = DefaultFontRunes
+	}
+	parsed, err := opentype.Parse(data)
+	if err != nil {
+		panic(err)
+	}
+	face, err := opentype.NewFace(parsed, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		panic(err)
+	}
+	defer face.Close()
+
+	metrics := face.Metrics()
+	ret := newFont(fixedToFloat(metrics.Height), fixedToFloat(metrics.Ascent))
+	var names []string
+	var images []image.Image
+	var packed []rune
+	for _, r := range runes {
+		if _, ok := ret.glyphs[r]; ok {
+			continue
+		}
+		if _, _, ok := face.GlyphBounds(r); !ok {
+			continue
+		}
+		dr, mask, maskp, advance, ok := face.Glyph(fixed.Point26_6{}, r)
+		if !ok {
+			continue
+		}
+		// dr is relative to the pen on the baseline.
+		ret.glyphs[r] = &Glyph{
+			W:        float64(dr.Dx()),
+			H:        float64(dr.Dy()),
+			XOffset:  float64(dr.Min.X),
+			YOffset:  ret.Base + float64(dr.Min.Y),
+			XAdvance: fixedToFloat(advance),
+		}
+		if dr.Empty() {
+			continue
+		}
+		// masks are reused by the face, so they are copied at once.
+		img := image.NewRGBA(image.Rect(0, 0, dr.Dx(), dr.Dy()))
+		draw.DrawMask(img, img.Bounds(), image.White, image.Point{}, mask, maskp, draw.Src)
+		names = append(names, strconv.QuoteRune(r))
+		images = append(images, img)
+		packed = append(packed, r)
+	}
+	for a := range ret.glyphs {
+		for b := range ret.glyphs {
+			if k := face.Kern(a, b); k != 0 {
+				ret.kernings[[2]rune{a, b}] = fixedToFloat(k)
+			}
+		}
+	}
+
+	pages, err := NewAtlasPacker().Pack(names, images)
+	if err != nil {
+		panic(err)
+	}
+	for _, packedPage := range pages {
+		page := newAtlasPage(packedPage.Image)
+		bounds := packedPage.Image.Bounds()
+		pw, ph := float64(bounds.Dx()), float64(bounds.Dy())
+		for _, region := range packedPage.Regions {
+			g := ret.glyphs[packed[region.index]]
+			g.page = page
+			g.uv = linalg.NewRect(float64(region.X)/pw, float64(region.Y)/ph, float64(region.W)/pw, float64(region.H)/ph)
+		}
+	}
+	return ret
+}
+
+func fixedToFloat(v fixed.Int26_6) float64 {
+	return float64(v) / 64
+}
@@ -1,7 +1,8 @@
 package layer
 
+// Orders of builtin sorting layers, renderables on a sorting layer of higher order are drawn over those of lower order.
 const (
-	Layer_BG      = 20
-	Layer_FG      = 0
-	Layer_Default = 10
+	Layer_BG      = -100
+	Layer_Default = 0
+	Layer_FG      = 100
 )
@@ -16,7 +16,7 @@ type AsyncLoggerSink struct {
 	buffer        []byte
 	pos           int
 	flushDuration time.Duration
-	timer         time.Ticker
+	timer         *time.Ticker
 	mu            *lock.SpinLock
 }
 
@@ -43,7 +43,7 @@ func NewAsyncLoggerSink(cacheSize int, duration time.Duration) *AsyncLoggerSink
 		buffer:        make([]byte, cacheSize),
 		pos:           0,
 		flushDuration: duration,
-		timer:         *time.NewTicker(duration),
+		timer:         time.NewTicker(duration),
 		mu:            &lock.SpinLock{},
 	}
 	go sink.logFlushRoutine()
@@ -0,0 +1,86 @@
+package linalg
+
+import "sort"
+
+// CurveKey is a value of a curve at time T.
+type CurveKey struct {
+	T float64
+	V float64
+}
+
+// Curve interpolates linearly between keys sorted by T, values out of the range of keys are clamped.
+type Curve []CurveKey
+
+// NewCurve returns a curve of keys, which are sorted by T.
+func NewCurve(keys ...CurveKey) Curve {
+	ret := append(Curve(nil), keys...)
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].T < ret[j].T
+	})
+	return ret
+}
+
+// ConstantCurve returns a curve of v.
+func ConstantCurve(v float64) Curve {
+	return Curve{{T: 0, V: v}}
+}
+
+// LinearCurve returns a curve going from a at 0 to b at 1.
+func LinearCurve(a float64, b float64) Curve {
+	return Curve{{T: 0, V: a}, {T: 1, V: b}}
+}
+
+// Evaluate returns the value at t, an empty curve is 1 everywhere.
+func (c Curve) Evaluate(t float64) float64 {
+	if len(c) == 0 {
+		return 1
+	}
+	idx := sort.Search(len(c), func(i int) bool {
+		return c[i].T > t
+	})
+	if idx == 0 {
+		return c[0].V
+	}
+	if idx == len(c) {
+		return c[len(c)-1].V
+	}
+	a, b := c[idx-1], c[idx]
+	return Lerp(a.V, b.V, InverseLerp(a.T, b.T, t))
+}
+
+// GradientKey is a color of a gradient at time T.
+type GradientKey struct {
+	T     float64
+	Color RgbaF64
+}
+
+// Gradient interpolates colors linearly between keys sorted by T, colors out of the range of keys are clamped.
+type Gradient []GradientKey
+
+// NewGradient returns a gradient of keys, which are sorted by T.
+func NewGradient(keys ...GradientKey) Gradient {
+	ret := append(Gradient(nil), keys...)
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].T < ret[j].T
+	})
+	return ret
+}
+
+// Evaluate returns the color at t, an empty gradient is opaque white everywhere.
+func (g Gradient) Evaluate(t float64) RgbaF64 {
+	if len(g) == 0 {
+		return NewRgbaF64(1, 1, 1, 1)
+	}
+	idx := sort.Search(len(g), func(i int) bool {
+		return g[i].T > t
+	})
+	if idx == 0 {
+		return g[0].Color
+	}
+	if idx == len(g) {
+		return g[len(g)-1].Color
+	}
+	a, b := g[idx-1], g[idx]
+	k := InverseLerp(a.T, b.T, t)
+	return NewRgbaF64(Lerp(a.Color.X, b.Color.X, k), Lerp(a.Color.Y, b.Color.Y, k), Lerp(a.Color.Z, b.Color.Z, k), Lerp(a.Color.W, b.Color.W, k))
+}
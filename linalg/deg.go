@@ -14,3 +14,24 @@ func Rad2Deg(rad float64) float64 {
 func InvertDeg(deg float64) float64 {
 	return 360 - deg
 }
+
+// WrapDeg wraps an angle into (-180, 180].
+func WrapDeg(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg <= -180 {
+		deg += 360
+	} else if deg > 180 {
+		deg -= 360
+	}
+	return deg
+}
+
+// DeltaDeg returns the shortest signed angle turning from one angle to another, in (-180, 180].
+func DeltaDeg(from float64, to float64) float64 {
+	return WrapDeg(to - from)
+}
+
+// LerpDeg interpolates between two angles along the shortest way, t is clamped into [0, 1].
+func LerpDeg(from float64, to float64, t float64) float64 {
+	return from + DeltaDeg(from, to)*Clamp(t, 0, 1)
+}
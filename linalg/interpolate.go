@@ -0,0 +1,55 @@
+package linalg
+
+import "math"
+
+// Lerp interpolates between a and b linearly, t is not clamped.
+func Lerp(a float64, b float64, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// InverseLerp returns t that Lerp(a, b, t) equals v, zero if a equals b.
+func InverseLerp(a float64, b float64, v float64) float64 {
+	if a == b {
+		return 0
+	}
+	return (v - a) / (b - a)
+}
+
+// Clamp limits v into [min, max].
+func Clamp(v float64, min float64, max float64) float64 {
+	return math.Max(min, math.Min(v, max))
+}
+
+// SmoothDamp moves current towards target like a critically damped spring, which never overshoots.
+// smoothTime is roughly the seconds to reach the target, maxSpeed limits the speed and could be math.Inf(1).
+// velocity should be kept by the caller between calls, the updated value and velocity are returned.
+func SmoothDamp(current float64, target float64, velocity float64, smoothTime float64, maxSpeed float64, dt float64) (float64, float64) {
+	if dt <= 0 {
+		return current, velocity
+	}
+	smoothTime = math.Max(0.0001, smoothTime)
+	omega := 2 / smoothTime
+	x := omega * dt
+	exp := 1 / (1 + x + 0.48*x*x + 0.235*x*x*x)
+	maxChange := maxSpeed * smoothTime
+	change := Clamp(current-target, -maxChange, maxChange)
+	originalTarget := target
+	target = current - change
+	temp := (velocity + omega*change) * dt
+	velocity = (velocity - omega*temp) * exp
+	output := target + (change+temp)*exp
+	// prevents overshooting.
+	if (originalTarget-current > 0) == (output > originalTarget) {
+		output = originalTarget
+		velocity = 0
+	}
+	return output, velocity
+}
+
+// SmoothDampVector applies SmoothDamp on each axis, maxSpeed limits the speed on each axis.
+func SmoothDampVector(current Vector2f64, target Vector2f64, velocity Vector2f64, smoothTime float64, maxSpeed float64, dt float64) (Vector2f64, Vector2f64) {
+	var ret Vector2f64
+	ret.X, velocity.X = SmoothDamp(current.X, target.X, velocity.X, smoothTime, maxSpeed, dt)
+	ret.Y, velocity.Y = SmoothDamp(current.Y, target.Y, velocity.Y, smoothTime, maxSpeed, dt)
+	return ret, velocity
+}
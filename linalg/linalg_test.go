@@ -1,6 +1,8 @@
 package linalg
 
 import (
+	"math"
+	"math/rand"
 	"testing"
 
 	"galaxyzeta.io/engine/infra/require"
@@ -13,6 +15,35 @@ func TestVectorTheta(t *testing.T) {
 	t.Log(v2.ThetaDeg(v1))
 }
 
+func TestVectorRotate(t *testing.T) {
+	v := Vector2f64{X: 5, Y: 0}.Rotate(90)
+	t.Log(v)
+	require.EqBool(true, math.Abs(v.X) < 1e-9 && math.Abs(v.Y-5) < 1e-9)
+	v = Vector2f64{X: 3, Y: 4}.Rotate(-30)
+	require.EqBool(true, math.Abs(v.Magnitude()-5) < 1e-9)
+}
+
+func TestMat3Transform(t *testing.T) {
+	m := NewTransformMat3(NewVector2f64(10, 20), 90, NewVector2f64(2, 1))
+	p := m.MulPoint(NewVector2f64(1, 0))
+	t.Log(p)
+	require.EqBool(true, math.Abs(p.X-10) < 1e-9 && math.Abs(p.Y-22) < 1e-9)
+	v := m.MulVector(NewVector2f64(0, 3))
+	require.EqBool(true, math.Abs(v.X+3) < 1e-9 && math.Abs(v.Y) < 1e-9)
+	// composing matches applying one after another.
+	n := NewTransformMat3(NewVector2f64(-5, 3), 30, NewVector2f64(1, 1))
+	q := NewVector2f64(7, -2)
+	composed := m.Mul(n).MulPoint(q)
+	sequential := m.MulPoint(n.MulPoint(q))
+	require.EqBool(true, composed.Sub(sequential).Magnitude() < 1e-9)
+	require.EqBool(true, Identity3().Mul(m) == m)
+	inv, ok := m.Inverse()
+	require.EqBool(true, ok)
+	require.EqBool(true, inv.MulPoint(m.MulPoint(q)).Sub(q).Magnitude() < 1e-9)
+	_, ok = NewTransformMat3(q, 45, NewVector2f64(0, 1)).Inverse()
+	require.EqBool(false, ok)
+}
+
 func TestSegmentIntersect(t *testing.T) {
 	// test parallel
 	s1 := NewSegmentf64(0, 0, 1, 1)
@@ -27,3 +58,195 @@ func TestSegmentIntersect(t *testing.T) {
 	s2 = NewSegmentf64(0, 1, 2, 0)
 	require.EqBool(true, s1.Intersect(s2))
 }
+
+// property tests below check invariants on random inputs, the seed is fixed to make failures reproducible.
+const propertyRounds = 1000
+
+func approxEq(a float64, b float64) bool {
+	return math.Abs(a-b) < 1e-6*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
+
+func approxVecEq(a Vector2f64, b Vector2f64) bool {
+	return approxEq(a.X, b.X) && approxEq(a.Y, b.Y)
+}
+
+func randVector(r *rand.Rand) Vector2f64 {
+	return NewVector2f64(r.Float64()*2000-1000, r.Float64()*2000-1000)
+}
+
+// randScale returns a scale away from zero, which could be negative.
+func randScale(r *rand.Rand) Vector2f64 {
+	s := func() float64 {
+		v := 0.1 + r.Float64()*4
+		if r.Intn(2) == 0 {
+			return -v
+		}
+		return v
+	}
+	return NewVector2f64(s(), s())
+}
+
+func TestMat3Properties(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < propertyRounds; i++ {
+		pos, deg, scale := randVector(r), r.Float64()*720-360, randScale(r)
+		m := NewTransformMat3(pos, deg, scale)
+		n := NewTransformMat3(randVector(r), r.Float64()*360, randScale(r))
+		p := randVector(r)
+		// composing equals applying one after another.
+		require.EqBool(true, approxVecEq(m.Mul(n).MulPoint(p), m.MulPoint(n.MulPoint(p))))
+		// T * R * S equals the combined constructor.
+		trs := NewTranslateMat3(pos).Mul(NewRotateMat3(deg)).Mul(NewScaleMat3(scale))
+		require.EqBool(true, approxVecEq(trs.MulPoint(p), m.MulPoint(p)))
+		// inverse undoes the transformation.
+		inv, ok := m.Inverse()
+		require.EqBool(true, ok)
+		require.EqBool(true, approxVecEq(inv.MulPoint(m.MulPoint(p)), p))
+		require.EqBool(true, approxVecEq(m.Mul(inv).MulPoint(p), p))
+		// vectors are not translated.
+		require.EqBool(true, approxVecEq(m.MulVector(p), m.MulPoint(p).Sub(m.MulPoint(Vector2f64{}))))
+		// decomposing and composing again gives the same matrix.
+		dpos, ddeg, dscale := m.Decompose()
+		rebuilt := NewTransformMat3(dpos, ddeg, dscale)
+		require.EqBool(true, approxVecEq(rebuilt.MulPoint(p), m.MulPoint(p)))
+		require.EqBool(true, approxEq(math.Abs(dscale.X*dscale.Y), math.Abs(scale.X*scale.Y)))
+	}
+}
+
+func TestVectorProperties(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < propertyRounds; i++ {
+		v, w := randVector(r), randVector(r)
+		deg := r.Float64()*720 - 360
+		// rotation keeps length and angles between vectors.
+		require.EqBool(true, approxEq(v.Rotate(deg).Length(), v.Length()))
+		require.EqBool(true, approxEq(v.Rotate(deg).Dot(w.Rotate(deg)), v.Dot(w)))
+		require.EqBool(true, approxVecEq(v.Rotate(deg).Rotate(-deg), v))
+		require.EqBool(true, approxVecEq(v.Rotate(deg), NewRotateMat3(deg).MulVector(v)))
+		// normalized vectors have unit length.
+		require.EqBool(true, approxEq(v.Normalize().Length(), 1))
+		require.EqBool(true, approxEq(v.LengthSquared(), v.Length()*v.Length()))
+		// projection is parallel to the axis, and the rest is perpendicular to it.
+		proj := v.ProjectOn(w)
+		require.EqBool(true, math.Abs(proj.Mult(w)) < 1e-6*w.LengthSquared()+1e-6)
+		require.EqBool(true, math.Abs(v.Sub(proj).Dot(w)) < 1e-6*v.Length()*w.Length()+1e-6)
+		require.EqBool(true, approxEq(v.Distance(w), w.Distance(v)))
+		// lerp hits both ends and the middle.
+		require.EqBool(true, approxVecEq(LerpVector(v, w, 0), v))
+		require.EqBool(true, approxVecEq(LerpVector(v, w, 1), w))
+		require.EqBool(true, approxVecEq(LerpVector(v, w, 0.5), v.Add(w).Scale(0.5)))
+	}
+	require.EqBool(true, Vector2f64{}.Normalize() == Vector2f64{})
+	require.EqBool(true, NewVector2f64(3, 4).ProjectOn(Vector2f64{}) == Vector2f64{})
+}
+
+func TestAngleProperties(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < propertyRounds; i++ {
+		a, b := r.Float64()*2000-1000, r.Float64()*2000-1000
+		w := WrapDeg(a)
+		// wrapped angles are in (-180, 180] and point to the same direction.
+		require.EqBool(true, w > -180 && w <= 180)
+		require.EqBool(true, approxVecEq(NewVector2f64(1, 0).Rotate(w), NewVector2f64(1, 0).Rotate(a)))
+		// the shortest delta turns a into b.
+		d := DeltaDeg(a, b)
+		require.EqBool(true, math.Abs(d) <= 180)
+		require.EqBool(true, approxVecEq(NewVector2f64(1, 0).Rotate(a+d), NewVector2f64(1, 0).Rotate(b)))
+		require.EqBool(true, approxEq(LerpDeg(a, b, 0), a))
+		require.EqBool(true, approxEq(LerpDeg(a, b, 1), a+d))
+		require.EqBool(true, approxEq(LerpDeg(a, b, 2), a+d))
+	}
+	require.EqBool(true, WrapDeg(-180) == 180 && WrapDeg(540) == 180 && WrapDeg(-190) == 170)
+}
+
+func TestInterpolateProperties(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	for i := 0; i < propertyRounds; i++ {
+		a, b, k := r.Float64()*200-100, r.Float64()*200-100, r.Float64()
+		require.EqBool(true, approxEq(InverseLerp(a, b, Lerp(a, b, k)), k))
+		c := Clamp(r.Float64()*400-200, -100, 100)
+		require.EqBool(true, c >= -100 && c <= 100)
+		// smooth damp approaches the target monotonically, never overshoots and respects the max speed.
+		current, target, velocity := a, b, 0.0
+		maxSpeed := 50 + r.Float64()*500
+		dt := 1.0 / 60
+		for step := 0; step < 600; step++ {
+			next, v := SmoothDamp(current, target, velocity, 0.1+r.Float64()*0.3, maxSpeed, dt)
+			require.EqBool(true, math.Abs(target-next) <= math.Abs(target-current)+1e-9)
+			if target >= a {
+				require.EqBool(true, next <= target+1e-9)
+			} else {
+				require.EqBool(true, next >= target-1e-9)
+			}
+			require.EqBool(true, math.Abs(next-current) <= maxSpeed*dt*1.01+1e-9)
+			current, velocity = next, v
+		}
+		require.EqBool(true, math.Abs(current-target) < 0.1)
+	}
+	next, _ := SmoothDampVector(NewVector2f64(0, 0), NewVector2f64(10, -10), Vector2f64{}, 0.2, math.Inf(1), 1.0/60)
+	require.EqBool(true, next.X > 0 && next.Y < 0 && approxEq(next.X, -next.Y))
+}
+
+func TestRectProperties(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	randRect := func() Rect {
+		return NewRect(r.Float64()*200-100, r.Float64()*200-100, r.Float64()*100, r.Float64()*100)
+	}
+	for i := 0; i < propertyRounds; i++ {
+		a, b := randRect(), randRect()
+		// union contains both, intersection is contained by both.
+		u := a.Union(b).Expand(1e-9)
+		require.EqBool(true, u.ContainsRect(a) && u.ContainsRect(b))
+		require.EqBool(true, a.Intersects(b) == b.Intersects(a))
+		if in, ok := a.Intersection(b); ok {
+			require.EqBool(true, a.Expand(1e-9).ContainsRect(in) && b.Expand(1e-9).ContainsRect(in) && !in.IsEmpty())
+			require.EqBool(true, a.Contains(in.Center()) && b.Contains(in.Center()))
+		}
+		require.EqBool(true, a.Contains(a.Center()) && a.Expand(1).ContainsRect(a))
+		// the transformed bounding rect contains transformed corners and center.
+		m := NewTransformMat3(randVector(r), r.Float64()*360, randScale(r))
+		tr := a.Transform(m).Expand(1e-6)
+		require.EqBool(true, tr.Contains(m.MulPoint(a.Min())) && tr.Contains(m.MulPoint(a.Max())) && tr.Contains(m.MulPoint(a.Center())))
+	}
+	require.EqBool(false, NewRect(0, 0, 1, 1).Intersects(NewRect(1, 0, 1, 1)))
+}
+
+func TestWorld2OpenGLMat3(t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	for i := 0; i < propertyRounds; i++ {
+		cam := randVector(r)
+		camRes := NewVector2f64(100+r.Float64()*1000, 100+r.Float64()*1000)
+		winRes := NewVector2f64(100+r.Float64()*1000, 100+r.Float64()*1000)
+		p := randVector(r)
+		m := World2OpenGLMat3(cam, camRes, winRes)
+		require.EqBool(true, approxVecEq(m.MulPoint(p), World2OpenGL(p, cam, camRes, winRes)))
+	}
+}
+
+func TestCurveProperties(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < propertyRounds; i++ {
+		var keys []CurveKey
+		var gkeys []GradientKey
+		for k := 0; k < 1+r.Intn(5); k++ {
+			keys = append(keys, CurveKey{T: r.Float64(), V: r.Float64()*200 - 100})
+			gkeys = append(gkeys, GradientKey{T: r.Float64(), Color: NewRgbaF64(r.Float64(), r.Float64(), r.Float64(), r.Float64())})
+		}
+		c, g := NewCurve(keys...), NewGradient(gkeys...)
+		// keys are hit exactly, values between keys stay between them, and ends are clamped.
+		for idx, key := range c {
+			if idx == len(c)-1 || c[idx+1].T != key.T {
+				require.EqBool(true, approxEq(c.Evaluate(key.T), key.V))
+			}
+			if idx > 0 {
+				v := c.Evaluate((c[idx-1].T + key.T) / 2)
+				require.EqBool(true, v >= math.Min(c[idx-1].V, key.V)-1e-9 && v <= math.Max(c[idx-1].V, key.V)+1e-9)
+			}
+		}
+		require.EqBool(true, c.Evaluate(-1) == c[0].V && c.Evaluate(2) == c[len(c)-1].V)
+		require.EqBool(true, g.Evaluate(-1) == g[0].Color && g.Evaluate(2) == g[len(g)-1].Color)
+	}
+	require.EqBool(true, approxEq(LinearCurve(2, 4).Evaluate(0.25), 2.5) && ConstantCurve(3).Evaluate(0.7) == 3 && Curve(nil).Evaluate(0.5) == 1)
+	mid := NewGradient(GradientKey{T: 0, Color: NewRgbaF64(0, 0, 0, 0)}, GradientKey{T: 1, Color: NewRgbaF64(1, 1, 1, 1)}).Evaluate(0.5)
+	require.EqBool(true, approxEq(mid.X, 0.5) && approxEq(mid.W, 0.5))
+}
@@ -0,0 +1,117 @@
+package linalg
+
+import "math"
+
+// Mat3 is a 3x3 row-major matrix describing a 2D affine transformation.
+// The last row is always (0, 0, 1), points are treated as column vectors (x, y, 1).
+type Mat3 [9]float64
+
+// Identity3 returns the identity matrix.
+func Identity3() Mat3 {
+	return Mat3{
+		1, 0, 0,
+		0, 1, 0,
+		0, 0, 1,
+	}
+}
+
+// NewTransformMat3 returns a matrix that scales first, then rotates by deg degrees (clockwise on screen), and finally translates to pos.
+func NewTransformMat3(pos Vector2f64, deg float64, scale Vector2f64) Mat3 {
+	sin, cos := 0.0, 1.0
+	if deg != 0 {
+		sin, cos = math.Sincos(Deg2Rad(deg))
+	}
+	return Mat3{
+		cos * scale.X, -sin * scale.Y, pos.X,
+		sin * scale.X, cos * scale.Y, pos.Y,
+		0, 0, 1,
+	}
+}
+
+// Mul returns m * n, which applies n first and then m.
+func (m Mat3) Mul(n Mat3) Mat3 {
+	var ret Mat3
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			ret[row*3+col] = m[row*3]*n[col] + m[row*3+1]*n[3+col] + m[row*3+2]*n[6+col]
+		}
+	}
+	return ret
+}
+
+// MulPoint transforms a point, translation is applied.
+func (m Mat3) MulPoint(p Vector2f64) Vector2f64 {
+	return Vector2f64{
+		X: m[0]*p.X + m[1]*p.Y + m[2],
+		Y: m[3]*p.X + m[4]*p.Y + m[5],
+	}
+}
+
+// MulVector transforms a direction, translation is not applied.
+func (m Mat3) MulVector(v Vector2f64) Vector2f64 {
+	return Vector2f64{
+		X: m[0]*v.X + m[1]*v.Y,
+		Y: m[3]*v.X + m[4]*v.Y,
+	}
+}
+
+// Translation returns the translation part of the matrix.
+func (m Mat3) Translation() Vector2f64 {
+	return Vector2f64{X: m[2], Y: m[5]}
+}
+
+// Inverse returns the inverse matrix, ok is false if the matrix is singular, such as when scaled by zero.
+func (m Mat3) Inverse() (ret Mat3, ok bool) {
+	det := m.Determinant()
+	if det == 0 {
+		return ret, false
+	}
+	inv := 1 / det
+	ret = Mat3{
+		m[4] * inv, -m[1] * inv, (m[1]*m[5] - m[4]*m[2]) * inv,
+		-m[3] * inv, m[0] * inv, (m[3]*m[2] - m[0]*m[5]) * inv,
+		0, 0, 1,
+	}
+	return ret, true
+}
+
+// NewTranslateMat3 returns a matrix that moves points by v.
+func NewTranslateMat3(v Vector2f64) Mat3 {
+	return Mat3{
+		1, 0, v.X,
+		0, 1, v.Y,
+		0, 0, 1,
+	}
+}
+
+// NewRotateMat3 returns a matrix that rotates points around the origin by deg degrees, clockwise on screen.
+func NewRotateMat3(deg float64) Mat3 {
+	return NewTransformMat3(Vector2f64{}, deg, NewVector2f64(1, 1))
+}
+
+// NewScaleMat3 returns a matrix that scales points from the origin.
+func NewScaleMat3(scale Vector2f64) Mat3 {
+	return Mat3{
+		scale.X, 0, 0,
+		0, scale.Y, 0,
+		0, 0, 1,
+	}
+}
+
+// Determinant returns the determinant of the linear part, negative value means the matrix flips.
+func (m Mat3) Determinant() float64 {
+	return m[0]*m[4] - m[1]*m[3]
+}
+
+// Decompose splits the matrix into translation, rotation in degrees and scale, so that NewTransformMat3 rebuilds it.
+// A flip is always reported as negative scale on Y, and skew, which could not be represented, is lost.
+func (m Mat3) Decompose() (pos Vector2f64, deg float64, scale Vector2f64) {
+	pos = m.Translation()
+	scale.X = math.Hypot(m[0], m[3])
+	if scale.X == 0 {
+		return pos, 0, NewVector2f64(0, math.Hypot(m[1], m[4]))
+	}
+	deg = Rad2Deg(math.Atan2(m[3], m[0]))
+	scale.Y = m.Determinant() / scale.X
+	return pos, deg, scale
+}
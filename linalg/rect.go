@@ -0,0 +1,117 @@
+package linalg
+
+import "math"
+
+// Rect is an axis aligned rectangle, (X, Y) is its top-left corner.
+type Rect struct {
+	X float64
+	Y float64
+	W float64
+	H float64
+}
+
+func NewRect(x float64, y float64, w float64, h float64) Rect {
+	return Rect{X: x, Y: y, W: w, H: h}
+}
+
+// NewRectFromPoints returns the smallest rect containing all points.
+func NewRectFromPoints(points ...Vector2f64) Rect {
+	if len(points) == 0 {
+		return Rect{}
+	}
+	minX, minY := points[0].X, points[0].Y
+	maxX, maxY := minX, minY
+	for _, p := range points[1:] {
+		minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+	return Rect{X: minX, Y: minY, W: maxX - minX, H: maxY - minY}
+}
+
+func (r Rect) Left() float64 {
+	return r.X
+}
+
+func (r Rect) Top() float64 {
+	return r.Y
+}
+
+func (r Rect) Right() float64 {
+	return r.X + r.W
+}
+
+func (r Rect) Bottom() float64 {
+	return r.Y + r.H
+}
+
+func (r Rect) Min() Vector2f64 {
+	return Vector2f64{X: r.X, Y: r.Y}
+}
+
+func (r Rect) Max() Vector2f64 {
+	return Vector2f64{X: r.X + r.W, Y: r.Y + r.H}
+}
+
+func (r Rect) Center() Vector2f64 {
+	return Vector2f64{X: r.X + r.W/2, Y: r.Y + r.H/2}
+}
+
+func (r Rect) Size() Vector2f64 {
+	return Vector2f64{X: r.W, Y: r.H}
+}
+
+// IsEmpty tells whether the rect has no area.
+func (r Rect) IsEmpty() bool {
+	return r.W <= 0 || r.H <= 0
+}
+
+// Contains tells whether the point is inside the rect, points on the edges are counted.
+func (r Rect) Contains(p Vector2f64) bool {
+	return p.X >= r.X && p.X <= r.Right() && p.Y >= r.Y && p.Y <= r.Bottom()
+}
+
+// ContainsRect tells whether another rect is entirely inside the rect.
+func (r Rect) ContainsRect(another Rect) bool {
+	return another.X >= r.X && another.Right() <= r.Right() && another.Y >= r.Y && another.Bottom() <= r.Bottom()
+}
+
+// Intersects tells whether two rects overlap, touching rects do not overlap.
+func (r Rect) Intersects(another Rect) bool {
+	return r.X < another.Right() && another.X < r.Right() && r.Y < another.Bottom() && another.Y < r.Bottom()
+}
+
+// Intersection returns the overlapped area, ok is false if they do not overlap.
+func (r Rect) Intersection(another Rect) (ret Rect, ok bool) {
+	if !r.Intersects(another) {
+		return ret, false
+	}
+	x, y := math.Max(r.X, another.X), math.Max(r.Y, another.Y)
+	return Rect{X: x, Y: y, W: math.Min(r.Right(), another.Right()) - x, H: math.Min(r.Bottom(), another.Bottom()) - y}, true
+}
+
+// Union returns the smallest rect containing both rects.
+func (r Rect) Union(another Rect) Rect {
+	return NewRectFromPoints(r.Min(), r.Max(), another.Min(), another.Max())
+}
+
+// Expand grows the rect by margin on each side, negative margin shrinks it.
+func (r Rect) Expand(margin float64) Rect {
+	return Rect{X: r.X - margin, Y: r.Y - margin, W: r.W + margin*2, H: r.H + margin*2}
+}
+
+// Translate returns the rect moved by v.
+func (r Rect) Translate(v Vector2f64) Rect {
+	r.X += v.X
+	r.Y += v.Y
+	return r
+}
+
+// Transform returns the bounding rect of the rect transformed by m.
+func (r Rect) Transform(m Mat3) Rect {
+	return NewRectFromPoints(
+		m.MulPoint(Vector2f64{X: r.X, Y: r.Y}),
+		m.MulPoint(Vector2f64{X: r.Right(), Y: r.Y}),
+		m.MulPoint(Vector2f64{X: r.Right(), Y: r.Bottom()}),
+		m.MulPoint(Vector2f64{X: r.X, Y: r.Bottom()}),
+	)
+}
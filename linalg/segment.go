@@ -90,3 +90,24 @@ func (s1 Segmentf64) Intersect(s2 Segmentf64) bool {
 func (s1 Segmentf64) IsVertical() bool {
 	return s1.Point1.Y == s1.Point1.Y
 }
+
+// ClosestPoint returns the point on the segment that is closest to p.
+func (s Segmentf64) ClosestPoint(p Vector2f64) Vector2f64 {
+	v := s.ToVector()
+	lenSquare := v.Dot(v)
+	if lenSquare == 0 {
+		return s.Point1
+	}
+	t := p.Sub(s.Point1).Dot(v) / lenSquare
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+	return Vector2f64{X: s.Point1.X + v.X*t, Y: s.Point1.Y + v.Y*t}
+}
+
+// DistanceToPoint returns the shortest distance between the segment and p.
+func (s Segmentf64) DistanceToPoint(p Vector2f64) float64 {
+	return s.ClosestPoint(p).Sub(p).Magnitude()
+}
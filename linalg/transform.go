@@ -35,12 +35,30 @@ func World2OpenGL(worldSpacePoint Vector2f64, camLTPos Vector2f64, camResolution
 	return Cam2OpenGL(World2Cam(worldSpacePoint, camLTPos), camResolution, windowResolution)
 }
 
+// World2OpenGLMat3 returns the matrix doing the same conversion as World2OpenGL.
+func World2OpenGLMat3(camLTPos Vector2f64, camResolution Vector2f64, windowResolution Vector2f64) Mat3 {
+	ratio := NewVector2f64(windowResolution.X/camResolution.X, windowResolution.Y/camResolution.Y)
+	screen2OpenGL := Mat3{
+		2 / windowResolution.X, 0, -1,
+		0, -2 / windowResolution.Y, 1,
+		0, 0, 1,
+	}
+	return screen2OpenGL.Mul(NewScaleMat3(ratio)).Mul(NewTranslateMat3(camLTPos.Scale(-1)))
+}
+
+// WorldVertice2OpenGL converts positions in a vertex array from world space into OpenGL space in place.
+// Each vertex takes stride floats, and its position starts from offset.
 func WorldVertice2OpenGL(arr *[]float64, offset int, stride int, camLTPos Vector2f64, camResolution Vector2f64, windowResolution Vector2f64) {
-	var pos int = offset
-	for pos < len(*arr) {
-		spos := World2OpenGL(NewVector2f64((*arr)[pos], (*arr)[pos+1]), camLTPos, camResolution, windowResolution)
-		(*arr)[pos] = spos.X
-		(*arr)[pos+1] = spos.Y
-		pos += stride
+	MulVertices(arr, offset, stride, World2OpenGLMat3(camLTPos, camResolution, windowResolution))
+}
+
+// MulVertices transforms positions in a vertex array by m in place.
+// Each vertex takes stride floats, and its position starts from offset.
+func MulVertices(arr *[]float64, offset int, stride int, m Mat3) {
+	vertices := *arr
+	for pos := offset; pos+1 < len(vertices); pos += stride {
+		p := m.MulPoint(NewVector2f64(vertices[pos], vertices[pos+1]))
+		vertices[pos] = p.X
+		vertices[pos+1] = p.Y
 	}
 }
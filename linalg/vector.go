@@ -68,6 +68,11 @@ func (vec1 Vector2f64) Sub(vec2 Vector2f64) Vector2f64 {
 	return Vector2f64{vec1.X - vec2.X, vec1.Y - vec2.Y}
 }
 
+// Scale multiplies both components by k.
+func (vec1 Vector2f64) Scale(k float64) Vector2f64 {
+	return Vector2f64{vec1.X * k, vec1.Y * k}
+}
+
 func (vec1 Vector2f64) Dot(vec2 Vector2f64) float64 {
 	return vec1.X*vec2.X + vec1.Y*vec2.Y
 }
@@ -92,8 +97,27 @@ func (vec1 Vector2f64) Magnitude() float64 {
 	return math.Sqrt(vec1.X*vec1.X + vec1.Y*vec1.Y)
 }
 
+// Length is the same as Magnitude.
+func (vec1 Vector2f64) Length() float64 {
+	return vec1.Magnitude()
+}
+
+// LengthSquared avoids the square root, it is enough for comparing lengths.
+func (vec1 Vector2f64) LengthSquared() float64 {
+	return vec1.X*vec1.X + vec1.Y*vec1.Y
+}
+
+// Distance returns the distance between two points.
+func (vec1 Vector2f64) Distance(vec2 Vector2f64) float64 {
+	return vec2.Sub(vec1).Magnitude()
+}
+
+// Normalize returns the unit vector of the same direction, zero vector stays zero.
 func (vec1 Vector2f64) Normalize() Vector2f64 {
 	magnitude := vec1.Magnitude()
+	if magnitude == 0 {
+		return vec1
+	}
 	return Vector2f64{X: vec1.X / magnitude, Y: vec1.Y / magnitude}
 }
 
@@ -101,8 +125,26 @@ func (vec1 Vector2f64) NormalVec() Vector2f64 {
 	return Vector2f64{X: -vec1.Y, Y: vec1.X}
 }
 
+// Rotate rotates the vector around the origin by deg degrees, which is clockwise on screen.
+func (vec1 Vector2f64) Rotate(deg float64) Vector2f64 {
+	if deg == 0 {
+		return vec1
+	}
+	sin, cos := math.Sincos(Deg2Rad(deg))
+	return Vector2f64{X: vec1.X*cos - vec1.Y*sin, Y: vec1.X*sin + vec1.Y*cos}
+}
+
+// ProjectOn returns the projection of the vector on the direction of vec2, zero if vec2 is zero.
 func (vec1 Vector2f64) ProjectOn(vec2 Vector2f64) Vector2f64 {
-	mag := vec2.Magnitude()
-	scale := vec1.Dot(vec2) / (mag * mag)
+	sqr := vec2.LengthSquared()
+	if sqr == 0 {
+		return Vector2f64{}
+	}
+	scale := vec1.Dot(vec2) / sqr
 	return Vector2f64{X: vec2.X * scale, Y: vec2.Y * scale}
 }
+
+// LerpVector interpolates between a and b linearly, t is not clamped.
+func LerpVector(a Vector2f64, b Vector2f64, t float64) Vector2f64 {
+	return Vector2f64{X: Lerp(a.X, b.X, t), Y: Lerp(a.Y, b.Y, t)}
+}
@@ -22,3 +22,51 @@ func MustParseNumericStringTuple(tuple string) linalg.Vector2f64 {
 	}
 	return linalg.NewVector2f64(f1, f2)
 }
+
+// MustParsePointList parses "x,y" tuples separated by spaces.
+func MustParsePointList(points string) []linalg.Vector2f64 {
+	var ret []linalg.Vector2f64
+	for _, tuple := range strings.Fields(points) {
+		ret = append(ret, MustParseNumericStringTuple(tuple))
+	}
+	return ret
+}
+
+// MustParseFloatList parses floats separated by commas.
+func MustParseFloatList(list string) []float64 {
+	var ret []float64
+	for _, term := range strings.Split(list, ",") {
+		f, err := strconv.ParseFloat(strings.TrimSpace(term), 64)
+		if err != nil {
+			panic(err)
+		}
+		ret = append(ret, f)
+	}
+	return ret
+}
+
+// MustParseInt parses an integer.
+func MustParseInt(term string) int64 {
+	i, err := strconv.ParseInt(strings.TrimSpace(term), 10, 64)
+	if err != nil {
+		panic(err)
+	}
+	return i
+}
+
+// MustParseColor parses a "r,g,b,a" tuple.
+func MustParseColor(tuple string) linalg.RgbaF64 {
+	splited := strings.Split(tuple, ",")
+	if len(splited) != 4 {
+		panic("color should contain 4 components")
+	}
+	var rgba [4]float64
+	for idx, term := range splited {
+		f, err := strconv.ParseFloat(strings.TrimSpace(term), 64)
+		if err != nil {
+			panic(err)
+		}
+		rgba[idx] = f
+	}
+	return linalg.NewRgbaF64(rgba[0], rgba[1], rgba[2], rgba[3])
+}
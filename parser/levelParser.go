@@ -12,12 +12,39 @@ type LevelConfig struct {
 }
 
 type LevelMetas struct {
-	Static           string           `xml:"static"`
-	CameraCount      int              `xml:"camera-count"`
-	FrameMetas       FrameMetas       `xml:"frame-metas"`
-	SpriteMetas      SpriteMetas      `xml:"sprite-metas"`
-	ObjectMetas      ObjectMetas      `xml:"object-metas"`
-	ApplicationMetas ApplicationMetas `xml:"application-metas"`
+	Static           string            `xml:"static"`
+	CameraCount      int               `xml:"camera-count"`
+	Layers           LayerMetas        `xml:"layers"`
+	SortingLayers    SortingLayerMetas `xml:"sorting-layers"`
+	FrameMetas       FrameMetas        `xml:"frame-metas"`
+	SpriteMetas      SpriteMetas       `xml:"sprite-metas"`
+	AnimatorMetas    AnimatorMetas     `xml:"animator-metas"`
+	FontMetas        FontMetas         `xml:"font-metas"`
+	ShaderMetas      ShaderMetas       `xml:"shader-metas"`
+	ParticleMetas    ParticleMetas     `xml:"particle-metas"`
+	ObjectMetas      ObjectMetas       `xml:"object-metas"`
+	ApplicationMetas ApplicationMetas  `xml:"application-metas"`
+	CollisionSystem  CollisionSystem   `xml:"collision-system"`
+}
+
+// LayerMetas names layers, renderables on layers are drawn by cameras whose layer masks contain them. Layer 0 is named "default".
+type LayerMetas struct {
+	Layers []LayerMeta `xml:"layer"`
+}
+
+type LayerMeta struct {
+	Name  string `xml:"name,attr"`
+	Index int    `xml:"index,attr"` // in [0, 32).
+}
+
+// SortingLayerMetas names sorting layers besides builtin "background", "default" and "foreground", whose orders are -100, 0 and 100.
+type SortingLayerMetas struct {
+	SortingLayers []SortingLayerMeta `xml:"sorting-layer"`
+}
+
+type SortingLayerMeta struct {
+	Name  string `xml:"name,attr"`
+	Order int    `xml:"order,attr"` // sorting layers of higher order are drawn over others.
 }
 
 type LevelDetails struct {
@@ -25,18 +52,78 @@ type LevelDetails struct {
 }
 
 type ObjectDetail struct {
-	Name string `xml:"name,attr"`
-	X    int64  `xml:"x,attr"`
-	Y    int64  `xml:"y,attr"`
+	Name      string    `xml:"name,attr"`
+	X         int64     `xml:"x,attr"`
+	Y         int64     `xml:"y,attr"`
+	Rotation  float64   `xml:"rotation,attr"` // degrees, clockwise.
+	Scale     string    `xml:"scale,attr"`    // "x,y" tuple of scale, 1,1 if empty.
+	OneWay    *OneWay   `xml:"one-way"`       // makes the collider of the object one-way.
+	Effectors Effectors `xml:"effectors"`     // effectors attached to the collider of the object.
+}
+
+// OneWay declares a one-way collider, Normal is a "x,y" tuple of the facing direction, upward if empty.
+type OneWay struct {
+	Normal string `xml:"normal,attr"`
+}
+
+type Effectors struct {
+	Conveyor *ConveyorEffector `xml:"conveyor"`
+	Bounce   *BounceEffector   `xml:"bounce"`
+	Buoyancy *BuoyancyEffector `xml:"buoyancy"`
+	Point    *PointEffector    `xml:"point"`
+}
+
+type ConveyorEffector struct {
+	Speed      float64  `xml:"speed,attr"`
+	ForceScale *float64 `xml:"force-scale,attr"` // 1 if not declared.
+}
+
+type BounceEffector struct {
+	Speed float64 `xml:"speed,attr"`
+}
+
+type BuoyancyEffector struct {
+	Density     float64  `xml:"density,attr"`
+	LinearDrag  *float64 `xml:"linear-drag,attr"`  // 1 if not declared.
+	AngularDrag *float64 `xml:"angular-drag,attr"` // 1 if not declared.
+	Flow        string   `xml:"flow,attr"`         // "x,y" tuple of flow velocity, still if empty.
+}
+
+// PointEffector declares a point effector, Mode could be "constant" (default), "inverse-linear" or "inverse-squared".
+type PointEffector struct {
+	Magnitude     float64  `xml:"magnitude,attr"`
+	Mode          string   `xml:"mode,attr"`
+	DistanceScale *float64 `xml:"distance-scale,attr"` // 1 if not declared.
 }
 
 type CameraWrapper struct {
-	Cameras []CameraDetail
+	Cameras []CameraDetail `xml:"camera"`
 }
 
 type CameraDetail struct {
 	RXYAttr
-	Index int `xml:"index,attr"`
+	Index      int                   `xml:"index,attr"`
+	Zoom       float64               `xml:"zoom,attr"`     // 1 if not declared.
+	Rotation   float64               `xml:"rotation,attr"` // degrees, clockwise.
+	Viewport   string                `xml:"viewport,attr"` // "x,y,w,h" normalized area of the window, the whole window if empty.
+	Order      int                   `xml:"order,attr"`    // cameras of higher order are drawn over others.
+	Layers     string                `xml:"layers,attr"`   // comma separated names of layers drawn, "all" if empty.
+	Enabled    *bool                 `xml:"enabled,attr"`  // not changed if not declared, only the first camera is enabled at first.
+	Controller *CameraControllerMeta `xml:"controller"`
+}
+
+// CameraControllerMeta configures how a camera follows targets and shakes. Attributes not declared keep defaults of the controller.
+type CameraControllerMeta struct {
+	Mode         string  `xml:"mode,attr"` // one of "smooth-damp", "lerp" and "instant".
+	Tick         string  `xml:"tick,attr"` // one of "physics" and "render".
+	SmoothTime   float64 `xml:"smooth-time,attr"`
+	LerpSpeed    float64 `xml:"lerp-speed,attr"`
+	Offset       string  `xml:"offset,attr"`   // "x,y" tuple.
+	Deadzone     string  `xml:"deadzone,attr"` // "w,h" tuple.
+	LookAhead    float64 `xml:"look-ahead,attr"`
+	LookAheadMax string  `xml:"look-ahead-max,attr"` // "x,y" tuple.
+	MaxShake     string  `xml:"max-shake,attr"`      // "x,y" tuple.
+	TraumaDecay  float64 `xml:"trauma-decay,attr"`
 }
 
 type Scene struct {
@@ -50,12 +137,55 @@ type ObjectDetailWrapper struct {
 }
 
 type SceneMetas struct {
-	RoomSize RWHAttr       `xml:"room-size"`
-	Cameras  CameraWrapper `xml:"cameras"`
+	RoomSize      RWHAttr           `xml:"room-size"`
+	Cameras       CameraWrapper     `xml:"cameras"`
+	PostProcesses []PostProcessMeta `xml:"post-process"`
+	Parallaxes    []ParallaxMeta    `xml:"parallax"`
+}
+
+// ParallaxMeta declares a parallax background of a scene, whose layers are drawn in order.
+type ParallaxMeta struct {
+	SortingLayer string              `xml:"sorting-layer,attr"` // "background" if empty.
+	Z            int64               `xml:"z,attr"`             // order in the sorting layer.
+	Layer        string              `xml:"layer,attr"`         // name of the layer drawn by cameras, "default" if empty.
+	Layers       []ParallaxLayerMeta `xml:"layer"`
+}
+
+// ParallaxLayerMeta declares an image of a parallax background by the name of a frame.
+type ParallaxLayerMeta struct {
+	Frame      string `xml:"frame,attr"`
+	Scroll     string `xml:"scroll,attr"` // "x,y" tuple of scroll factors, 1,1 if empty, which moves with the world.
+	Offset     string `xml:"offset,attr"` // "x,y" tuple.
+	Scale      string `xml:"scale,attr"`  // "x,y" tuple, 1,1 if empty.
+	RepeatX    bool   `xml:"repeat-x,attr"`
+	RepeatY    bool   `xml:"repeat-y,attr"`
+	AutoScroll string `xml:"auto-scroll,attr"` // "x,y" tuple of pixels per second.
+	Tint       string `xml:"tint,attr"`        // "r,g,b,a" tuple.
+}
+
+// PostProcessMeta declares the chain of effects of a camera in a scene, effects are applied in order.
+type PostProcessMeta struct {
+	Camera  int              `xml:"camera,attr"`
+	Effects []PostEffectMeta `xml:"effect"`
+}
+
+// PostEffectMeta declares a builtin effect of Type, which is one of "color-grading", "vignette", "bloom", "scanlines", "pixelate" and "flash",
+// or an effect drawn with a declared shader of sprite layout. Uniforms override defaults of the effect.
+type PostEffectMeta struct {
+	Type     string        `xml:"type,attr"`
+	Name     string        `xml:"name,attr"` // the type if empty, effects are toggled by names.
+	Shader   string        `xml:"shader,attr"`
+	Enabled  *bool         `xml:"enabled,attr"` // true if not declared.
+	Tint     string        `xml:"tint,attr"`    // "r,g,b,a" tuple, multiplies the result of the effect.
+	Uniforms []UniformMeta `xml:"uniform"`
 }
 
 type FrameMetas struct {
-	Dirs []FrameDir `xml:"dir"`
+	Dirs      []FrameDir      `xml:"dir"`
+	Atlases   []FrameAtlas    `xml:"atlas"`
+	Sheets    []FrameSheet    `xml:"sheet"`
+	Aseprites []FrameAseprite `xml:"aseprite"`
+	Boxes     []FrameBoxes    `xml:"boxes"`
 }
 
 type FrameDir struct {
@@ -63,10 +193,231 @@ type FrameDir struct {
 	Prefix string `xml:"prefix,attr"`
 }
 
+// FrameAtlas declares an atlas written by gxatlas, File is the JSON description relative to the static path.
+type FrameAtlas struct {
+	File   string `xml:"file,attr"`
+	Prefix string `xml:"prefix,attr"`
+}
+
+// FrameSheet declares a sprite sheet relative to the static path, which is sliced by grids and named regions.
+// Frames are named by Prefix followed by names of regions, or names of grids and indices of cells.
+type FrameSheet struct {
+	File    string        `xml:"file,attr"`
+	Prefix  string        `xml:"prefix,attr"`
+	Grids   []SheetGrid   `xml:"grid"`
+	Regions []SheetRegion `xml:"region"`
+}
+
+// SheetGrid slices a sheet into cells. Cell, Margin and Spacing are "x,y" tuples, Margin and Spacing are 0,0 if empty.
+// Count limits the number of cells, all cells are taken if 0. Indices of cells start from Start.
+type SheetGrid struct {
+	Name    string `xml:"name,attr"`
+	Cell    string `xml:"cell,attr"`
+	Margin  string `xml:"margin,attr"`
+	Spacing string `xml:"spacing,attr"`
+	Count   int    `xml:"count,attr"`
+	Start   int    `xml:"start,attr"`
+}
+
+// SheetRegion is a named rectangle of a sheet in pixels.
+type SheetRegion struct {
+	Name string `xml:"name,attr"`
+	X    int    `xml:"x,attr"`
+	Y    int    `xml:"y,attr"`
+	W    int    `xml:"w,attr"`
+	H    int    `xml:"h,attr"`
+}
+
+// FrameAseprite declares a JSON exported by Aseprite relative to the static path.
+// Frames are named by FramePrefix followed by indices, and a sprite is created for each tag named by SpritePrefix followed by the tag.
+type FrameAseprite struct {
+	File         string `xml:"file,attr"`
+	FramePrefix  string `xml:"frame-prefix,attr"`
+	SpritePrefix string `xml:"sprite-prefix,attr"`
+}
+
+// FrameBoxes authors hitboxes and hurtboxes of frames, which are generated from alpha channel if absent.
+// File is an optional JSON relative to the static path, frames in it and in Frames are named by Prefix followed by their names.
+type FrameBoxes struct {
+	File   string           `xml:"file,attr"`
+	Prefix string           `xml:"prefix,attr"`
+	Frames []FrameBoxesMeta `xml:"frame"`
+}
+
+// FrameBoxesMeta declares boxes of a frame, points of a box are "x,y" tuples separated by spaces, in pixels of the frame.
+type FrameBoxesMeta struct {
+	Name      string    `xml:"name,attr"`
+	Hitbox    *BoxMeta  `xml:"hitbox"`
+	Hurtboxes []BoxMeta `xml:"hurtbox"`
+}
+
+type BoxMeta struct {
+	Points string `xml:"points,attr"`
+}
+
 type SpriteMetas struct {
 	Sprites []Sprite `xml:"sprite"`
 }
 
+// FontMetas declares fonts, files are relative to the static path.
+type FontMetas struct {
+	BMFonts   []BMFontMeta   `xml:"bmfont"`
+	TrueTypes []TrueTypeMeta `xml:"truetype"`
+}
+
+// BMFontMeta declares a font in text format of BMFont, whose pages are next to File.
+type BMFontMeta struct {
+	Name string `xml:"name,attr"`
+	File string `xml:"file,attr"`
+}
+
+// TrueTypeMeta declares a TrueType or OpenType font rasterized at Size in pixels.
+// Runes are characters to rasterize, printable ASCII characters if empty.
+type TrueTypeMeta struct {
+	Name  string  `xml:"name,attr"`
+	File  string  `xml:"file,attr"`
+	Size  float64 `xml:"size,attr"`
+	Runes string  `xml:"runes,attr"`
+}
+
+// ShaderMetas declares shaders and materials, files are relative to the static path.
+// Builtin "default" and "color" shaders are replaced by shaders of the same names.
+type ShaderMetas struct {
+	HotReload bool           `xml:"hot-reload,attr"` // recompiles shaders when their files change.
+	Shaders   []ShaderMeta   `xml:"shader"`
+	Materials []MaterialMeta `xml:"material"`
+}
+
+// ShaderMeta declares a shader program, the builtin vertex or fragment file of the layout is used if a file is not given.
+type ShaderMeta struct {
+	Name     string `xml:"name,attr"`
+	Vertex   string `xml:"vertex,attr"`
+	Fragment string `xml:"fragment,attr"`
+	Layout   string `xml:"layout,attr"` // "sprite" or "color", "sprite" if empty.
+}
+
+// MaterialMeta declares a material drawn with a shader of sprite layout.
+type MaterialMeta struct {
+	Name     string        `xml:"name,attr"`
+	Shader   string        `xml:"shader,attr"`
+	Tint     string        `xml:"tint,attr"` // "r,g,b,a" tuple, white if empty.
+	Uniforms []UniformMeta `xml:"uniform"`
+}
+
+// UniformMeta sets a float, vec2, vec3 or vec4 uniform by values separated by commas.
+type UniformMeta struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type ParticleMetas struct {
+	Emitters []EmitterMeta `xml:"emitter"`
+}
+
+// EmitterMeta declares a particle emitter config, attributes not declared keep their defaults.
+// Speed, Lifetime and StartSize are "min,max" tuples, Size and Gravity are "x,y" tuples.
+// Shape could be "point" (default), "circle", "rectangle" or "edge", Space could be "world" (default) or "local".
+type EmitterMeta struct {
+	Name            string         `xml:"name,attr"`
+	Sprite          string         `xml:"sprite,attr"`
+	MaxParticles    *int           `xml:"max-particles,attr"`
+	Duration        *float64       `xml:"duration,attr"`
+	Loop            *bool          `xml:"loop,attr"`
+	Rate            *float64       `xml:"rate,attr"`
+	Shape           string         `xml:"shape,attr"`
+	Radius          float64        `xml:"radius,attr"`
+	Size            string         `xml:"size,attr"`
+	Direction       *float64       `xml:"direction,attr"`
+	Spread          *float64       `xml:"spread,attr"`
+	Speed           string         `xml:"speed,attr"`
+	Lifetime        string         `xml:"lifetime,attr"`
+	StartSize       string         `xml:"start-size,attr"`
+	Gravity         string         `xml:"gravity,attr"`
+	Space           string         `xml:"space,attr"`
+	Collide         bool           `xml:"collide,attr"`
+	Bounce          *float64       `xml:"bounce,attr"`
+	KillOnCollision bool           `xml:"kill-on-collision,attr"`
+	SolidTag        string         `xml:"solid-tag,attr"`
+	Bursts          []BurstMeta    `xml:"burst"`
+	SpeedCurve      []CurveKeyMeta `xml:"speed-curve>key"`
+	SizeCurve       []CurveKeyMeta `xml:"size-curve>key"`
+	ColorGradient   []ColorKeyMeta `xml:"color-gradient>key"`
+}
+
+type BurstMeta struct {
+	Time  float64 `xml:"time,attr"`
+	Count int     `xml:"count,attr"`
+}
+
+// CurveKeyMeta is a value of a curve at normalized time T.
+type CurveKeyMeta struct {
+	T float64 `xml:"t,attr"`
+	V float64 `xml:"v,attr"`
+}
+
+// ColorKeyMeta is a color of a gradient at normalized time T, Color is a "r,g,b,a" tuple in [0, 1].
+type ColorKeyMeta struct {
+	T     float64 `xml:"t,attr"`
+	Color string  `xml:"color,attr"`
+}
+
+type AnimatorMetas struct {
+	Animators []AnimatorMeta `xml:"animator"`
+}
+
+// AnimatorMeta declares an animation state machine, which is entered from the first state if Entry is empty.
+type AnimatorMeta struct {
+	Name        string               `xml:"name,attr"`
+	Entry       string               `xml:"entry,attr"`
+	Params      AnimParams           `xml:"parameters"`
+	States      []AnimStateMeta      `xml:"state"`
+	Transitions []AnimTransitionMeta `xml:"transition"`
+}
+
+type AnimParams struct {
+	Bools    []AnimBoolParam    `xml:"bool"`
+	Floats   []AnimFloatParam   `xml:"float"`
+	Triggers []AnimTriggerParam `xml:"trigger"`
+}
+
+type AnimBoolParam struct {
+	Name    string `xml:"name,attr"`
+	Default bool   `xml:"default,attr"`
+}
+
+type AnimFloatParam struct {
+	Name    string  `xml:"name,attr"`
+	Default float64 `xml:"default,attr"`
+}
+
+type AnimTriggerParam struct {
+	Name string `xml:"name,attr"`
+}
+
+type AnimStateMeta struct {
+	Name   string   `xml:"name,attr"`
+	Sprite string   `xml:"sprite,attr"`
+	Speed  *float64 `xml:"speed,attr"` // 1 if not declared.
+}
+
+// AnimTransitionMeta declares a transition, which comes from any state if From is empty.
+// ExitTime is the normalized time of the source state, no exit time if not declared.
+type AnimTransitionMeta struct {
+	From       string              `xml:"from,attr"`
+	To         string              `xml:"to,attr"`
+	Priority   int                 `xml:"priority,attr"`
+	ExitTime   *float64            `xml:"exit-time,attr"`
+	ToSelf     bool                `xml:"to-self,attr"`
+	Conditions []AnimConditionMeta `xml:"condition"`
+}
+
+// AnimConditionMeta declares a condition, Mode could be "if" (default), "if-not", "greater" or "less".
+type AnimConditionMeta struct {
+	Param     string  `xml:"param,attr"`
+	Mode      string  `xml:"mode,attr"`
+	Threshold float64 `xml:"threshold,attr"`
+}
+
 type ObjectMetas struct {
 	Objects []Object `xml:"object"`
 }
@@ -85,20 +436,44 @@ type Object struct {
 	Name string `xml:"name,attr"`
 }
 
+// Sprite declares a sprite meta, Mode could be "loop" (default), "once", "ping-pong" or "clamp-forever".
 type Sprite struct {
-	Name   string  `xml:"name,attr"`
-	Frames []Frame `xml:"frame"`
+	Name    string  `xml:"name,attr"`
+	Mode    string  `xml:"mode,attr"`
+	Reverse bool    `xml:"reverse,attr"`
+	Frames  []Frame `xml:"frame"`
 }
 
+// Frame declares a frame of a sprite meta.
+// Duration is in milliseconds, the update interval of instances is used if 0. Event is a comma separated list of event names.
 type Frame struct {
-	Name string `xml:"name,attr"`
+	Name     string `xml:"name,attr"`
+	Duration int    `xml:"duration,attr"`
+	Event    string `xml:"event,attr"`
 }
 
 type ApplicationMetas struct {
-	Resolution  RWHAttr `xml:"resolution"`
-	FPS         FPS     `xml:"fps"`
-	Parallelism int     `xml:"parallelism"`
-	Title       string  `xml:"title"`
+	Resolution        RWHAttr `xml:"resolution"`
+	VirtualResolution RWHAttr `xml:"virtual-resolution"` // design resolution of cameras, the resolution if not declared.
+	ScaleMode         string  `xml:"scale-mode"`         // one of "stretch" (default), "letterbox", "integer" and "expand".
+	FPS               FPS     `xml:"fps"`
+	Parallelism       int     `xml:"parallelism"`
+	Title             string  `xml:"title"`
+}
+
+// CollisionSystem chooses the broadphase used by the level.
+// Type could be "quadtree" (default) or "spatial-hash".
+type CollisionSystem struct {
+	Type        string   `xml:"type,attr"`
+	LoadFactor  int      `xml:"load-factor,attr"`  // quadtree only
+	MinDivision float64  `xml:"min-division,attr"` // quadtree only
+	Area        AreaAttr `xml:"area"`              // quadtree only
+	CellSize    RWHAttr  `xml:"cell-size"`         // spatial-hash only
+}
+
+type AreaAttr struct {
+	RXYAttr
+	RWHAttr
 }
 
 type FPS struct {
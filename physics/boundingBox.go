@@ -51,6 +51,16 @@ func (bb BoundingBox) GetHeight() float64 {
 	return bb[BB_BotLeft].Y - bb[BB_TopLeft].Y
 }
 
+// boundingBoxOf builds a bounding box from its min and max coordinates.
+func boundingBoxOf(minX float64, minY float64, maxX float64, maxY float64) BoundingBox {
+	return BoundingBox{
+		{X: maxX, Y: minY}, // top right
+		{X: minX, Y: minY}, // top left
+		{X: minX, Y: maxY}, // bot left
+		{X: maxX, Y: maxY}, // bot right
+	}
+}
+
 func SliceToBoundingBox(vecSlice []linalg.Vector2f64) BoundingBox {
 	array := [4]linalg.Vector2f64{}
 	copy(array[:], vecSlice)
@@ -0,0 +1,94 @@
+package physics
+
+import (
+	"math"
+
+	"galaxyzeta.io/engine/linalg"
+)
+
+// Capsule is a segment inflated by a radius, which is good at describing characters
+// because it slides smoothly over edges and slopes.
+type Capsule struct {
+	Point1      linalg.Vector2f64 // center of the first cap, relative to the anchor.
+	Point2      linalg.Vector2f64 // center of the second cap, relative to the anchor.
+	Radius      float64
+	rotationDeg float64 // rotation around the anchor
+	anchor      *linalg.Vector2f64
+}
+
+func NewCapsule(anchor *linalg.Vector2f64, point1 linalg.Vector2f64, point2 linalg.Vector2f64, radius float64) Capsule {
+	return Capsule{
+		Point1: point1,
+		Point2: point2,
+		Radius: radius,
+		anchor: anchor,
+	}
+}
+
+// NewVerticalCapsule creates an upright capsule that exactly fits into a w*h box whose top-left corner is (left, top).
+func NewVerticalCapsule(anchor *linalg.Vector2f64, left float64, top float64, w float64, h float64) Capsule {
+	radius := w / 2
+	cx := left + radius
+	if h < w {
+		// degenerates into a circle
+		return NewCapsule(anchor, linalg.NewVector2f64(cx, top+h/2), linalg.NewVector2f64(cx, top+h/2), h/2)
+	}
+	return NewCapsule(anchor, linalg.NewVector2f64(cx, top+radius), linalg.NewVector2f64(cx, top+h-radius), radius)
+}
+
+func (c Capsule) GetAnchor() *linalg.Vector2f64 {
+	return c.anchor
+}
+
+// GetWorldSegment returns the core segment of the capsule in world coordinates.
+func (c Capsule) GetWorldSegment() linalg.Segmentf64 {
+	anchor := anchorOf(c.anchor)
+	rotRad := linalg.Deg2Rad(c.rotationDeg)
+	sin, cos := math.Sin(rotRad), math.Cos(rotRad)
+	p1 := linalg.NewVector2f64(c.Point1.X*cos-c.Point1.Y*sin+anchor.X, c.Point1.X*sin+c.Point1.Y*cos+anchor.Y)
+	p2 := linalg.NewVector2f64(c.Point2.X*cos-c.Point2.Y*sin+anchor.X, c.Point2.X*sin+c.Point2.Y*cos+anchor.Y)
+	return linalg.Segmentf64{Point1: p1, Point2: p2}
+}
+
+// Intersect checks whether the capsule overlaps with another shape.
+func (c Capsule) Intersect(shape IShape) bool {
+	return Intersects(c, shape)
+}
+
+// GetBoundingBox returns a bounding box calculated by current conditions.
+func (c Capsule) GetBoundingBox() BoundingBox {
+	seg := c.GetWorldSegment()
+	return boundingBoxOf(
+		math.Min(seg.Point1.X, seg.Point2.X)-c.Radius,
+		math.Min(seg.Point1.Y, seg.Point2.Y)-c.Radius,
+		math.Max(seg.Point1.X, seg.Point2.X)+c.Radius,
+		math.Max(seg.Point1.Y, seg.Point2.Y)+c.Radius,
+	)
+}
+
+// Shift a capsule with given x and y amount in world space, and return the shifted replica of original capsule.
+func (c Capsule) Shift(x float64, y float64) IShape {
+	delta := unrotate(linalg.NewVector2f64(x, y), c.rotationDeg)
+	c.Point1 = c.Point1.Add(delta)
+	c.Point2 = c.Point2.Add(delta)
+	return c
+}
+
+// GetWorldOutline returns vertices around the capsule in world coordinates, each cap is approximated by percision vertices.
+func (c Capsule) GetWorldOutline(percision int) []linalg.Vector2f64 {
+	seg := c.GetWorldSegment()
+	dir := seg.ToVector()
+	base := math.Atan2(dir.Y, dir.X)
+	if dir.Magnitude() == 0 {
+		base = 0
+	}
+	vertices := make([]linalg.Vector2f64, 0, percision*2+2)
+	// cap of Point2 sweeps from one side to the other, then the cap of Point1.
+	for i, center := range []linalg.Vector2f64{seg.Point2, seg.Point1} {
+		for j := 0; j <= percision; j++ {
+			rad := base - math.Pi/2 + math.Pi*float64(j)/float64(percision) + math.Pi*float64(i)
+			vertices = append(vertices, linalg.NewVector2f64(center.X+c.Radius*math.Cos(rad), center.Y+c.Radius*math.Sin(rad)))
+		}
+	}
+	return vertices
+}
@@ -6,24 +6,76 @@ import (
 	"galaxyzeta.io/engine/linalg"
 )
 
+const defaultCirclePercision = 16
+
+// Circle is a native circle shape. Left and Top describe the top-left corner of its bounding square,
+// relative to the anchor, the same way a Rectangle does.
 type Circle struct {
-	Left      float64
-	Top       float64
-	Radius    float64
-	Percision int
+	Left        float64
+	Top         float64
+	Radius      float64
+	Percision   int     // how many vertices will be used when converting to polygon.
+	rotationDeg float64 // rotation around the anchor, only matters when the center is not on the anchor.
+	anchor      *linalg.Vector2f64
+}
+
+func NewCircle(anchor *linalg.Vector2f64, left float64, top float64, radius float64) Circle {
+	return Circle{
+		Left:      left,
+		Top:       top,
+		Radius:    radius,
+		Percision: defaultCirclePercision,
+		anchor:    anchor,
+	}
+}
+
+func (circle Circle) GetAnchor() *linalg.Vector2f64 {
+	return circle.anchor
+}
+
+// GetWorldCenter returns the center of the circle in world coordinates.
+func (circle Circle) GetWorldCenter() linalg.Vector2f64 {
+	anchor := anchorOf(circle.anchor)
+	center := rotate(linalg.NewVector2f64(circle.Left+circle.Radius, circle.Top+circle.Radius), circle.rotationDeg)
+	return center.Add(anchor)
+}
+
+// Intersect checks whether the circle overlaps with another shape.
+func (circle Circle) Intersect(shape IShape) bool {
+	return Intersects(circle, shape)
+}
+
+// GetBoundingBox returns a bounding box calculated by current conditions.
+func (circle Circle) GetBoundingBox() BoundingBox {
+	center := circle.GetWorldCenter()
+	return boundingBoxOf(center.X-circle.Radius, center.Y-circle.Radius, center.X+circle.Radius, center.Y+circle.Radius)
+}
+
+// Shift a circle with given x and y amount in world space, and return the shifted replica of original circle.
+func (circle Circle) Shift(x float64, y float64) IShape {
+	delta := unrotate(linalg.NewVector2f64(x, y), circle.rotationDeg)
+	circle.Left += delta.X
+	circle.Top += delta.Y
+	return circle
 }
 
 // ToPolygon converts a circle into polygon.
 func (circle Circle) ToPolygon() Polygon {
-	var dirDelta float64 = float64(360.0 / circle.Percision)
-	var vertices []linalg.Vector2f64
-	var deg float64 = 0
-	for deg < 360.0 {
-		rad := linalg.Deg2Rad(deg)
-		vertices = append(vertices, linalg.Vector2f64{X: circle.Radius * math.Cos(rad), Y: circle.Radius * math.Sin(rad)})
-		deg += dirDelta
+	percision := circle.Percision
+	if percision < 3 {
+		percision = defaultCirclePercision
+	}
+	dirDelta := 360.0 / float64(percision)
+	cx := circle.Left + circle.Radius
+	cy := circle.Top + circle.Radius
+	vertices := make([]linalg.Vector2f64, 0, percision)
+	for i := 0; i < percision; i++ {
+		rad := linalg.Deg2Rad(dirDelta * float64(i))
+		vertices = append(vertices, linalg.Vector2f64{X: cx + circle.Radius*math.Cos(rad), Y: cy + circle.Radius*math.Sin(rad)})
 	}
 	return Polygon{
-		vertices: vertices,
+		anchor:      circle.anchor,
+		vertices:    vertices,
+		rotationDeg: circle.rotationDeg,
 	}
 }
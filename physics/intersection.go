@@ -0,0 +1,141 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+
+	"galaxyzeta.io/engine/linalg"
+)
+
+// primitive is the world space representation shared by all shapes during narrow phase.
+// Circles and capsules are round shapes: a core segment (degenerated into a point for circles) with a radius.
+// Rectangles and polygons are convex vertex lists.
+type primitive struct {
+	isPolygon bool
+	segment   linalg.Segmentf64
+	radius    float64
+	vertices  []linalg.Vector2f64
+}
+
+func toPrimitive(shape IShape) primitive {
+	switch shape := shape.(type) {
+	case Circle:
+		center := shape.GetWorldCenter()
+		return primitive{segment: linalg.Segmentf64{Point1: center, Point2: center}, radius: shape.Radius}
+	case *Circle:
+		return toPrimitive(*shape)
+	case Capsule:
+		return primitive{segment: shape.GetWorldSegment(), radius: shape.Radius}
+	case *Capsule:
+		return toPrimitive(*shape)
+	case Rectangle:
+		return primitive{isPolygon: true, vertices: shape.GetWorldVertices()}
+	case *Rectangle:
+		return toPrimitive(*shape)
+	case Polygon:
+		return primitive{isPolygon: true, vertices: shape.GetWorldVertices()}
+	case *Polygon:
+		return toPrimitive(*shape)
+	}
+	panic(fmt.Sprintf("unsupported shape %T", shape))
+}
+
+// Intersects is the exact pairwise overlap test between any two shapes.
+// Touching shapes are considered as overlapped, so that tiles sharing edges are found by each other.
+func Intersects(a IShape, b IShape) bool {
+	pa := toPrimitive(a)
+	pb := toPrimitive(b)
+	switch {
+	case pa.isPolygon && pb.isPolygon:
+		return intersectPolygonPolygon(pa.vertices, pb.vertices)
+	case pa.isPolygon:
+		return intersectRoundPolygon(pb, pa.vertices)
+	case pb.isPolygon:
+		return intersectRoundPolygon(pa, pb.vertices)
+	}
+	return segmentDistance(pa.segment, pb.segment) <= pa.radius+pb.radius
+}
+
+// intersectPolygonPolygon uses the separating axis theorem on two convex polygons.
+func intersectPolygonPolygon(vertices1 []linalg.Vector2f64, vertices2 []linalg.Vector2f64) bool {
+	return !hasSeparatingAxis(vertices1, vertices1, vertices2) && !hasSeparatingAxis(vertices2, vertices1, vertices2)
+}
+
+// hasSeparatingAxis tests all edge normals of the edge provider.
+func hasSeparatingAxis(edgeProvider []linalg.Vector2f64, vertices1 []linalg.Vector2f64, vertices2 []linalg.Vector2f64) bool {
+	for i := range edgeProvider {
+		j := (i + 1) % len(edgeProvider)
+		axis := edgeProvider[j].Sub(edgeProvider[i]).NormalVec()
+		if !overlap(projectVertices(vertices1, axis), projectVertices(vertices2, axis)) {
+			return true
+		}
+	}
+	return false
+}
+
+func projectVertices(vertices []linalg.Vector2f64, axis linalg.Vector2f64) linalg.Vector2f64 {
+	min := vertices[0].Dot(axis)
+	max := min
+	for i := 1; i < len(vertices); i++ {
+		dotProduct := vertices[i].Dot(axis)
+		if dotProduct < min {
+			min = dotProduct
+		} else if dotProduct > max {
+			max = dotProduct
+		}
+	}
+	return linalg.Vector2f64{X: min, Y: max}
+}
+
+// intersectRoundPolygon tests a circle or capsule against a convex polygon.
+func intersectRoundPolygon(round primitive, vertices []linalg.Vector2f64) bool {
+	if pointInConvexPolygon(round.segment.Point1, vertices) || pointInConvexPolygon(round.segment.Point2, vertices) {
+		return true
+	}
+	minDist := math.Inf(1)
+	for i := range vertices {
+		edge := linalg.Segmentf64{Point1: vertices[i], Point2: vertices[(i+1)%len(vertices)]}
+		minDist = math.Min(minDist, segmentDistance(round.segment, edge))
+	}
+	return minDist <= round.radius
+}
+
+// pointInConvexPolygon tells whether p is strictly inside the polygon, regardless of its winding order.
+func pointInConvexPolygon(p linalg.Vector2f64, vertices []linalg.Vector2f64) bool {
+	if len(vertices) < 3 {
+		return false
+	}
+	sign := 0.0
+	for i := range vertices {
+		edge := vertices[(i+1)%len(vertices)].Sub(vertices[i])
+		cross := edge.Mult(p.Sub(vertices[i]))
+		if cross == 0 || sign*cross < 0 {
+			return false
+		}
+		sign = cross
+	}
+	return true
+}
+
+// segmentDistance returns the shortest distance between two segments.
+func segmentDistance(s1 linalg.Segmentf64, s2 linalg.Segmentf64) float64 {
+	if segmentsCross(s1, s2) {
+		return 0
+	}
+	return math.Min(
+		math.Min(s2.DistanceToPoint(s1.Point1), s2.DistanceToPoint(s1.Point2)),
+		math.Min(s1.DistanceToPoint(s2.Point1), s1.DistanceToPoint(s2.Point2)),
+	)
+}
+
+// segmentsCross tells whether two segments properly cross each other.
+// Touching cases are covered by point to segment distances.
+func segmentsCross(s1 linalg.Segmentf64, s2 linalg.Segmentf64) bool {
+	v1 := s1.ToVector()
+	v2 := s2.ToVector()
+	d1 := v1.Mult(s2.Point1.Sub(s1.Point1))
+	d2 := v1.Mult(s2.Point2.Sub(s1.Point1))
+	d3 := v2.Mult(s1.Point1.Sub(s2.Point1))
+	d4 := v2.Mult(s1.Point2.Sub(s2.Point1))
+	return d1*d2 < 0 && d3*d4 < 0
+}
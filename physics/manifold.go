@@ -0,0 +1,271 @@
+package physics
+
+import (
+	"math"
+
+	"galaxyzeta.io/engine/linalg"
+)
+
+const manifoldEpsilon = 1e-9
+
+// Manifold describes how two overlapped shapes contact with each other.
+type Manifold struct {
+	Normal   linalg.Vector2f64   // unit vector pointing from shape A to shape B.
+	Depth    float64             // penetration depth along the normal.
+	Contacts []linalg.Vector2f64 // contact points in world space, at most 2.
+}
+
+// Collide computes the contact manifold of two shapes, ok is false when they do not overlap.
+func Collide(a IShape, b IShape) (m Manifold, ok bool) {
+	pa := toPrimitive(a)
+	pb := toPrimitive(b)
+	switch {
+	case pa.isPolygon && pb.isPolygon:
+		return collidePolygonPolygon(pa.vertices, pb.vertices)
+	case pa.isPolygon:
+		m, ok = collideRoundPolygon(pb, pa.vertices)
+		m.Normal = m.Normal.Scale(-1)
+		return m, ok
+	case pb.isPolygon:
+		return collideRoundPolygon(pa, pb.vertices)
+	}
+	return collideRoundRound(pa, pb)
+}
+
+func collideRoundRound(a primitive, b primitive) (Manifold, bool) {
+	pa, pb := closestPointsOfSegments(a.segment, b.segment)
+	delta := pb.Sub(pa)
+	dist := delta.Magnitude()
+	radius := a.radius + b.radius
+	if dist >= radius {
+		return Manifold{}, false
+	}
+	var normal linalg.Vector2f64
+	if dist > manifoldEpsilon {
+		normal = delta.Scale(1 / dist)
+	} else {
+		// core segments cross each other, separate them by their centers.
+		normal = fallbackNormal(segmentCenter(a.segment), segmentCenter(b.segment))
+	}
+	depth := radius - dist
+	return Manifold{
+		Normal:   normal,
+		Depth:    depth,
+		Contacts: []linalg.Vector2f64{pa.Add(normal.Scale(a.radius - depth/2))},
+	}, true
+}
+
+// collideRoundPolygon collides a circle or capsule (A) with a convex polygon (B).
+func collideRoundPolygon(round primitive, vertices []linalg.Vector2f64) (Manifold, bool) {
+	seg := round.segment
+	normals := outwardNormals(vertices)
+	if !pointInConvexPolygon(seg.Point1, vertices) && !pointInConvexPolygon(seg.Point2, vertices) {
+		minDist := math.Inf(1)
+		var onRound, onPoly linalg.Vector2f64
+		for i := range vertices {
+			edge := linalg.Segmentf64{Point1: vertices[i], Point2: vertices[(i+1)%len(vertices)]}
+			pr, pp := closestPointsOfSegments(seg, edge)
+			if dist := pp.Sub(pr).Magnitude(); dist < minDist {
+				minDist, onRound, onPoly = dist, pr, pp
+			}
+		}
+		if minDist >= round.radius {
+			return Manifold{}, false
+		}
+		if minDist > manifoldEpsilon {
+			return Manifold{
+				Normal:   onPoly.Sub(onRound).Scale(1 / minDist),
+				Depth:    round.radius - minDist,
+				Contacts: []linalg.Vector2f64{onPoly},
+			}, true
+		}
+		// the core segment touches or crosses the boundary, fall through.
+	}
+	// the core is inside of the polygon, push it out through the face with least penetration.
+	bestSep := math.Inf(-1)
+	bestIdx := -1
+	var deepest linalg.Vector2f64
+	for i, n := range normals {
+		if n == (linalg.Vector2f64{}) {
+			continue
+		}
+		sep1 := seg.Point1.Sub(vertices[i]).Dot(n)
+		sep2 := seg.Point2.Sub(vertices[i]).Dot(n)
+		sep, point := sep1, seg.Point1
+		if sep2 < sep1 {
+			sep, point = sep2, seg.Point2
+		}
+		if sep > bestSep {
+			bestSep, bestIdx, deepest = sep, i, point
+		}
+	}
+	if bestIdx < 0 {
+		return Manifold{}, false
+	}
+	n := normals[bestIdx]
+	return Manifold{
+		Normal:   n.Scale(-1),
+		Depth:    round.radius - bestSep,
+		Contacts: []linalg.Vector2f64{deepest.Sub(n.Scale(bestSep))},
+	}, true
+}
+
+// collidePolygonPolygon finds the reference face by SAT, and clips the incident face against it.
+func collidePolygonPolygon(a []linalg.Vector2f64, b []linalg.Vector2f64) (Manifold, bool) {
+	normalsA := outwardNormals(a)
+	penA, faceA := leastPenetration(a, normalsA, b)
+	if penA >= 0 {
+		return Manifold{}, false
+	}
+	normalsB := outwardNormals(b)
+	penB, faceB := leastPenetration(b, normalsB, a)
+	if penB >= 0 {
+		return Manifold{}, false
+	}
+
+	ref, refNormals, refIdx, inc, incNormals := a, normalsA, faceA, b, normalsB
+	flip := false
+	// prefer A as the reference polygon to keep contacts stable between frames.
+	if penA < penB*0.95+penA*0.01 {
+		ref, refNormals, refIdx, inc, incNormals = b, normalsB, faceB, a, normalsA
+		flip = true
+	}
+	refNormal := refNormals[refIdx]
+
+	// incident face is the one most anti-parallel to the reference normal.
+	incIdx := 0
+	minDot := math.Inf(1)
+	for i, n := range incNormals {
+		if d := n.Dot(refNormal); d < minDot {
+			minDot, incIdx = d, i
+		}
+	}
+	incFace := []linalg.Vector2f64{inc[incIdx], inc[(incIdx+1)%len(inc)]}
+
+	v1 := ref[refIdx]
+	v2 := ref[(refIdx+1)%len(ref)]
+	sideNormal := v2.Sub(v1).Normalize()
+	incFace = clipSegment(sideNormal.Scale(-1), -sideNormal.Dot(v1), incFace)
+	if len(incFace) < 2 {
+		return Manifold{}, false
+	}
+	incFace = clipSegment(sideNormal, sideNormal.Dot(v2), incFace)
+	if len(incFace) < 2 {
+		return Manifold{}, false
+	}
+
+	refC := refNormal.Dot(v1)
+	m := Manifold{Normal: refNormal}
+	for _, p := range incFace {
+		if sep := refNormal.Dot(p) - refC; sep <= 0 {
+			m.Contacts = append(m.Contacts, p)
+			m.Depth = math.Max(m.Depth, -sep)
+		}
+	}
+	if len(m.Contacts) == 0 {
+		return Manifold{}, false
+	}
+	if flip {
+		m.Normal = m.Normal.Scale(-1)
+	}
+	return m, true
+}
+
+// leastPenetration returns the face of a with maximum separation against b, negative separation means penetration.
+func leastPenetration(a []linalg.Vector2f64, normals []linalg.Vector2f64, b []linalg.Vector2f64) (float64, int) {
+	bestSep := math.Inf(-1)
+	bestIdx := 0
+	for i, n := range normals {
+		if n == (linalg.Vector2f64{}) {
+			continue
+		}
+		// support point of b along -n
+		support := b[0]
+		for _, v := range b[1:] {
+			if v.Dot(n) < support.Dot(n) {
+				support = v
+			}
+		}
+		if sep := support.Sub(a[i]).Dot(n); sep > bestSep {
+			bestSep, bestIdx = sep, i
+		}
+	}
+	return bestSep, bestIdx
+}
+
+// clipSegment keeps the part of a segment where dot(n, p) <= c.
+func clipSegment(n linalg.Vector2f64, c float64, face []linalg.Vector2f64) []linalg.Vector2f64 {
+	ret := make([]linalg.Vector2f64, 0, 2)
+	d1 := n.Dot(face[0]) - c
+	d2 := n.Dot(face[1]) - c
+	if d1 <= 0 {
+		ret = append(ret, face[0])
+	}
+	if d2 <= 0 {
+		ret = append(ret, face[1])
+	}
+	if d1*d2 < 0 {
+		alpha := d1 / (d1 - d2)
+		ret = append(ret, face[0].Add(face[1].Sub(face[0]).Scale(alpha)))
+	}
+	return ret
+}
+
+// outwardNormals returns unit outward normals of each edge, regardless of the winding order.
+// Normal of a degenerated edge is zero.
+func outwardNormals(vertices []linalg.Vector2f64) []linalg.Vector2f64 {
+	area := 0.0
+	for i := range vertices {
+		area += vertices[i].Mult(vertices[(i+1)%len(vertices)])
+	}
+	normals := make([]linalg.Vector2f64, len(vertices))
+	for i := range vertices {
+		edge := vertices[(i+1)%len(vertices)].Sub(vertices[i])
+		length := edge.Magnitude()
+		if length == 0 {
+			continue
+		}
+		if area > 0 {
+			normals[i] = linalg.NewVector2f64(edge.Y/length, -edge.X/length)
+		} else {
+			normals[i] = linalg.NewVector2f64(-edge.Y/length, edge.X/length)
+		}
+	}
+	return normals
+}
+
+// closestPointsOfSegments returns the closest pair of points on two segments.
+func closestPointsOfSegments(s1 linalg.Segmentf64, s2 linalg.Segmentf64) (linalg.Vector2f64, linalg.Vector2f64) {
+	if segmentsCross(s1, s2) {
+		v1 := s1.ToVector()
+		v2 := s2.ToVector()
+		t := s2.Point1.Sub(s1.Point1).Mult(v2) / v1.Mult(v2)
+		p := s1.Point1.Add(v1.Scale(t))
+		return p, p
+	}
+	p1, p2 := s1.Point1, s2.ClosestPoint(s1.Point1)
+	best := p2.Sub(p1).Magnitude()
+	if q := s2.ClosestPoint(s1.Point2); q.Sub(s1.Point2).Magnitude() < best {
+		p1, p2, best = s1.Point2, q, q.Sub(s1.Point2).Magnitude()
+	}
+	if q := s1.ClosestPoint(s2.Point1); q.Sub(s2.Point1).Magnitude() < best {
+		p1, p2, best = q, s2.Point1, q.Sub(s2.Point1).Magnitude()
+	}
+	if q := s1.ClosestPoint(s2.Point2); q.Sub(s2.Point2).Magnitude() < best {
+		p1, p2 = q, s2.Point2
+	}
+	return p1, p2
+}
+
+func segmentCenter(s linalg.Segmentf64) linalg.Vector2f64 {
+	return s.Point1.Add(s.Point2).Scale(0.5)
+}
+
+// fallbackNormal is used when two shapes are exactly overlapped, any direction works.
+func fallbackNormal(from linalg.Vector2f64, to linalg.Vector2f64) linalg.Vector2f64 {
+	delta := to.Sub(from)
+	if length := delta.Magnitude(); length > manifoldEpsilon {
+		return delta.Scale(1 / length)
+	}
+	return linalg.NewVector2f64(0, 1)
+}
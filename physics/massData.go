@@ -0,0 +1,93 @@
+package physics
+
+import (
+	"fmt"
+
+	"galaxyzeta.io/engine/linalg"
+)
+
+// Centroid returns the center of mass of a shape with uniform density in world space.
+func Centroid(shape IShape) linalg.Vector2f64 {
+	switch shape := shape.(type) {
+	case Circle:
+		return shape.GetWorldCenter()
+	case *Circle:
+		return Centroid(*shape)
+	case Capsule:
+		seg := shape.GetWorldSegment()
+		return seg.Point1.Add(seg.Point2).Scale(0.5)
+	case *Capsule:
+		return Centroid(*shape)
+	case Rectangle:
+		w := shape.GetWorldRect()
+		return linalg.NewVector2f64(w.Left+w.Width/2, w.Top+w.Height/2)
+	case *Rectangle:
+		return Centroid(*shape)
+	case Polygon:
+		return polygonCentroid(shape.GetWorldVertices())
+	case *Polygon:
+		return Centroid(*shape)
+	}
+	panic(fmt.Sprintf("unsupported shape %T", shape))
+}
+
+// MomentOfInertia returns the moment of inertia of a shape with uniform density, rotating around its centroid.
+func MomentOfInertia(shape IShape, mass float64) float64 {
+	switch shape := shape.(type) {
+	case Circle:
+		return mass * shape.Radius * shape.Radius / 2
+	case *Circle:
+		return MomentOfInertia(*shape, mass)
+	case Capsule:
+		// approximated by its bounding box along the core segment.
+		w := shape.Radius * 2
+		h := shape.Point2.Sub(shape.Point1).Magnitude() + w
+		return mass * (w*w + h*h) / 12
+	case *Capsule:
+		return MomentOfInertia(*shape, mass)
+	case Rectangle:
+		return mass * (shape.Width*shape.Width + shape.Height*shape.Height) / 12
+	case *Rectangle:
+		return MomentOfInertia(*shape, mass)
+	case Polygon:
+		// sum up triangles formed by the anchor and each edge, and then move the axis to the centroid.
+		vertices := shape.localVertices()
+		var numerator, denominator float64
+		for i := range vertices {
+			p1 := vertices[i]
+			p2 := vertices[(i+1)%len(vertices)]
+			cross := p1.Mult(p2)
+			numerator += cross * (p1.Dot(p1) + p1.Dot(p2) + p2.Dot(p2))
+			denominator += cross
+		}
+		if denominator == 0 {
+			return 0
+		}
+		center := polygonCentroid(vertices)
+		return mass*numerator/(6*denominator) - mass*center.Dot(center)
+	case *Polygon:
+		return MomentOfInertia(*shape, mass)
+	}
+	panic(fmt.Sprintf("unsupported shape %T", shape))
+}
+
+// polygonCentroid returns the centroid of the area enclosed by vertices, or their average if the area is empty.
+func polygonCentroid(vertices []linalg.Vector2f64) linalg.Vector2f64 {
+	var sum linalg.Vector2f64
+	var area float64
+	for i := range vertices {
+		p1 := vertices[i]
+		p2 := vertices[(i+1)%len(vertices)]
+		cross := p1.Mult(p2)
+		area += cross
+		sum = sum.Add(p1.Add(p2).Scale(cross))
+	}
+	if area == 0 {
+		var avg linalg.Vector2f64
+		for _, p := range vertices {
+			avg = avg.Add(p)
+		}
+		return avg.Scale(1 / float64(len(vertices)))
+	}
+	return sum.Scale(1 / (3 * area))
+}
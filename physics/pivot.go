@@ -46,3 +46,12 @@ func (o PivotOption) GetPivotPoint(bb BoundingBox) linalg.Vector2f64 {
 	}
 	panic("unknown pivot type")
 }
+
+// GetPointOfBox returns the pivot point of a w*h box whose top-left corner is the origin.
+// A fixed Point always takes precedence over the Option.
+func (p Pivot) GetPointOfBox(w float64, h float64) linalg.Vector2f64 {
+	if p.Point != nil {
+		return *p.Point
+	}
+	return p.Option.GetPivotPoint(boundingBoxOf(0, 0, w, h))
+}
@@ -1,8 +1,6 @@
 package physics
 
 import (
-	"math"
-
 	"galaxyzeta.io/engine/linalg"
 )
 
@@ -39,46 +37,23 @@ func (poly Polygon) GetAnchor() *linalg.Vector2f64 {
 // GetWorldVertices converts a polygon to world coordinates system.
 func (poly Polygon) GetWorldVertices() []linalg.Vector2f64 {
 	verticesReplica := make([]linalg.Vector2f64, len(poly.vertices))
-	copy(verticesReplica[:], poly.vertices)
-	rotRad := linalg.Deg2Rad(poly.rotationDeg)
-	var anchorX float64 = 0
-	var anchorY float64 = 0
-	if poly.anchor != nil {
-		anchorX = poly.anchor.X
-		anchorY = poly.anchor.Y
-	}
-	for idx, vertice := range verticesReplica {
-		x := (vertice.X-poly.pivot.X)*math.Cos(rotRad) - (vertice.Y-poly.pivot.Y)*math.Sin(rotRad) + anchorX
-		y := (vertice.X-poly.pivot.X)*math.Sin(rotRad) + (vertice.Y-poly.pivot.Y)*math.Cos(rotRad) + anchorY
-		verticesReplica[idx].X = x
-		verticesReplica[idx].Y = y
+	m := poly.localToWorld()
+	for idx, vertice := range poly.vertices {
+		verticesReplica[idx] = m.MulPoint(vertice)
 	}
 	return verticesReplica
 }
 
-// Intersect checks whether two polygons overlaps with eachother.
-func (poly Polygon) Intersect(poly2 Polygon) bool {
-	vertices := poly.GetWorldVertices()
-	vertices2 := poly2.GetWorldVertices()
-	for i := 1; i < len(vertices); i++ {
-		edgeVec := linalg.Vector2f64{X: vertices[i].X - vertices[i-1].X, Y: vertices[i].Y - vertices[i-1].Y}
-		axis := edgeVec.NormalVec()
-		seg0 := poly2.ProjectOn(axis)
-		seg1 := poly.ProjectOn(axis)
-		if !overlap(seg0, seg1) {
-			return false
-		}
-	}
-	for i := 1; i < len(vertices2); i++ {
-		edgeVec := linalg.Vector2f64{X: vertices2[i].X - vertices2[i-1].X, Y: vertices2[i].Y - vertices2[i-1].Y}
-		axis := edgeVec.NormalVec()
-		seg0 := poly2.ProjectOn(axis)
-		seg1 := poly.ProjectOn(axis)
-		if !overlap(seg0, seg1) {
-			return false
-		}
-	}
-	return true
+// localToWorld returns the matrix that moves the pivot onto the origin, rotates, and then moves the origin onto the anchor.
+func (poly Polygon) localToWorld() linalg.Mat3 {
+	m := linalg.NewTransformMat3(anchorOf(poly.anchor), poly.rotationDeg, linalg.NewVector2f64(1, 1))
+	return m.Mul(linalg.NewTranslateMat3(poly.pivot.Scale(-1)))
+}
+
+// Intersect checks whether the polygon overlaps with another shape.
+// Polygon is assumed to be convex.
+func (poly Polygon) Intersect(shape IShape) bool {
+	return Intersects(poly, shape)
 }
 
 // GetBoundingBox returns a bounding box calculated by current conditions.
@@ -86,7 +61,7 @@ func (poly Polygon) GetBoundingBox() BoundingBox {
 	currentWorldVetices := poly.GetWorldVertices()
 	var minX, minY, maxX, maxY float64
 	minX = currentWorldVetices[0].X
-	maxX = minY
+	maxX = minX
 	minY = currentWorldVetices[0].Y
 	maxY = minY
 	for i := 1; i < len(currentWorldVetices); i++ {
@@ -126,25 +101,70 @@ func (poly Polygon) ProjectOn(axis linalg.Vector2f64) linalg.Vector2f64 {
 	return linalg.Vector2f64{X: min, Y: max}
 }
 
-// Shift a polygon's original vertices with given x and y amount, and return the shifted replica of original polygon.
-func (poly Polygon) Shift(x float64, y float64) Polygon {
+// Shift a polygon with given x and y amount in world space, and return the shifted replica of original polygon.
+func (poly Polygon) Shift(x float64, y float64) IShape {
 	replica := poly
 	replicatedArray := make([]linalg.Vector2f64, len(poly.vertices))
 	copy(replicatedArray, poly.vertices)
 	replica.vertices = replicatedArray
+	delta := unrotate(linalg.NewVector2f64(x, y), poly.rotationDeg)
 	for idx, vertice := range replica.vertices {
-		replica.vertices[idx] = vertice.Add(linalg.NewVector2f64(x, y))
+		replica.vertices[idx] = vertice.Add(delta)
 	}
 	return replica
 }
 
-// overlap judges whether two segments on a same axis overlaps.
+// Transformed returns a replica of the polygon scaled around its pivot, and then rotated around its anchor by deg.
+// The transformation is absolute, so it should be applied to the untransformed polygon.
+func (poly Polygon) Transformed(deg float64, scale linalg.Vector2f64) Polygon {
+	poly.rotationDeg = deg
+	if scale == (linalg.Vector2f64{X: 1, Y: 1}) {
+		return poly
+	}
+	n := len(poly.vertices)
+	vertices := make([]linalg.Vector2f64, n)
+	// flipping on one axis reverses the winding, which is restored by reversing vertices.
+	flip := scale.X*scale.Y < 0
+	for idx, vertice := range poly.vertices {
+		local := vertice.Sub(poly.pivot)
+		scaled := linalg.NewVector2f64(local.X*scale.X, local.Y*scale.Y)
+		if flip {
+			vertices[n-1-idx] = scaled
+		} else {
+			vertices[idx] = scaled
+		}
+	}
+	poly.vertices = vertices
+	poly.pivot = linalg.Vector2f64{}
+	return poly
+}
+
+// unrotate rotates a world space delta back into the local space of a shape rotated by deg.
+func unrotate(delta linalg.Vector2f64, deg float64) linalg.Vector2f64 {
+	return rotate(delta, -deg)
+}
+
+// rotate rotates a vector around the origin by deg.
+func rotate(v linalg.Vector2f64, deg float64) linalg.Vector2f64 {
+	return v.Rotate(deg)
+}
+
+// localVertices returns vertices relative to the anchor, without rotation.
+func (poly Polygon) localVertices() []linalg.Vector2f64 {
+	ret := make([]linalg.Vector2f64, len(poly.vertices))
+	for idx, vertice := range poly.vertices {
+		ret[idx] = vertice.Sub(poly.pivot)
+	}
+	return ret
+}
+
+// overlap judges whether two segments on a same axis overlaps, touching ends are counted.
 func overlap(a linalg.Vector2f64, b linalg.Vector2f64) bool {
 	leftMost, rightMost := a, b
 	if a.X > b.X {
 		leftMost, rightMost = b, a
 	}
-	if rightMost.X >= leftMost.Y {
+	if rightMost.X > leftMost.Y {
 		return false
 	}
 	return true
@@ -2,11 +2,13 @@ package physics
 
 import "galaxyzeta.io/engine/linalg"
 
+// Rectangle is an axis aligned box. When it has an anchor, Left and Top are relative to the anchor.
 type Rectangle struct {
 	Width  float64
 	Height float64
 	Left   float64
 	Top    float64
+	anchor *linalg.Vector2f64
 }
 
 func NewRectangle(left float64, top float64, w float64, h float64) Rectangle {
@@ -18,15 +20,57 @@ func NewRectangle(left float64, top float64, w float64, h float64) Rectangle {
 	}
 }
 
+// NewAnchoredRectangle creates an axis aligned box that moves with the given anchor.
+func NewAnchoredRectangle(anchor *linalg.Vector2f64, left float64, top float64, w float64, h float64) Rectangle {
+	rect := NewRectangle(left, top, w, h)
+	rect.anchor = anchor
+	return rect
+}
+
+func (rect Rectangle) GetAnchor() *linalg.Vector2f64 {
+	return rect.anchor
+}
+
+// GetWorldRect returns the rectangle in world coordinates, which has no anchor.
+func (rect Rectangle) GetWorldRect() Rectangle {
+	anchor := anchorOf(rect.anchor)
+	return NewRectangle(rect.Left+anchor.X, rect.Top+anchor.Y, rect.Width, rect.Height)
+}
+
+// GetWorldVertices returns 4 corners in world coordinates, clockwise from the top-left one.
+func (rect Rectangle) GetWorldVertices() []linalg.Vector2f64 {
+	w := rect.GetWorldRect()
+	return []linalg.Vector2f64{
+		{X: w.Left, Y: w.Top},
+		{X: w.Left + w.Width, Y: w.Top},
+		{X: w.Left + w.Width, Y: w.Top + w.Height},
+		{X: w.Left, Y: w.Top + w.Height},
+	}
+}
+
+// Intersect checks whether the rectangle overlaps with another shape.
+// Rectangles are tested by IntersectWithRectangle, which is cheaper and counts touching edges as well.
 func (rect Rectangle) Intersect(shape IShape) bool {
-	switch shape := shape.(type) {
+	switch another := shape.(type) {
 	case Rectangle:
-		return rect.IntersectWithRectangle(shape)
+		return rect.IntersectWithRectangle(another)
 	case *Rectangle:
-		return rect.IntersectWithRectangle(*shape)
+		return rect.IntersectWithRectangle(*another)
 	}
+	return Intersects(rect, shape)
+}
 
-	return false
+// GetBoundingBox returns a bounding box calculated by current conditions.
+func (rect Rectangle) GetBoundingBox() BoundingBox {
+	w := rect.GetWorldRect()
+	return boundingBoxOf(w.Left, w.Top, w.Left+w.Width, w.Top+w.Height)
+}
+
+// Shift a rectangle with given x and y amount, and return the shifted replica of original rectangle.
+func (rect Rectangle) Shift(x float64, y float64) IShape {
+	rect.Left += x
+	rect.Top += y
+	return rect
 }
 
 func (rect Rectangle) InsideRectangle(anotherRect Rectangle) bool {
@@ -37,7 +81,10 @@ func (rect Rectangle) InsideRectangle(anotherRect Rectangle) bool {
 	return rect.Left >= anotherRect.Left && thisRight <= anotherRight && rect.Top <= anotherRect.Top && thisBottom >= anotherBottom
 }
 
+// IntersectWithRectangle checks whether two rectangles overlap, touching edges are counted as intersected.
 func (rect Rectangle) IntersectWithRectangle(anotherRect Rectangle) bool {
+	rect = rect.GetWorldRect()
+	anotherRect = anotherRect.GetWorldRect()
 	anotherRight := anotherRect.Left + anotherRect.Width
 	anotherBottom := anotherRect.Top + anotherRect.Height
 	thisRight := rect.Left + rect.Width
@@ -63,6 +110,7 @@ func (rect Rectangle) ToPolygon() Polygon {
 		linalg.Vector2f64{X: rect.Left + rect.Width, Y: rect.Top + rect.Height},
 		linalg.Vector2f64{X: rect.Left, Y: rect.Top + rect.Height})
 	return Polygon{
+		anchor:   rect.anchor,
 		vertices: vertices,
 	}
 }
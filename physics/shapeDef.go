@@ -1,11 +1,19 @@
 package physics
 
 import (
+	"fmt"
+	"math"
+
 	"galaxyzeta.io/engine/linalg"
 )
 
+// IShape is implemented by every collision shape: Circle, Capsule, Rectangle (AABB) and convex Polygon.
+// All shapes are described in a local space and placed into the world by an optional anchor.
 type IShape interface {
-	Intersect(shape IShape) bool
+	Intersect(shape IShape) bool       // Intersect tests whether two shapes overlap. Touching shapes overlap.
+	GetBoundingBox() BoundingBox       // GetBoundingBox returns the world space bounding box.
+	GetAnchor() *linalg.Vector2f64     // GetAnchor returns the base point the shape is attached to, might be nil.
+	Shift(x float64, y float64) IShape // Shift returns a replica of the shape moved by x and y in world space.
 }
 
 type Rotation struct {
@@ -17,3 +25,98 @@ type Point struct {
 	X float64
 	Y float64
 }
+
+// anchorOf dereferences an anchor, nil anchor means the world origin.
+func anchorOf(anchor *linalg.Vector2f64) linalg.Vector2f64 {
+	if anchor == nil {
+		return linalg.Vector2f64{}
+	}
+	return *anchor
+}
+
+// WithRotation returns a replica of the shape rotated around its anchor by deg.
+// The rotation is absolute, which means rotating a rotated shape does not accumulate.
+// Rectangle is axis aligned, so it will be converted into Polygon once rotated.
+func WithRotation(shape IShape, deg float64) IShape {
+	switch shape := shape.(type) {
+	case Circle:
+		shape.rotationDeg = deg
+		return shape
+	case *Circle:
+		return WithRotation(*shape, deg)
+	case Capsule:
+		shape.rotationDeg = deg
+		return shape
+	case *Capsule:
+		return WithRotation(*shape, deg)
+	case Rectangle:
+		if deg == 0 {
+			return shape
+		}
+		poly := shape.ToPolygon()
+		poly.rotationDeg = deg
+		return poly
+	case *Rectangle:
+		return WithRotation(*shape, deg)
+	case Polygon:
+		shape.rotationDeg = deg
+		return shape
+	case *Polygon:
+		return WithRotation(*shape, deg)
+	}
+	panic(fmt.Sprintf("unsupported shape %T", shape))
+}
+
+// WithTransform returns a replica of the shape scaled in its local space, and then rotated around its anchor by deg.
+// Like WithRotation, the transformation is absolute, so it should be applied to the untransformed shape.
+// Rectangle is converted into Polygon once rotated, and Circle is converted into Polygon if scaled unevenly.
+func WithTransform(shape IShape, deg float64, scale linalg.Vector2f64) IShape {
+	if scale == (linalg.Vector2f64{X: 1, Y: 1}) {
+		return WithRotation(shape, deg)
+	}
+	switch shape := shape.(type) {
+	case Circle:
+		if math.Abs(scale.X) != math.Abs(scale.Y) {
+			return WithTransform(shape.ToPolygon(), deg, scale)
+		}
+		center := linalg.NewVector2f64((shape.Left+shape.Radius)*scale.X, (shape.Top+shape.Radius)*scale.Y)
+		shape.Radius *= math.Abs(scale.X)
+		shape.Left = center.X - shape.Radius
+		shape.Top = center.Y - shape.Radius
+		return WithRotation(shape, deg)
+	case *Circle:
+		return WithTransform(*shape, deg, scale)
+	case Capsule:
+		// the radius is scaled across the core segment.
+		across := linalg.NewVector2f64(math.Abs(scale.X), math.Abs(scale.Y)).Magnitude() / math.Sqrt2
+		if dir := shape.Point2.Sub(shape.Point1); dir != (linalg.Vector2f64{}) {
+			n := dir.Normalize().NormalVec()
+			across = linalg.NewVector2f64(n.X*scale.X, n.Y*scale.Y).Magnitude()
+		}
+		shape.Point1 = linalg.NewVector2f64(shape.Point1.X*scale.X, shape.Point1.Y*scale.Y)
+		shape.Point2 = linalg.NewVector2f64(shape.Point2.X*scale.X, shape.Point2.Y*scale.Y)
+		shape.Radius *= across
+		return WithRotation(shape, deg)
+	case *Capsule:
+		return WithTransform(*shape, deg, scale)
+	case Rectangle:
+		shape.Left, shape.Width = scaleRange(shape.Left, shape.Width, scale.X)
+		shape.Top, shape.Height = scaleRange(shape.Top, shape.Height, scale.Y)
+		return WithRotation(shape, deg)
+	case *Rectangle:
+		return WithTransform(*shape, deg, scale)
+	case Polygon:
+		return shape.Transformed(deg, scale)
+	case *Polygon:
+		return shape.Transformed(deg, scale)
+	}
+	panic(fmt.Sprintf("unsupported shape %T", shape))
+}
+
+// scaleRange scales a range starting at min with a length, the length stays positive when k is negative.
+func scaleRange(min float64, length float64, k float64) (float64, float64) {
+	if k < 0 {
+		return (min + length) * k, -length * k
+	}
+	return min * k, length * k
+}
@@ -1,6 +1,7 @@
 package physics
 
 import (
+	"math"
 	"testing"
 
 	"galaxyzeta.io/engine/infra/require"
@@ -91,3 +92,207 @@ func TestCircle2Polygon(t *testing.T) {
 	poly := circle.ToPolygon()
 	t.Log(poly)
 }
+
+func TestCircleToPolygonWithAnchor(t *testing.T) {
+	circle := NewCircle(linalg.NewVector2f64Ptr(10, 10), 2, 2, 1)
+	bb := circle.ToPolygon().GetBoundingBox()
+	require.EqBool(bb.GetTopLeftPoint().X > 11.9 && bb.GetTopLeftPoint().X < 12.1, true)
+	require.EqBool(bb.GetBottomRightPoint().Y > 13.9 && bb.GetBottomRightPoint().Y < 14.1, true)
+}
+
+func TestCircleIntersection(t *testing.T) {
+	c0 := NewCircle(linalg.NewVector2f64Ptr(0, 0), -1, -1, 1)
+	c1 := NewCircle(linalg.NewVector2f64Ptr(1.5, 0), -1, -1, 1)
+	c2 := NewCircle(linalg.NewVector2f64Ptr(3, 0), -1, -1, 1)
+	require.EqBool(c0.Intersect(c1), true)
+	require.EqBool(c0.Intersect(c2), false)
+	require.EqBool(c1.Intersect(c2), true)
+
+	rect := NewAnchoredRectangle(linalg.NewVector2f64Ptr(0, 0), 1.5, -1, 2, 2)
+	require.EqBool(c0.Intersect(rect), false)
+	require.EqBool(c1.Intersect(rect), true)
+	require.EqBool(rect.Intersect(c1), true)
+
+	// the circle is near the corner of the rectangle but outside of it
+	corner := NewCircle(linalg.NewVector2f64Ptr(0, 0), 3.4, 0.9, 0.5)
+	require.EqBool(corner.Intersect(rect), false)
+	require.EqBool(corner.Shift(-0.3, 0).Intersect(rect), true)
+}
+
+func TestRectangleIntersection(t *testing.T) {
+	rect := NewRectangle(0, 0, 2, 2)
+	require.EqBool(rect.Intersect(NewRectangle(1, 1, 2, 2)), true)
+	require.EqBool(rect.Intersect(NewRectangle(2, 0, 2, 2)), true) // touching edges
+	another := NewRectangle(0, 2, 2, 2)
+	require.EqBool(rect.Intersect(&another), true)
+	require.EqBool(rect.Intersect(NewRectangle(2.1, 0, 2, 2)), false)
+	// anchored rectangles are compared in world space
+	anchored := NewAnchoredRectangle(linalg.NewVector2f64Ptr(3, 0), -1, 0, 2, 2)
+	require.EqBool(rect.Intersect(anchored), true)
+	require.EqBool(rect.Intersect(anchored.Shift(0.5, 0)), false)
+}
+
+func TestTouchingIntersection(t *testing.T) {
+	// every shape touches the right edge of the rectangle, or the right of the circle at (1, 1).
+	rect := NewRectangle(0, 0, 2, 2)
+	circle := NewCircle(nil, 0, 0, 1)
+	square := NewStaticPolygon(linalg.NewVector2f64(0, 0), 0, []linalg.Vector2f64{
+		linalg.NewVector2f64(2, 0),
+		linalg.NewVector2f64(4, 0),
+		linalg.NewVector2f64(4, 2),
+		linalg.NewVector2f64(2, 2),
+	})
+	pairs := map[string][2]IShape{
+		"rectangle-rectangle": {rect, NewRectangle(2, 0, 2, 2)},
+		"rectangle-polygon":   {rect, square},
+		"rectangle-circle":    {rect, NewCircle(nil, 2, 0, 1)},
+		"rectangle-capsule":   {rect, NewVerticalCapsule(nil, 2, -1, 2, 4)},
+		"polygon-polygon":     {square.Shift(-2, 0), square},
+		"circle-circle":       {circle, NewCircle(nil, 2, 0, 1)},
+		"circle-capsule":      {circle, NewVerticalCapsule(nil, 2, -1, 2, 4)},
+		"circle-polygon":      {circle, square},
+	}
+	for name, pair := range pairs {
+		t.Log(name)
+		a, b := pair[0], pair[1]
+		require.EqBool(a.Intersect(b), true)
+		require.EqBool(b.Intersect(a), true)
+		require.EqBool(a.Intersect(b.Shift(0.01, 0)), false)
+		require.EqBool(b.Shift(0.01, 0).Intersect(a), false)
+	}
+}
+
+func TestCapsuleIntersection(t *testing.T) {
+	capsule := NewVerticalCapsule(linalg.NewVector2f64Ptr(0, 0), -1, -4, 2, 8)
+	require.EqBool(capsule.GetBoundingBox().GetHeight() == 8, true)
+
+	circle := NewCircle(nil, 0.5, 2, 1)
+	require.EqBool(capsule.Intersect(circle), true)
+	require.EqBool(capsule.Intersect(circle.Shift(0.6, 0)), false)
+
+	rect := NewRectangle(-3, -5, 6, 0.5)
+	require.EqBool(capsule.Intersect(rect), false)
+	require.EqBool(capsule.Intersect(rect.Shift(0, 0.6)), true)
+
+	another := NewCapsule(nil, linalg.NewVector2f64(-5, 0), linalg.NewVector2f64(5, 0), 0.5)
+	require.EqBool(capsule.Intersect(another), true)
+	require.EqBool(another.Intersect(capsule.Shift(0, 10)), false)
+}
+
+func TestMixedIntersection(t *testing.T) {
+	poly := NewPolygon(linalg.NewVector2f64Ptr(0, 0), linalg.NewVector2f64(0, 0), 0, []linalg.Vector2f64{
+		linalg.NewVector2f64(0, -2),
+		linalg.NewVector2f64(2, 0),
+		linalg.NewVector2f64(0, 2),
+		linalg.NewVector2f64(-2, 0),
+	})
+	rect := NewRectangle(1.2, 1.2, 1, 1)
+	require.EqBool(poly.Intersect(rect), false)
+	require.EqBool(rect.Intersect(*poly), false)
+	require.EqBool(poly.Intersect(rect.Shift(-0.5, -0.5)), true)
+
+	circle := NewCircle(nil, 1, 1, 0.5)
+	require.EqBool(poly.Intersect(circle), false)
+	require.EqBool(poly.Intersect(circle.Shift(-0.3, -0.3)), true)
+	// a circle fully inside of a polygon
+	require.EqBool(poly.Intersect(NewCircle(nil, -0.1, -0.1, 0.1)), true)
+}
+
+func TestCollideManifold(t *testing.T) {
+	near := func(a float64, b float64) bool {
+		return math.Abs(a-b) < 1e-6
+	}
+	// two boxes overlapped by 1 on x axis
+	a := NewRectangle(0, 0, 4, 4)
+	b := NewRectangle(3, 1, 4, 2)
+	m, ok := Collide(a, b)
+	require.EqBool(ok, true)
+	require.EqBool(near(m.Normal.X, 1) && near(m.Normal.Y, 0), true)
+	require.EqBool(near(m.Depth, 1), true)
+	require.EqInt(len(m.Contacts), 2)
+	m, _ = Collide(b, a)
+	require.EqBool(near(m.Normal.X, -1), true)
+	_, ok = Collide(a, b.Shift(1.5, 0))
+	require.EqBool(ok, false)
+
+	// circle resting on a box
+	circle := NewCircle(nil, 1, -1.5, 1)
+	m, ok = Collide(circle, a)
+	require.EqBool(ok, true)
+	require.EqBool(near(m.Normal.Y, 1) && near(m.Depth, 0.5), true)
+	require.EqInt(len(m.Contacts), 1)
+	m, _ = Collide(a, circle)
+	require.EqBool(near(m.Normal.Y, -1), true)
+
+	// a circle deep inside is pushed out through the nearest face
+	m, ok = Collide(NewCircle(nil, 2.5, 1, 0.5), a)
+	require.EqBool(ok, true)
+	require.EqBool(near(m.Normal.X, -1) && near(m.Depth, 1.5), true)
+
+	c1 := NewCircle(nil, 0, 0, 1)
+	c2 := NewCircle(nil, 1.5, 0, 1)
+	m, ok = Collide(c1, c2)
+	require.EqBool(ok, true)
+	require.EqBool(near(m.Normal.X, 1) && near(m.Depth, 0.5), true)
+
+	capsule := NewCapsule(nil, linalg.NewVector2f64(-5, 0), linalg.NewVector2f64(5, 0), 1)
+	m, ok = Collide(capsule, NewCircle(nil, -0.5, 0.5, 0.5))
+	require.EqBool(ok, true)
+	require.EqBool(near(m.Normal.Y, 1) && near(m.Depth, 0.5), true)
+
+	// rotated box
+	rotated := WithRotation(NewAnchoredRectangle(linalg.NewVector2f64Ptr(0, 0), -1, -1, 2, 2), 45)
+	_, ok = Collide(rotated, NewRectangle(1.3, -1, 1, 2))
+	require.EqBool(ok, true)
+	_, ok = Collide(rotated, NewRectangle(1.5, -1, 1, 2))
+	require.EqBool(ok, false)
+}
+
+func TestMomentOfInertia(t *testing.T) {
+	near := func(a float64, b float64) bool {
+		return math.Abs(a-b) < 1e-6
+	}
+	require.EqBool(near(MomentOfInertia(NewCircle(nil, -1, -1, 1), 2), 1), true)
+	// inertia is taken about the centroid, wherever the anchor is.
+	require.EqBool(near(MomentOfInertia(NewCircle(nil, 0, -1, 1), 2), 1), true)
+	box := NewAnchoredRectangle(linalg.NewVector2f64Ptr(0, 0), -1, -2, 2, 4)
+	require.EqBool(near(MomentOfInertia(box, 3), 5), true)
+	require.EqBool(near(MomentOfInertia(box.ToPolygon(), 3), 5), true)
+	corner := NewAnchoredRectangle(linalg.NewVector2f64Ptr(0, 0), 0, 0, 2, 4)
+	require.EqBool(near(MomentOfInertia(corner, 3), 5), true)
+	require.EqBool(near(MomentOfInertia(corner.ToPolygon(), 3), 5), true)
+}
+
+func TestCentroid(t *testing.T) {
+	anchor := linalg.NewVector2f64(10, 10)
+	corner := NewAnchoredRectangle(&anchor, 0, 0, 2, 4)
+	require.EqBool(true, Centroid(corner) == linalg.NewVector2f64(11, 12))
+	require.EqBool(true, Centroid(corner.ToPolygon()) == linalg.NewVector2f64(11, 12))
+	require.EqBool(true, Centroid(NewCircle(&anchor, -5, -5, 5)) == anchor)
+	capsule := NewCapsule(&anchor, linalg.NewVector2f64(0, 0), linalg.NewVector2f64(0, 8), 2)
+	require.EqBool(true, Centroid(capsule) == linalg.NewVector2f64(10, 14))
+}
+
+func TestWithTransform(t *testing.T) {
+	anchor := linalg.NewVector2f64(100, 100)
+	// a 10*10 box centered on the anchor, scaled by 2 and flipped on X.
+	rect := NewAnchoredRectangle(&anchor, -5, -5, 10, 10)
+	bb := WithTransform(rect, 0, linalg.NewVector2f64(-2, 2)).GetBoundingBox()
+	t.Log(bb)
+	require.EqBool(true, bb.GetTopLeftPoint() == linalg.NewVector2f64(90, 90))
+	require.EqBool(true, bb.GetBottomRightPoint() == linalg.NewVector2f64(110, 110))
+	// a 20*4 bar rotated by 90 degrees stands upright.
+	bar := NewAnchoredRectangle(&anchor, -10, -2, 20, 4)
+	bb = WithTransform(bar, 90, linalg.NewVector2f64(1, 1)).GetBoundingBox()
+	t.Log(bb)
+	require.EqBool(true, math.Abs(bb.ToRectangle().Width-4) < 1e-9 && math.Abs(bb.ToRectangle().Height-20) < 1e-9)
+	// uneven scale converts circles into polygons, even scale keeps them.
+	circle := NewCircle(&anchor, -5, -5, 5)
+	_, isPoly := WithTransform(circle, 0, linalg.NewVector2f64(2, 1)).(Polygon)
+	require.EqBool(true, isPoly)
+	scaled := WithTransform(circle, 0, linalg.NewVector2f64(3, 3)).(Circle)
+	require.EqBool(true, scaled.Radius == 15 && scaled.GetWorldCenter() == anchor)
+	// flipped polygons still collide.
+	poly := rect.ToPolygon().Transformed(0, linalg.NewVector2f64(-1, 1))
+	require.EqBool(true, poly.Intersect(NewRectangle(95, 95, 2, 2)))
+}
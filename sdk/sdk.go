@@ -1,8 +1,12 @@
 package sdk
 
 import (
+	"time"
+
 	"galaxyzeta.io/engine/base"
 	"galaxyzeta.io/engine/core"
+	"galaxyzeta.io/engine/ecs/component"
+	"galaxyzeta.io/engine/ecs/system"
 	"galaxyzeta.io/engine/graphics"
 	"galaxyzeta.io/engine/linalg"
 )
@@ -62,16 +66,22 @@ func CreateInactive(constructor func() base.IGameObject2D) base.IGameObject2D {
 
 // Destroy will deconstruct an active/inactive object immediately.
 // The object will be truely removed from resource pool in the next physical tick.
+// Children of the object are detached and kept in place.
 func Destroy(iobj base.IGameObject2D) {
 	core.Destroy(iobj)
 }
 
-// Activate an object from deactive list, if it exists in it.
+// DestroyWithChildren destroys the object and all its descendants in the transform hierarchy.
+func DestroyWithChildren(iobj base.IGameObject2D) {
+	core.DestroyWithChildren(iobj)
+}
+
+// Activate an object from deactive list, if it exists in it. Its descendants are activated as well.
 func Activate(iobj base.IGameObject2D) bool {
 	return core.Activate(iobj)
 }
 
-// Deactivate an object from active list, if it exists in it.
+// Deactivate an object from active list, if it exists in it. Its descendants are deactivated as well.
 func Deactivate(iobj base.IGameObject2D) bool {
 	return core.Deactivate(iobj)
 }
@@ -79,3 +89,91 @@ func Deactivate(iobj base.IGameObject2D) bool {
 func GetCamera() *graphics.Camera {
 	return graphics.GetCurrentCamera()
 }
+
+// CursorWorldPos returns the position of the cursor in world space, seen by the camera drawn under the cursor,
+// or by the current camera if the cursor is out of all viewports.
+func CursorWorldPos() linalg.Vector2f64 {
+	x, y := core.GetCursorPos()
+	p := linalg.NewVector2f64(x, y)
+	cam := graphics.CameraAt(p)
+	if cam == nil {
+		cam = graphics.GetCurrentCamera()
+	}
+	return cam.ScreenToWorld(p)
+}
+
+// GetCameraController returns the controller of the current camera.
+func GetCameraController() *graphics.CameraController {
+	return graphics.GetCurrentCamera().Controller()
+}
+
+// FollowObject makes the current camera follow an object, which is kept at the center of the view.
+func FollowObject(iobj base.IGameObject2D) {
+	GetCameraController().Follow(objectPos(iobj))
+}
+
+// TransitionCameraTo moves the current camera to an object in duration, easing in and out, and then follows it.
+func TransitionCameraTo(iobj base.IGameObject2D, duration time.Duration) {
+	GetCameraController().TransitionTo(objectPos(iobj), duration)
+}
+
+// ShakeCamera adds trauma to the current camera, trauma of 1 shakes the hardest.
+func ShakeCamera(trauma float64) {
+	GetCameraController().AddTrauma(trauma)
+}
+
+func objectPos(iobj base.IGameObject2D) func() linalg.Vector2f64 {
+	tf := iobj.Obj().GetComponent(component.NameTransform2D).(*component.Transform2D)
+	return func() linalg.Vector2f64 {
+		return tf.Pos
+	}
+}
+
+// +------------------------+
+// |	  Post Processing	 	|
+// +------------------------+
+
+// GetPostProcessor returns the post-processing chain of the current camera, effects could be added or removed at runtime.
+func GetPostProcessor() *graphics.PostProcessor {
+	return graphics.GetCurrentCamera().PostProcessor()
+}
+
+// SetPostEffectEnabled turns an effect of the current camera on or off. Will panic if it is not found.
+func SetPostEffectEnabled(name string, enabled bool) {
+	GetPostProcessor().SetEnabled(name, enabled)
+}
+
+// IsPostEffectEnabled tells whether an effect of the current camera is on, false if it is not found.
+func IsPostEffectEnabled(name string) bool {
+	effect := GetPostProcessor().Get(name)
+	return effect != nil && effect.IsEnabled()
+}
+
+// FlashScreen covers the screen of the current camera with color, which fades out in duration.
+// A flash effect is appended to the end of the chain if the chain does not have one.
+func FlashScreen(color linalg.RgbaF64, duration time.Duration) {
+	processor := GetPostProcessor()
+	for _, effect := range processor.Effects() {
+		if flash, ok := effect.(*graphics.FlashEffect); ok {
+			flash.Flash(color, duration)
+			return
+		}
+	}
+	flash := graphics.NewFlashEffect("flash")
+	processor.Add(flash)
+	flash.Flash(color, duration)
+}
+
+// +------------------------+
+// |	  	  Debug	 	 	|
+// +------------------------+
+
+// SetPhysicsDebugDraw turns on or off outlines of physical bodies. Sleeping bodies are drawn in gray.
+func SetPhysicsDebugDraw(enabled bool) {
+	core.GetSystem(system.NamePhysicsDebug2DSystem).(*system.PhysicsDebug2DSystem).SetEnabled(enabled)
+}
+
+// GetRenderStats returns draw calls, vertices and quads of the last frame drawn by the 2D renderer.
+func GetRenderStats() graphics.RenderStats {
+	return core.GetSystem(system.NameRenderer2DSystem).(*system.Renderer2DSystem).GetRenderStats()
+}